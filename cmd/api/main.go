@@ -4,58 +4,64 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"io/ioutil"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
-	"path/filepath"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/1way-market/v3/internal/config"
 	"github.com/1way-market/v3/internal/database"
+	grpcdelivery "github.com/1way-market/v3/internal/delivery/grpc"
 	"github.com/1way-market/v3/internal/delivery/http/router"
+	"github.com/1way-market/v3/internal/domain"
 	"github.com/1way-market/v3/internal/repository"
 	"github.com/1way-market/v3/internal/usecase"
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis/v8"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+	"google.golang.org/grpc"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func initDatabase(cfg *config.Config) (*gorm.DB, error) {
-	// First, try to connect to PostgreSQL server
+// ensureDatabaseExists checks that the database can be reached and, with
+// AutoCreateDB, creates it when it cannot. The connections it opens are closed
+// before it returns.
+func ensureDatabaseExists(cfg *config.Config) error {
 	sqlDB, err := sql.Open("postgres", cfg.DatabaseURL)
 	if err != nil {
-		return nil, fmt.Errorf("error connecting to PostgreSQL: %v", err)
+		return fmt.Errorf("error connecting to PostgreSQL: %v", err)
 	}
-
-	// Try to connect to the specific database
 	err = sqlDB.Ping()
-	if err != nil {
-		// If database doesn't exist, create it
-		sqlDB.Close()
+	sqlDB.Close()
+	if err == nil {
+		return nil
+	}
+	if !cfg.AutoCreateDB {
+		return fmt.Errorf("error connecting to database %s: %v", cfg.DBName, err)
+	}
 
-		// Connect to postgres database to create our database
-		postgresDB, err := sql.Open("postgres", strings.Replace(cfg.DatabaseURL, cfg.DBName, "postgres", 1))
-		if err != nil {
-			return nil, fmt.Errorf("error connecting to postgres database: %v", err)
-		}
-		defer postgresDB.Close()
+	// Connect to postgres database to create our database
+	postgresDB, err := sql.Open("postgres", cfg.DB.AdminDSN())
+	if err != nil {
+		return fmt.Errorf("error connecting to postgres database: %v", err)
+	}
+	defer postgresDB.Close()
 
-		_, err = postgresDB.Exec("CREATE DATABASE " + cfg.DBName)
-		if err != nil && !strings.Contains(err.Error(), "already exists") {
-			return nil, fmt.Errorf("error creating database: %v", err)
-		}
+	_, err = postgresDB.Exec("CREATE DATABASE " + pq.QuoteIdentifier(cfg.DBName))
+	if err != nil && !strings.Contains(err.Error(), "already exists") {
+		return fmt.Errorf("error creating database: %v", err)
+	}
+	return nil
+}
 
-		// Reconnect to the newly created database
-		sqlDB, err = sql.Open("postgres", cfg.DatabaseURL)
-		if err != nil {
-			return nil, fmt.Errorf("error connecting to new database: %v", err)
-		}
+func initDatabase(cfg *config.Config) (*gorm.DB, error) {
+	if err := ensureDatabaseExists(cfg); err != nil {
+		return nil, err
 	}
 
 	// Initialize GORM
@@ -64,31 +70,47 @@ func initDatabase(cfg *config.Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("error initializing GORM: %v", err)
 	}
 
-	// Validate schema
-	if err := database.ValidateSchema(sqlDB); err != nil {
-		// If tables don't exist, run migrations
-		if strings.Contains(err.Error(), "does not exist") {
-			log.Printf("Database schema not found, running migrations...")
-			migrationSQL, err := ioutil.ReadFile(filepath.Join("migrations", "001_initial_schema.sql"))
-			if err != nil {
-				return nil, fmt.Errorf("error reading migration file: %v", err)
-			}
+	// Tune the connection pool GORM queries through; schema checks and
+	// migrations use it too
+	pool, err := gormDB.DB()
+	if err != nil {
+		return nil, fmt.Errorf("error getting database connection pool: %v", err)
+	}
+	pool.SetMaxOpenConns(cfg.DB.MaxOpenConns)
+	pool.SetMaxIdleConns(cfg.DB.MaxIdleConns)
+	pool.SetConnMaxLifetime(cfg.DB.ConnMaxLifetime)
 
-			if err := gormDB.Exec(string(migrationSQL)).Error; err != nil {
-				return nil, fmt.Errorf("error running migrations: %v", err)
-			}
+	if err := prepareSchema(pool, cfg.MigrationsDir); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return gormDB, nil
+}
 
-			// Validate schema again after migration
-			if err := database.ValidateSchema(sqlDB); err != nil {
-				return nil, fmt.Errorf("schema validation failed after migration: %v", err)
-			}
-		} else {
-			// If schema validation failed for other reasons, return the error
-			return nil, fmt.Errorf("schema validation failed: %v", err)
-		}
+// prepareSchema validates the schema, running the migrations when its tables
+// do not exist yet
+func prepareSchema(db *sql.DB, migrationsDir string) error {
+	err := database.ValidateSchema(db, false)
+	if err == nil {
+		return nil
+	}
+	// If schema validation failed for other reasons, return the error
+	if !strings.Contains(err.Error(), "does not exist") {
+		return fmt.Errorf("schema validation failed: %v", err)
 	}
 
-	return gormDB, nil
+	log.Printf("Database schema not found, running migrations...")
+	applied, err := database.Migrate(db, migrationsDir)
+	if err != nil {
+		return fmt.Errorf("error running migrations: %v", err)
+	}
+	log.Printf("Applied migration versions: %v", applied)
+
+	// Validate schema again after migration
+	if err := database.ValidateSchema(db, false); err != nil {
+		return fmt.Errorf("schema validation failed after migration: %v", err)
+	}
+	return nil
 }
 
 func initRedis(cfg *config.Config) (*redis.Client, error) {
@@ -105,9 +127,29 @@ func initRedis(cfg *config.Config) (*redis.Client, error) {
 	return client, nil
 }
 
+// stopGRPC stops the gRPC server, letting running calls finish until ctx is
+// done and cancelling them after
+func stopGRPC(ctx context.Context, server *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		server.Stop()
+		<-stopped
+	}
+}
+
 func main() {
 	// Initialize configuration
 	cfg := config.New()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	domain.DefaultFallbackChain = cfg.LangFallbackChain
 
 	// Initialize database
 	db, err := initDatabase(cfg)
@@ -124,12 +166,59 @@ func main() {
 	// Initialize repositories
 	repos := repository.NewRepositories(db)
 
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalf("Failed to get database connection: %v", err)
+	}
+
 	// Initialize use cases
-	useCases := usecase.NewUseCases(repos, redisClient)
+	useCases := usecase.NewUseCases(repos, sqlDB, redisClient, cfg)
+
+	// Warm up the category ad counts cache
+	if redisClient != nil {
+		if err := useCases.CategoryUseCase.WarmUpCounts(context.Background()); err != nil {
+			log.Printf("Warning: Failed to warm up category counts: %v", err)
+		}
+	}
+
+	// Apply ad changes made through other instances and flush view counts until shutdown
+	listenCtx, stopListening := context.WithCancel(context.Background())
+	defer stopListening()
+	flushed := make(chan struct{})
+	if redisClient != nil {
+		go useCases.AdUseCase.ListenInvalidations(listenCtx)
+		go func() {
+			useCases.ViewCountFlusher.Run(listenCtx)
+			close(flushed)
+		}()
+	} else {
+		close(flushed)
+	}
+
+	// Publish the ad events recorded in the outbox
+	relayStopped := make(chan struct{})
+	if useCases.OutboxRelay != nil {
+		go func() {
+			useCases.OutboxRelay.Run(listenCtx)
+			close(relayStopped)
+		}()
+	} else {
+		close(relayStopped)
+	}
+
+	// Activate approved ads once their activation delay has passed
+	if useCases.ActivationWorker != nil {
+		go useCases.ActivationWorker.Run(listenCtx)
+	}
+
+	// Complete active ads once they expire
+	if useCases.ExpirationWorker != nil {
+		go useCases.ExpirationWorker.Run(listenCtx)
+	}
 
 	// Initialize Gin router
 	gin.SetMode(gin.ReleaseMode)
-	r := router.Setup(useCases)
+	r := router.Setup(cfg, useCases)
 
 	// Create HTTP server
 	srv := &http.Server{
@@ -146,16 +235,46 @@ func main() {
 
 	log.Printf("Server is running on %s", cfg.ServerAddress)
 
+	// Serve the gRPC API for internal consumers on its own port
+	var grpcServer *grpc.Server
+	if cfg.GRPCAddress != "" {
+		listener, err := net.Listen("tcp", cfg.GRPCAddress)
+		if err != nil {
+			log.Fatalf("Failed to listen for gRPC on %s: %v", cfg.GRPCAddress, err)
+		}
+		grpcServer = grpcdelivery.NewServer(cfg, useCases.AdUseCase)
+		go func() {
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatalf("Failed to start gRPC server: %v", err)
+			}
+		}()
+		log.Printf("gRPC server is running on %s", cfg.GRPCAddress)
+	}
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
+	stopListening()
 
-	// Shutdown server
+	// Shutdown servers, both within the same timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	grpcStopped := make(chan struct{})
+	go func() {
+		if grpcServer != nil {
+			stopGRPC(ctx, grpcServer)
+		}
+		close(grpcStopped)
+	}()
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
+	<-grpcStopped
+
+	// Wait for the final flush of the view counts and for the event
+	// publisher to close
+	<-flushed
+	<-relayStopped
 }
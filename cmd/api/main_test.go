@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/1way-market/v3/internal/config"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// unreachableConfig points at a local port nothing listens on
+func unreachableConfig(t *testing.T) *config.Config {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+	return &config.Config{
+		DatabaseURL: "postgres://ads:ads@" + addr + "/ads?sslmode=disable&connect_timeout=1",
+		DBName:      "ads",
+	}
+}
+
+func TestEnsureDatabaseExistsClosesConnections(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	if err := ensureDatabaseExists(unreachableConfig(t)); err == nil {
+		t.Fatal("expected an error for an unreachable database")
+	}
+}
+
+// TestInitDatabaseClosesConnections needs a PostgreSQL database at
+// TEST_DATABASE_URL and is skipped without one
+func TestInitDatabaseClosesConnections(t *testing.T) {
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	defer goleak.VerifyNone(t)
+
+	cfg := &config.Config{DatabaseURL: databaseURL, MigrationsDir: "../../migrations"}
+	cfg.DB.MaxOpenConns = 2
+	db, err := initDatabase(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if open := pool.Stats().OpenConnections; open > cfg.DB.MaxOpenConns {
+		t.Errorf("%d connections open, want at most %d", open, cfg.DB.MaxOpenConns)
+	}
+	pool.Close()
+}
+
+func TestStopGRPCCancelsCallsAfterTimeout(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every call blocks until it is cancelled
+	started := make(chan struct{})
+	server := grpc.NewServer(grpc.UnknownServiceHandler(func(_ interface{}, stream grpc.ServerStream) error {
+		close(started)
+		<-stream.Context().Done()
+		return stream.Context().Err()
+	}))
+	go server.Serve(listener)
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	called := make(chan error, 1)
+	go func() {
+		called <- conn.Invoke(context.Background(), "/test.Blocking/Call", &emptypb.Empty{}, &emptypb.Empty{})
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	stopGRPC(ctx, server)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("stopping took %v", elapsed)
+	}
+	if err := <-called; status.Code(err) == codes.OK {
+		t.Error("blocked call succeeded, want it cancelled")
+	}
+}
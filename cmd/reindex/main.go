@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"math"
+
+	"github.com/1way-market/v3/internal/config"
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/1way-market/v3/internal/repository"
+	"github.com/1way-market/v3/internal/search"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Reindex copies every ad from the database into the OpenSearch index,
+// creating the index first if needed. Ads already indexed are replaced.
+func main() {
+	batchSize := flag.Int("batch", 500, "number of ads sent per bulk request")
+	flag.Parse()
+
+	cfg := config.New()
+	db, err := gorm.Open(postgres.Open(cfg.DatabaseURL), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	ctx := context.Background()
+	index := search.NewOpenSearch(cfg.OpenSearchURL, cfg.OpenSearchIndex)
+	if err := index.EnsureIndex(ctx); err != nil {
+		log.Fatalf("Failed to create the search index: %v", err)
+	}
+
+	var batch []domain.Ad
+	indexed := 0
+	flush := func() error {
+		if err := index.IndexAds(ctx, batch); err != nil {
+			return err
+		}
+		indexed += len(batch)
+		batch = batch[:0]
+		log.Printf("Indexed %d ads", indexed)
+		return nil
+	}
+
+	err = repository.NewAdRepository(db).Export(ctx, domain.FilterRequest{}, math.MaxInt32, func(ad *domain.Ad) error {
+		batch = append(batch, *ad)
+		if len(batch) < *batchSize {
+			return nil
+		}
+		return flush()
+	})
+	if err == nil && len(batch) > 0 {
+		err = flush()
+	}
+	if err != nil {
+		log.Fatalf("Reindex failed after %d ads: %v", indexed, err)
+	}
+	log.Printf("Reindex completed, %d ads indexed into %s", indexed, cfg.OpenSearchIndex)
+}
@@ -0,0 +1,161 @@
+// Package authtest issues tokens for tests, signed with a local key published
+// through a JWKS server, as the identity service does.
+package authtest
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/1way-market/v3/internal/auth"
+)
+
+const (
+	// Issuer and Audience are the iss and aud claims of issued tokens
+	Issuer   = "https://id.test"
+	Audience = "ads-api"
+)
+
+// Identity signs tokens and publishes its keys at a JWKS URL
+type Identity struct {
+	server *httptest.Server
+
+	mu      sync.Mutex
+	keys    map[string]*rsa.PrivateKey
+	kid     string
+	fetches int
+}
+
+// New starts an identity service with one signing key, stopped with the test
+func New(t testing.TB) *Identity {
+	t.Helper()
+	id := &Identity{keys: make(map[string]*rsa.PrivateKey)}
+	id.Rotate(t, "key-1")
+	id.server = httptest.NewServer(http.HandlerFunc(id.serveJWKS))
+	t.Cleanup(id.server.Close)
+	return id
+}
+
+// URL returns the JWKS URL
+func (id *Identity) URL() string {
+	return id.server.URL
+}
+
+// Verifier returns a verifier for the identity's tokens
+func (id *Identity) Verifier() *auth.Verifier {
+	return auth.NewVerifier(auth.NewKeySet(id.URL()), Issuer, Audience)
+}
+
+// Fetches returns how many times the JWKS was fetched
+func (id *Identity) Fetches() int {
+	id.mu.Lock()
+	defer id.mu.Unlock()
+	return id.fetches
+}
+
+// Rotate adds a signing key with the given ID, which signs later tokens
+func (id *Identity) Rotate(t testing.TB, kid string) {
+	t.Helper()
+	// 1024 bits keeps the tests fast; the key size does not matter to them
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id.mu.Lock()
+	defer id.mu.Unlock()
+	id.keys[kid] = key
+	id.kid = kid
+}
+
+// Token returns a token for the user with the given roles, valid for an hour
+func (id *Identity) Token(t testing.TB, subject string, roles ...string) string {
+	t.Helper()
+	return id.Sign(t, Claims(subject, roles...))
+}
+
+// Claims returns the claims of a token for the user with the given roles,
+// valid for an hour
+func Claims(subject string, roles ...string) map[string]interface{} {
+	return map[string]interface{}{
+		"sub":   subject,
+		"roles": roles,
+		"iss":   Issuer,
+		"aud":   Audience,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+// Sign returns an RS256 token with the given claims, signed with the current key
+func (id *Identity) Sign(t testing.TB, claims map[string]interface{}) string {
+	t.Helper()
+	id.mu.Lock()
+	kid, key := id.kid, id.keys[id.kid]
+	id.mu.Unlock()
+
+	signingInput := segment(t, map[string]string{"alg": "RS256", "typ": "JWT", "kid": kid}) + "." + segment(t, claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// Tamper returns the token with its claims replaced and its signature kept
+func Tamper(t testing.TB, token string, claims map[string]interface{}) string {
+	t.Helper()
+	parts := strings.Split(token, ".")
+	parts[1] = segment(t, claims)
+	return strings.Join(parts, ".")
+}
+
+// segment encodes v as a base64url JSON token segment
+func segment(t testing.TB, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// serveJWKS publishes the public signing keys
+func (id *Identity) serveJWKS(w http.ResponseWriter, r *http.Request) {
+	id.mu.Lock()
+	defer id.mu.Unlock()
+	id.fetches++
+
+	type jwk struct {
+		Kid string `json:"kid"`
+		Kty string `json:"kty"`
+		Use string `json:"use"`
+		Alg string `json:"alg"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	}
+	var document struct {
+		Keys []jwk `json:"keys"`
+	}
+	for kid, key := range id.keys {
+		document.Keys = append(document.Keys, jwk{
+			Kid: kid,
+			Kty: "RSA",
+			Use: "sig",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(document)
+}
@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// jwksMaxAge is how long fetched keys are used before they are refetched
+	jwksMaxAge = time.Hour
+	// jwksMinRefreshInterval limits refetches triggered by unknown key IDs,
+	// so that tokens with made-up kids cannot hammer the identity service
+	jwksMinRefreshInterval = time.Minute
+	// jwksFetchTimeout bounds a single JWKS request
+	jwksFetchTimeout = 5 * time.Second
+)
+
+// KeySet caches the RSA signing keys published at a JWKS URL
+type KeySet struct {
+	url    string
+	client *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// NewKeySet returns a key set fetching keys from the JWKS URL on first use
+func NewKeySet(url string) *KeySet {
+	return &KeySet{
+		url:    url,
+		client: &http.Client{Timeout: jwksFetchTimeout},
+	}
+}
+
+// Key returns the key with the given ID. Keys are refetched when they are
+// older than jwksMaxAge or the ID is unknown, e.g. after a key rotation.
+func (s *KeySet) Key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, known := s.keys[kid]
+	sinceFetch := time.Since(s.fetchedAt)
+	if (known && sinceFetch < jwksMaxAge) || (!known && sinceFetch < jwksMinRefreshInterval) {
+		if !known {
+			return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, kid)
+		}
+		return key, nil
+	}
+
+	keys, err := s.fetch(ctx)
+	if err != nil {
+		// Keep serving known keys while the identity service is unavailable
+		if known {
+			return key, nil
+		}
+		return nil, err
+	}
+	s.keys = keys
+	s.fetchedAt = time.Now()
+
+	if key, known = s.keys[kid]; !known {
+		return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, kid)
+	}
+	return key, nil
+}
+
+// jwk is a JSON Web Key as published in a JWKS document
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// fetch downloads the JWKS document and returns its RSA signing keys by ID
+func (s *KeySet) fetch(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating JWKS request: %v", err)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching JWKS: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching JWKS: unexpected status %d", resp.StatusCode)
+	}
+
+	var document struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&document); err != nil {
+		return nil, fmt.Errorf("error decoding JWKS: %v", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range document.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") || (k.Alg != "" && k.Alg != "RS256") {
+			continue
+		}
+		key, err := rsaPublicKey(k.N, k.E)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding JWKS key %q: %v", k.Kid, err)
+		}
+		keys[k.Kid] = key
+	}
+	return keys, nil
+}
+
+// rsaPublicKey builds a public key from the base64url-encoded modulus and exponent
+func rsaPublicKey(n, e string) (*rsa.PublicKey, error) {
+	modulus, err := base64.RawURLEncoding.DecodeString(n)
+	if err != nil {
+		return nil, err
+	}
+	exponent, err := base64.RawURLEncoding.DecodeString(e)
+	if err != nil {
+		return nil, err
+	}
+	if len(exponent) == 0 || len(exponent) > 4 {
+		return nil, fmt.Errorf("invalid exponent")
+	}
+
+	var exp int
+	for _, b := range exponent {
+		exp = exp<<8 | int(b)
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(modulus), E: exp}, nil
+}
@@ -0,0 +1,87 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1way-market/v3/internal/auth"
+	"github.com/1way-market/v3/internal/auth/authtest"
+)
+
+func TestVerify(t *testing.T) {
+	identity := authtest.New(t)
+	verifier := identity.Verifier()
+
+	claims, err := verifier.Verify(context.Background(), identity.Token(t, "user-1", "seller", "parser"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Subject != "user-1" || !slices.Equal(claims.Roles, []string{"seller", "parser"}) {
+		t.Errorf("claims = %+v, want user-1 with seller and parser", claims)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	identity := authtest.New(t)
+	other := authtest.New(t)
+	valid := identity.Token(t, "user-1", "seller")
+
+	with := func(key string, value interface{}) map[string]interface{} {
+		claims := authtest.Claims("user-1", "seller")
+		claims[key] = value
+		return claims
+	}
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"expired", identity.Sign(t, with("exp", time.Now().Add(-time.Hour).Unix())), auth.ErrExpiredToken},
+		{"without exp", identity.Sign(t, with("exp", nil)), auth.ErrExpiredToken},
+		{"not yet valid", identity.Sign(t, with("nbf", time.Now().Add(time.Hour).Unix())), auth.ErrExpiredToken},
+		{"malformed", "not-a-token", auth.ErrInvalidToken},
+		{"unsigned", strings.Join(strings.Split(valid, ".")[:2], ".") + ".", auth.ErrInvalidToken},
+		{"tampered", authtest.Tamper(t, valid, with("roles", []string{"admin"})), auth.ErrInvalidToken},
+		{"foreign key", other.Token(t, "user-1", "admin"), auth.ErrInvalidToken},
+		{"without sub", identity.Sign(t, with("sub", "")), auth.ErrInvalidToken},
+		{"other issuer", identity.Sign(t, with("iss", "https://evil.example")), auth.ErrInvalidToken},
+		{"other audience", identity.Sign(t, with("aud", []string{"billing"})), auth.ErrInvalidToken},
+	}
+	verifier := identity.Verifier()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := verifier.Verify(context.Background(), tt.token); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeySetCachesKeys(t *testing.T) {
+	identity := authtest.New(t)
+	verifier := identity.Verifier()
+	for i := 0; i < 3; i++ {
+		if _, err := verifier.Verify(context.Background(), identity.Token(t, "user-1")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A rotated key is only looked up again once a refetch is due, so tokens
+	// with made-up key IDs cannot make every request fetch the keys
+	identity.Rotate(t, "key-2")
+	if _, err := verifier.Verify(context.Background(), identity.Token(t, "user-1")); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("error for a key unknown since the last fetch = %v, want %v", err, auth.ErrInvalidToken)
+	}
+	if fetches := identity.Fetches(); fetches != 1 {
+		t.Errorf("fetched the keys %d times, want 1", fetches)
+	}
+
+	// A new key set fetches the rotated key
+	if _, err := identity.Verifier().Verify(context.Background(), identity.Token(t, "user-1")); err != nil {
+		t.Errorf("rotated key rejected: %v", err)
+	}
+}
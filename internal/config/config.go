@@ -1,9 +1,17 @@
 package config
 
 import (
+	"encoding/base64"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/1way-market/v3/internal/domain"
 	"github.com/joho/godotenv"
 )
 
@@ -13,6 +21,222 @@ type Config struct {
 	RedisURL      string
 	Environment   string
 	DBName        string
+	DB            DBConfig
+	MigrationsDir string
+	CORS          CORSConfig
+	JWT           JWTConfig
+	// GRPCAddress is where the gRPC API for internal consumers listens; empty disables it
+	GRPCAddress string
+	// GRPCRequestTimeout bounds gRPC calls that come without a deadline
+	GRPCRequestTimeout time.Duration
+	// AutoCreateDB creates the database on startup when it does not exist
+	AutoCreateDB bool
+	// DebugEndpoints enables the debug endpoints such as the query plan explainer
+	DebugEndpoints bool
+	// AdsCacheSoftTTL is how long a cached ads listing is served as fresh
+	AdsCacheSoftTTL time.Duration
+	// AdsCacheHardTTL is how long a cached ads listing is kept and served stale
+	AdsCacheHardTTL time.Duration
+	// AdCacheTTL is how long a single ad is cached after it is read or written
+	AdCacheTTL time.Duration
+	// AdNotFoundCacheTTL is how long a missing ad ID is remembered
+	AdNotFoundCacheTTL time.Duration
+	// CategoryPathTTL is how long the ancestor path of a category is cached
+	CategoryPathTTL time.Duration
+	// ViewFlushInterval is how often ad views counted in Redis are added to the database
+	ViewFlushInterval time.Duration
+	// ActivationDelay is how long ads stay approved before they are activated; 0 disables activation
+	ActivationDelay time.Duration
+	// ActivationInterval is how often approved ads are checked for activation
+	ActivationInterval time.Duration
+	// ExpirationInterval is how often active ads are checked for expiry
+	ExpirationInterval time.Duration
+	// AdLifetime is how long ads stay active, from activation or renewal, unless given an expiry date
+	AdLifetime time.Duration
+	// SearchBackend answers the text searches of ad listings: postgres or opensearch
+	SearchBackend string
+	// OpenSearchURL is the address of the OpenSearch cluster, with credentials if needed
+	OpenSearchURL string
+	// OpenSearchIndex is the OpenSearch index holding the ads
+	OpenSearchIndex string
+	// EventPublisher is where ad events are relayed from the outbox: redis (a Redis
+	// stream), nats (a JetStream subject), kafka (a topic) or log; empty keeps
+	// them in the outbox
+	EventPublisher string
+	// EventStream is the Redis stream, NATS subject or Kafka topic ad events
+	// are published to
+	EventStream string
+	// NATSURL is the NATS server ad events are published to
+	NATSURL string
+	// KafkaBrokers are the addresses of the Kafka brokers ad events are published to
+	KafkaBrokers []string
+	// OutboxInterval is how often the outbox is polled for events to relay
+	OutboxInterval time.Duration
+	// OutboxRetention is how long relayed events are kept in the outbox
+	OutboxRetention time.Duration
+	// StorageEndpoint is the S3-compatible endpoint ad images are stored at; empty disables images
+	StorageEndpoint string
+	// StorageBucket is the bucket holding the ad images
+	StorageBucket string
+	// StorageRegion is the region requests to the storage are signed for
+	StorageRegion string
+	// StorageAccessKey and StorageSecretKey are the credentials of the storage
+	StorageAccessKey string
+	StorageSecretKey string
+	// StoragePublicURL is where clients fetch the bucket's objects; empty uses the endpoint
+	StoragePublicURL string
+	// MaxImageSize caps uploaded images, in bytes
+	MaxImageSize int64
+	// CacheBypassEnabled lets any caller skip the cache with Cache-Control or refresh=true;
+	// callers with the admin API key always can
+	CacheBypassEnabled bool
+	// CacheCodec is the serialization of cache entries: json or msgpack
+	CacheCodec string
+	// CacheGzipMinSize is the entry size from which cache entries are gzipped; 0 disables it
+	CacheGzipMinSize int
+	// RedisCacheTimeout bounds each cache call before falling back to the database
+	RedisCacheTimeout time.Duration
+	// SiteBaseURL is the public site address used to build links to ads
+	SiteBaseURL string
+	// AdminAPIKey authorizes requests to the admin endpoints; empty disables them
+	AdminAPIKey string
+	// AutoRejectThreshold is the number of reports above which an ad is rejected automatically; 0 disables it
+	AutoRejectThreshold int
+	// DuplicateThreshold is the title similarity, between 0 and 1, from which a
+	// parser import duplicates a recent ad; 0 disables duplicate detection
+	DuplicateThreshold float64
+	// DuplicateWindow is how far back parser imports are checked for duplicates
+	DuplicateWindow time.Duration
+	// ExportMaxRows caps the number of ads in an export
+	ExportMaxRows int
+	// MaxPageSize caps the page size of ad listings
+	MaxPageSize int
+	// MaxRequestBodySize caps request bodies, in bytes; 0 disables the limit
+	MaxRequestBodySize int64
+	// MaxImportSize caps the NDJSON bodies of ad imports, in bytes
+	MaxImportSize int64
+	// MaxImportItems caps the number of ads in an import; 0 disables the limit
+	MaxImportItems int
+	// ImportConcurrency is the number of imports a client may run at a time;
+	// 0 disables the limit
+	ImportConcurrency int
+	// CompressionMinBytes is the response size from which responses are gzipped
+	CompressionMinBytes int
+	// DefaultVisibleStatuses are the statuses listings show to callers other
+	// than moderators, outside their own ads; empty shows every status
+	DefaultVisibleStatuses []domain.AdStatus
+	// LangFallbackChain lists the languages served, in order, when a text is
+	// missing in the requested language
+	LangFallbackChain domain.LangFallbackChain
+	// CategoryCurrencies restricts the price currencies allowed in a category
+	CategoryCurrencies map[int][]domain.Currency
+	// PhoneEncryptionKey is the AES-256 key ad phones are encrypted with;
+	// ads cannot be given a phone without it
+	PhoneEncryptionKey []byte
+
+	// unsetRequired lists the connection settings missing from the environment
+	unsetRequired []string
+}
+
+// Validate reports configuration that is unsafe to run with
+func (c *Config) Validate() error {
+	if c.Environment == "production" && len(c.unsetRequired) > 0 {
+		return fmt.Errorf("missing required environment variables for production: %s",
+			strings.Join(c.unsetRequired, ", "))
+	}
+	if c.CORS.AllowCredentials && slices.Contains(c.CORS.AllowedOrigins, "*") {
+		return fmt.Errorf("CORS_ALLOWED_ORIGINS cannot be * when CORS_ALLOW_CREDENTIALS is set: list the trusted origins")
+	}
+	return nil
+}
+
+// DBConfig holds the PostgreSQL connection settings
+type DBConfig struct {
+	// URL is a complete postgres:// connection URL used instead of the discrete settings
+	URL      string
+	Host     string
+	Port     string
+	User     string
+	Password string
+	Name     string
+	SSLMode  string
+
+	// MaxOpenConns caps the open connections; 0 means unlimited
+	MaxOpenConns int
+	// MaxIdleConns caps the idle connections kept in the pool
+	MaxIdleConns int
+	// ConnMaxLifetime is how long a connection is reused before it is closed
+	ConnMaxLifetime time.Duration
+}
+
+// DSN returns the connection string for the configured database
+func (c DBConfig) DSN() string {
+	if c.URL != "" {
+		return c.URL
+	}
+	return c.dsn(c.Name)
+}
+
+// AdminDSN returns the connection string for the postgres maintenance
+// database, used to create the configured database
+func (c DBConfig) AdminDSN() string {
+	if postgresURLDatabase(c.URL) != "" {
+		u, _ := url.Parse(c.URL)
+		u.Path = "/postgres"
+		return u.String()
+	}
+	return c.dsn("postgres")
+}
+
+// postgresURLDatabase returns the database name of a postgres:// URL,
+// or an empty string for other connection strings
+func postgresURLDatabase(connString string) string {
+	u, err := url.Parse(connString)
+	if err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+		return ""
+	}
+	return strings.TrimPrefix(u.Path, "/")
+}
+
+func (c DBConfig) dsn(dbName string) string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		dsnValue(c.Host), dsnValue(c.Port), dsnValue(c.User), dsnValue(c.Password), dsnValue(dbName), dsnValue(c.SSLMode))
+}
+
+// dsnValue quotes a key/value connection string value when needed
+func dsnValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
+}
+
+// CORSConfig holds the cross-origin settings applied to the HTTP API
+type CORSConfig struct {
+	// AllowedOrigins lists the origins allowed cross-origin requests, "*" for
+	// any; none are allowed by default
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+	// AllowCredentials lets browsers send cookies and authorization headers
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache a preflight response
+	MaxAge time.Duration
+}
+
+// JWTConfig holds the settings for verifying tokens issued by the identity service
+type JWTConfig struct {
+	// JWKSURL publishes the token signing keys; empty disables token authentication
+	JWKSURL string
+	// Issuer and Audience are the required iss and aud claims; empty skips the check
+	Issuer   string
+	Audience string
+	// TrustGatewayHeaders accepts the user ID the API gateway forwards in
+	// X-User-ID from requests without a token. Only enable it behind a gateway
+	// that strips the header from client requests.
+	TrustGatewayHeaders bool
 }
 
 func New() *Config {
@@ -21,31 +245,234 @@ func New() *Config {
 		fmt.Printf("Warning: .env file not found: %v\n", err)
 	}
 
-	dbHost := getEnv("DB_HOST", "localhost")
-	dbPort := getEnv("DB_PORT", "5432")
-	dbUser := getEnv("DB_USER", "postgres")
-	dbPass := getEnv("DB_PASSWORD", "postgres")
-	dbName := getEnv("DB_NAME", "market")
-	dbSSLMode := getEnv("DB_SSLMODE", "disable")
+	// Connection settings must be set explicitly in production rather than fall back
+	// to their localhost defaults, unless a complete URL replaces them, and so
+	// must the phone encryption key.
+	// REDIS_PASSWORD may be empty for a Redis without authentication.
+	var required []string
+	if _, exists := os.LookupEnv("DATABASE_URL"); !exists {
+		required = append(required, "DB_HOST", "DB_PASSWORD")
+	}
+	if _, exists := os.LookupEnv("REDIS_URL"); !exists {
+		required = append(required, "REDIS_HOST", "REDIS_PASSWORD")
+	}
+	required = append(required, "PHONE_ENCRYPTION_KEY")
+	var unsetRequired []string
+	for _, key := range required {
+		if _, exists := os.LookupEnv(key); !exists {
+			unsetRequired = append(unsetRequired, key)
+		}
+	}
+
+	db := DBConfig{
+		Host:     getEnv("DB_HOST", "localhost"),
+		Port:     getEnv("DB_PORT", "5432"),
+		User:     getEnv("DB_USER", "postgres"),
+		Password: getEnv("DB_PASSWORD", "postgres"),
+		Name:     getEnv("DB_NAME", "market"),
+		SSLMode:  getEnv("DB_SSLMODE", "disable"),
+
+		MaxOpenConns:    getEnvInt("DB_MAX_OPEN_CONNS", 25),
+		MaxIdleConns:    getEnvInt("DB_MAX_IDLE_CONNS", 10),
+		ConnMaxLifetime: getEnvDuration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+	}
+
+	// A complete DATABASE_URL, as provided by cloud platforms, is used verbatim
+	if databaseURL := getEnv("DATABASE_URL", ""); databaseURL != "" {
+		db.URL = databaseURL
+		if name := postgresURLDatabase(databaseURL); name != "" {
+			db.Name = name
+		} else {
+			fmt.Printf("Warning: no database name in DATABASE_URL, using %s\n", db.Name)
+		}
+	}
+
+	redisURL := getEnv("REDIS_URL", "")
+	if redisURL == "" {
+		redisURL = redisURLFromEnv()
+	}
+
+	environment := getEnv("ENVIRONMENT", "development")
+
+	// Only create missing databases automatically outside production unless asked to
+	autoCreateDB, err := strconv.ParseBool(getEnv("AUTO_CREATE_DB", strconv.FormatBool(environment != "production")))
+	if err != nil {
+		fmt.Printf("Warning: invalid AUTO_CREATE_DB, database auto-creation disabled\n")
+		autoCreateDB = false
+	}
+
+	// Debug endpoints are available outside production unless disabled
+	debugEndpoints, err := strconv.ParseBool(getEnv("DEBUG_ENDPOINTS", strconv.FormatBool(environment != "production")))
+	if err != nil {
+		fmt.Printf("Warning: invalid DEBUG_ENDPOINTS, debug endpoints disabled\n")
+		debugEndpoints = false
+	}
+
+	cacheBypassEnabled, err := strconv.ParseBool(getEnv("CACHE_BYPASS_ENABLED", "false"))
+	if err != nil {
+		fmt.Printf("Warning: invalid CACHE_BYPASS_ENABLED, cache bypass disabled\n")
+		cacheBypassEnabled = false
+	}
+
+	redisCacheTimeout := getEnvDuration("REDIS_CACHE_TIMEOUT", 200*time.Millisecond)
+
+	adsCacheSoftTTL := getEnvDuration("ADS_CACHE_SOFT_TTL", 5*time.Minute)
+	adsCacheHardTTL := getEnvDuration("ADS_CACHE_HARD_TTL", 15*time.Minute)
+	if adsCacheHardTTL < adsCacheSoftTTL {
+		fmt.Printf("Warning: ADS_CACHE_HARD_TTL is shorter than ADS_CACHE_SOFT_TTL, using the soft TTL\n")
+		adsCacheHardTTL = adsCacheSoftTTL
+	}
+
+	adCacheTTL := getEnvDuration("AD_CACHE_TTL", 10*time.Minute)
+	adNotFoundCacheTTL := getEnvDuration("AD_NOT_FOUND_CACHE_TTL", 30*time.Second)
+
+	exportMaxRows, err := strconv.Atoi(getEnv("EXPORT_MAX_ROWS", "100000"))
+	if err != nil || exportMaxRows <= 0 {
+		fmt.Printf("Warning: invalid EXPORT_MAX_ROWS, using 100000\n")
+		exportMaxRows = 100000
+	}
+
+	maxPageSize, err := strconv.Atoi(getEnv("MAX_PAGE_SIZE", "100"))
+	if err != nil || maxPageSize <= 0 {
+		fmt.Printf("Warning: invalid MAX_PAGE_SIZE, using 100\n")
+		maxPageSize = 100
+	}
 
-	dbURL := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		dbHost, dbPort, dbUser, dbPass, dbName, dbSSLMode)
+	duplicateThreshold, err := strconv.ParseFloat(getEnv("DUPLICATE_SIMILARITY_THRESHOLD", "0.8"), 64)
+	if err != nil || duplicateThreshold < 0 || duplicateThreshold > 1 {
+		fmt.Printf("Warning: invalid DUPLICATE_SIMILARITY_THRESHOLD, using 0.8\n")
+		duplicateThreshold = 0.8
+	}
+
+	eventPublisher := getEnv("EVENT_PUBLISHER", "")
+	if eventPublisher != "" && !slices.Contains([]string{"redis", "nats", "kafka", "log"}, eventPublisher) {
+		fmt.Printf("Warning: invalid EVENT_PUBLISHER %q, ad events are not published\n", eventPublisher)
+		eventPublisher = ""
+	}
+	// Kafka topics cannot contain colons, so brokers get a dotted name
+	eventStream := "ads:events"
+	if eventPublisher == "nats" || eventPublisher == "kafka" {
+		eventStream = "ads.events"
+	}
+
+	searchBackend := getEnv("SEARCH_BACKEND", "postgres")
+	if searchBackend != "postgres" && searchBackend != "opensearch" {
+		fmt.Printf("Warning: invalid SEARCH_BACKEND %q, using postgres\n", searchBackend)
+		searchBackend = "postgres"
+	}
+
+	phoneEncryptionKey, err := base64.StdEncoding.DecodeString(getEnv("PHONE_ENCRYPTION_KEY", ""))
+	if err != nil || (len(phoneEncryptionKey) != 0 && len(phoneEncryptionKey) != 32) {
+		fmt.Printf("Warning: PHONE_ENCRYPTION_KEY must be 32 base64-encoded bytes, ad phones disabled\n")
+		phoneEncryptionKey = nil
+	}
+
+	corsAllowCredentials, err := strconv.ParseBool(getEnv("CORS_ALLOW_CREDENTIALS", "false"))
+	if err != nil {
+		fmt.Printf("Warning: invalid CORS_ALLOW_CREDENTIALS, credentials disallowed\n")
+		corsAllowCredentials = false
+	}
 
+	trustGatewayHeaders, err := strconv.ParseBool(getEnv("TRUST_GATEWAY_HEADERS", "false"))
+	if err != nil {
+		fmt.Printf("Warning: invalid TRUST_GATEWAY_HEADERS, gateway headers ignored\n")
+		trustGatewayHeaders = false
+	}
+
+	return &Config{
+		ServerAddress:       getEnv("SERVER_ADDRESS", ":8080"),
+		GRPCAddress:         getEnv("GRPC_ADDRESS", ":9090"),
+		GRPCRequestTimeout:  getEnvDuration("GRPC_REQUEST_TIMEOUT", 10*time.Second),
+		DatabaseURL:         db.DSN(),
+		RedisURL:            redisURL,
+		Environment:         environment,
+		DBName:              db.Name,
+		DB:                  db,
+		AutoCreateDB:        autoCreateDB,
+		DebugEndpoints:      debugEndpoints,
+		RedisCacheTimeout:   redisCacheTimeout,
+		CacheBypassEnabled:  cacheBypassEnabled,
+		CacheCodec:          getEnv("CACHE_CODEC", "json"),
+		CacheGzipMinSize:    getEnvInt("CACHE_GZIP_MIN_SIZE", 0),
+		AdsCacheSoftTTL:     adsCacheSoftTTL,
+		AdsCacheHardTTL:     adsCacheHardTTL,
+		AdCacheTTL:          adCacheTTL,
+		AdNotFoundCacheTTL:  adNotFoundCacheTTL,
+		CategoryPathTTL:     getEnvDuration("CATEGORY_PATH_CACHE_TTL", time.Hour),
+		ViewFlushInterval:   getEnvDuration("VIEW_COUNT_FLUSH_INTERVAL", time.Minute),
+		ActivationDelay:     getEnvDuration("AUTO_ACTIVATE_DELAY", 0),
+		ActivationInterval:  getEnvDuration("AUTO_ACTIVATE_INTERVAL", time.Minute),
+		ExpirationInterval:  getEnvDuration("EXPIRATION_CHECK_INTERVAL", time.Minute),
+		AdLifetime:          getEnvDuration("AD_LIFETIME", 30*24*time.Hour),
+		SearchBackend:       searchBackend,
+		OpenSearchURL:       getEnv("OPENSEARCH_URL", "http://localhost:9200"),
+		OpenSearchIndex:     getEnv("OPENSEARCH_INDEX", "ads"),
+		EventPublisher:      eventPublisher,
+		EventStream:         getEnv("EVENT_STREAM", eventStream),
+		NATSURL:             getEnv("NATS_URL", "nats://localhost:4222"),
+		KafkaBrokers:        getEnvList("KAFKA_BROKERS", []string{"localhost:9092"}),
+		OutboxInterval:      getEnvDuration("OUTBOX_POLL_INTERVAL", time.Second),
+		OutboxRetention:     getEnvDuration("OUTBOX_RETENTION", 7*24*time.Hour),
+		StorageEndpoint:     getEnv("S3_ENDPOINT", ""),
+		StorageBucket:       getEnv("S3_BUCKET", "ads"),
+		StorageRegion:       getEnv("S3_REGION", "us-east-1"),
+		StorageAccessKey:    getEnv("S3_ACCESS_KEY", ""),
+		StorageSecretKey:    getEnv("S3_SECRET_KEY", ""),
+		StoragePublicURL:    getEnv("S3_PUBLIC_URL", ""),
+		MaxImageSize:        int64(getEnvInt("MAX_IMAGE_SIZE", 10<<20)),
+		MigrationsDir:       getEnv("MIGRATIONS_DIR", "migrations"),
+		SiteBaseURL:         getEnv("SITE_BASE_URL", "http://localhost:3000"),
+		AdminAPIKey:         getEnv("ADMIN_API_KEY", ""),
+		ExportMaxRows:       exportMaxRows,
+		MaxPageSize:         maxPageSize,
+		AutoRejectThreshold: getEnvInt("AUTO_REJECT_THRESHOLD", 5),
+		DuplicateThreshold:  duplicateThreshold,
+		DuplicateWindow:     getEnvDuration("DUPLICATE_WINDOW", 30*24*time.Hour),
+		MaxRequestBodySize:  int64(getEnvInt("MAX_REQUEST_BODY_SIZE", 1<<20)),
+		MaxImportSize:       int64(getEnvInt("MAX_IMPORT_SIZE", 100<<20)),
+		MaxImportItems:      getEnvInt("MAX_IMPORT_ITEMS", 10000),
+		ImportConcurrency:   getEnvInt("IMPORT_CONCURRENCY", 2),
+		CompressionMinBytes: getEnvInt("COMPRESSION_MIN_BYTES", 1024),
+		LangFallbackChain:   parseLangFallbackChain(getEnvList("LANG_FALLBACK_CHAIN", []string{"2"})),
+		CategoryCurrencies:  parseCategoryCurrencies(getEnv("CATEGORY_ALLOWED_CURRENCIES", "")),
+		PhoneEncryptionKey:  phoneEncryptionKey,
+		DefaultVisibleStatuses: parseStatuses("DEFAULT_VISIBLE_STATUSES",
+			getEnvList("DEFAULT_VISIBLE_STATUSES", []string{"active", "approved"})),
+		JWT: JWTConfig{
+			JWKSURL:             getEnv("JWT_JWKS_URL", ""),
+			Issuer:              getEnv("JWT_ISSUER", ""),
+			Audience:            getEnv("JWT_AUDIENCE", ""),
+			TrustGatewayHeaders: trustGatewayHeaders,
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: getEnvList("CORS_ALLOWED_ORIGINS", nil),
+			AllowedMethods: getEnvList("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}),
+			AllowedHeaders: getEnvList("CORS_ALLOWED_HEADERS", []string{"Origin", "Content-Type", "Accept", "Authorization"}),
+
+			AllowCredentials: corsAllowCredentials,
+			MaxAge:           getEnvDuration("CORS_MAX_AGE", 10*time.Minute),
+		},
+		unsetRequired: unsetRequired,
+	}
+}
+
+// redisURLFromEnv builds the Redis URL from the discrete REDIS_* settings
+func redisURLFromEnv() string {
 	redisHost := getEnv("REDIS_HOST", "localhost")
 	redisPort := getEnv("REDIS_PORT", "6379")
 	redisDB := getEnv("REDIS_DB", "0")
 	redisPass := getEnv("REDIS_PASSWORD", "")
 
-	redisURL := fmt.Sprintf("redis://%s:%s@%s:%s/%s",
-		redisPass, redisPass, redisHost, redisPort, redisDB)
-
-	return &Config{
-		ServerAddress: getEnv("SERVER_ADDRESS", ":8080"),
-		DatabaseURL:   dbURL,
-		RedisURL:      redisURL,
-		Environment:   getEnv("ENVIRONMENT", "development"),
-		DBName:        dbName,
+	// Redis authenticates with the password alone, so the URL has an empty username
+	redisURL := url.URL{
+		Scheme: "redis",
+		Host:   net.JoinHostPort(redisHost, redisPort),
+		Path:   "/" + redisDB,
 	}
+	if redisPass != "" {
+		redisURL.User = url.UserPassword("", redisPass)
+	}
+	return redisURL.String()
 }
 
 func getEnv(key, defaultValue string) string {
@@ -54,3 +481,105 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvDuration reads a positive duration such as "200ms", using the default when unset or invalid
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		fmt.Printf("Warning: invalid %s %q, using %s\n", key, value, defaultValue)
+		return defaultValue
+	}
+	return duration
+}
+
+// getEnvInt reads a non-negative integer, using the default when unset or invalid
+func getEnvInt(key string, defaultValue int) int {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		fmt.Printf("Warning: invalid %s %q, using %d\n", key, value, defaultValue)
+		return defaultValue
+	}
+	return n
+}
+
+// getEnvList reads a comma-separated list, ignoring empty entries
+func getEnvList(key string, defaultValue []string) []string {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// parseCategoryCurrencies parses "category:currency,currency;category:currency" entries,
+// e.g. "12:TRY;15:949,USD"
+func parseCategoryCurrencies(value string) map[int][]domain.Currency {
+	result := make(map[int][]domain.Currency)
+	for _, entry := range strings.Split(value, ";") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+
+		category, currencies, ok := strings.Cut(entry, ":")
+		categoryID, err := strconv.Atoi(strings.TrimSpace(category))
+		if !ok || err != nil {
+			fmt.Printf("Warning: invalid CATEGORY_ALLOWED_CURRENCIES entry %q\n", entry)
+			continue
+		}
+
+		for _, code := range strings.Split(currencies, ",") {
+			currency, err := domain.ParseCurrency(code)
+			if err != nil {
+				fmt.Printf("Warning: invalid CATEGORY_ALLOWED_CURRENCIES entry %q: %v\n", entry, err)
+				continue
+			}
+			result[categoryID] = append(result[categoryID], currency)
+		}
+	}
+	return result
+}
+
+// parseStatuses parses status names or codes, skipping invalid entries
+func parseStatuses(key string, values []string) []domain.AdStatus {
+	var statuses []domain.AdStatus
+	for _, value := range values {
+		status, err := domain.ParseAdStatus(value)
+		if err != nil {
+			fmt.Printf("Warning: invalid %s entry: %v\n", key, err)
+			continue
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// parseLangFallbackChain parses language codes or numbers, skipping invalid entries
+func parseLangFallbackChain(values []string) domain.LangFallbackChain {
+	var chain domain.LangFallbackChain
+	for _, value := range values {
+		lang, err := domain.ParseLanguage(value)
+		if err != nil {
+			fmt.Printf("Warning: invalid LANG_FALLBACK_CHAIN entry: %v\n", err)
+			continue
+		}
+		chain = append(chain, lang)
+	}
+	return chain
+}
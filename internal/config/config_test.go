@@ -0,0 +1,103 @@
+package config
+
+import (
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/1way-market/v3/internal/domain"
+)
+
+func TestValidateCORS(t *testing.T) {
+	tests := []struct {
+		name        string
+		origins     []string
+		credentials bool
+		wantErr     bool
+	}{
+		{"none", nil, true, false},
+		{"listed with credentials", []string{"https://1way.market"}, true, false},
+		{"any without credentials", []string{"*"}, false, false},
+		{"any with credentials", []string{"https://1way.market", "*"}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{CORS: CORSConfig{AllowedOrigins: tt.origins, AllowCredentials: tt.credentials}}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCORSDeniesAllOriginsByDefault(t *testing.T) {
+	for _, environment := range []string{"development", "production"} {
+		t.Setenv("ENVIRONMENT", environment)
+		t.Setenv("CORS_ALLOWED_ORIGINS", "")
+		os.Unsetenv("CORS_ALLOWED_ORIGINS")
+		if origins := New().CORS.AllowedOrigins; len(origins) != 0 {
+			t.Errorf("%s allows origins %v by default, want none", environment, origins)
+		}
+	}
+}
+
+func TestDefaultVisibleStatuses(t *testing.T) {
+	tests := []struct {
+		name  string
+		value *string
+		want  []domain.AdStatus
+	}{
+		{"unset", nil, []domain.AdStatus{domain.StatusActive, domain.StatusApproved}},
+		{"listed", ptr("pending, active"), []domain.AdStatus{domain.StatusPending, domain.StatusActive}},
+		{"invalid entries skipped", ptr("active,hidden"), []domain.AdStatus{domain.StatusActive}},
+		{"empty shows every status", ptr(""), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DEFAULT_VISIBLE_STATUSES", "")
+			if tt.value == nil {
+				os.Unsetenv("DEFAULT_VISIBLE_STATUSES")
+			} else {
+				t.Setenv("DEFAULT_VISIBLE_STATUSES", *tt.value)
+			}
+			if got := New().DefaultVisibleStatuses; !slices.Equal(got, tt.want) {
+				t.Errorf("DefaultVisibleStatuses = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestEventPublisher(t *testing.T) {
+	tests := []struct {
+		publisher  string
+		wantKind   string
+		wantStream string
+	}{
+		{"redis", "redis", "ads:events"},
+		{"nats", "nats", "ads.events"},
+		{"kafka", "kafka", "ads.events"},
+		{"rabbitmq", "", "ads:events"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.publisher, func(t *testing.T) {
+			t.Setenv("EVENT_PUBLISHER", tt.publisher)
+			t.Setenv("EVENT_STREAM", "")
+			os.Unsetenv("EVENT_STREAM")
+
+			cfg := New()
+			if cfg.EventPublisher != tt.wantKind || cfg.EventStream != tt.wantStream {
+				t.Errorf("publisher %q to %q, want %q to %q", cfg.EventPublisher, cfg.EventStream, tt.wantKind, tt.wantStream)
+			}
+		})
+	}
+
+	t.Setenv("EVENT_PUBLISHER", "kafka")
+	t.Setenv("EVENT_STREAM", "marketplace.ads")
+	t.Setenv("KAFKA_BROKERS", "kafka-1:9092, kafka-2:9092")
+	cfg := New()
+	if cfg.EventStream != "marketplace.ads" || !slices.Equal(cfg.KafkaBrokers, []string{"kafka-1:9092", "kafka-2:9092"}) {
+		t.Errorf("topic %q on %v", cfg.EventStream, cfg.KafkaBrokers)
+	}
+}
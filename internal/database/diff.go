@@ -0,0 +1,108 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// SchemaDiff compares the database with the expected schema and returns the
+// DDL statements that would reconcile them, without executing anything.
+// Destructive statements, such as dropping extra columns, are returned
+// commented out for the operator to review.
+func SchemaDiff(db *sql.DB) ([]string, error) {
+	issues, err := findSchemaIssues(db, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var statements []string
+	for _, issue := range issues {
+		statements = append(statements, issueDDL(issue)...)
+	}
+	return statements, nil
+}
+
+// issueDDL returns the statements fixing a single schema issue
+func issueDDL(issue SchemaIssue) []string {
+	switch issue.Kind {
+	case IssueMissingTable:
+		return createTableDDL(expectedTables[issue.Table])
+	case IssueMissingColumn:
+		return []string{fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", issue.Table, columnDefinition(*issue.Expected))}
+	case IssueColumnMismatch:
+		return alterColumnDDL(issue.Table, *issue.Expected, *issue.Actual)
+	case IssueExtraColumn:
+		return []string{fmt.Sprintf("-- ALTER TABLE %s DROP COLUMN %s;", issue.Table, issue.Actual.Name)}
+	case IssueMissingIndex:
+		if issue.Index.Definition == "" {
+			return []string{fmt.Sprintf("-- %s: recreate the primary key of %s", issue.Index.Name, issue.Table)}
+		}
+		return []string{issue.Index.Definition + ";"}
+	default:
+		return nil
+	}
+}
+
+// createTableDDL returns the statements creating a table with its indexes
+func createTableDDL(table TableInfo) []string {
+	columns := make([]string, len(table.Columns))
+	for i, column := range table.Columns {
+		columns[i] = "    " + columnDefinition(column)
+	}
+
+	statements := []string{fmt.Sprintf("CREATE TABLE %s (\n%s\n);", table.Name, strings.Join(columns, ",\n"))}
+	for _, index := range table.Indexes {
+		if index.Definition != "" {
+			statements = append(statements, index.Definition+";")
+		}
+	}
+	return statements
+}
+
+// columnDefinition renders a column as used in CREATE TABLE and ADD COLUMN
+func columnDefinition(column ColumnInfo) string {
+	definition := column.Name + " " + column.SQLType
+	if column.IsSerial {
+		return definition
+	}
+	if column.IsNullable == "NO" {
+		definition += " NOT NULL"
+	}
+	if column.ColumnDefault != nil {
+		definition += " DEFAULT " + *column.ColumnDefault
+	}
+	return definition
+}
+
+// alterColumnDDL returns the statements changing an existing column to the expected definition
+func alterColumnDDL(table string, expected, actual ColumnInfo) []string {
+	prefix := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s", table, expected.Name)
+
+	var statements []string
+	if normalizeDataType(expected.DataType) != normalizeDataType(actual.DataType) {
+		statements = append(statements, fmt.Sprintf("%s TYPE %s USING %s::%s;", prefix, expected.SQLType, expected.Name, expected.SQLType))
+	}
+	if expected.IsNullable != actual.IsNullable {
+		if expected.IsNullable == "NO" {
+			statements = append(statements, prefix+" SET NOT NULL;")
+		} else {
+			statements = append(statements, prefix+" DROP NOT NULL;")
+		}
+	}
+
+	if expected.IsSerial {
+		if !actual.IsSerial {
+			statements = append(statements, fmt.Sprintf("-- %s: convert to a serial/identity column", expected.Name))
+		}
+		return statements
+	}
+	switch {
+	case expected.ColumnDefault == nil && actual.ColumnDefault != nil:
+		statements = append(statements, prefix+" DROP DEFAULT;")
+	case expected.ColumnDefault != nil &&
+		(actual.ColumnDefault == nil || !strings.Contains(*actual.ColumnDefault, *expected.ColumnDefault)):
+		statements = append(statements, fmt.Sprintf("%s SET DEFAULT %s;", prefix, *expected.ColumnDefault))
+	}
+	return statements
+}
@@ -0,0 +1,251 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/1way-market/v3/migrations"
+)
+
+// migrationLockID is the advisory lock key serializing concurrent migration runs
+const migrationLockID = 7244_0001
+
+// migrationFilePattern matches files such as 002_convert_ads_status.up.sql.
+// Files without an up/down suffix are treated as up migrations.
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+?)(?:\.(up|down))?\.sql$`)
+
+// Migration is a numbered pair of up and down SQL files from the migrations directory
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+	DownSQL string
+}
+
+// MigrationSource returns the migration files in dir, falling back to the
+// migrations embedded in the binary when dir does not exist
+func MigrationSource(dir string) fs.FS {
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return os.DirFS(dir)
+	}
+	return migrations.FS
+}
+
+// LoadMigrations reads the numbered migration files in dir, or the embedded
+// ones when dir does not exist, ordered by version
+func LoadMigrations(dir string) ([]Migration, error) {
+	return LoadMigrationsFS(MigrationSource(dir))
+}
+
+// LoadMigrationsFS reads the numbered migration files at the root of fsys ordered by version
+func LoadMigrationsFS(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations directory: %v", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	seen := make(map[string]string)
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		direction := match[3]
+		if direction == "" {
+			direction = "up"
+		}
+		slot := fmt.Sprintf("%d.%s", version, direction)
+		if other, ok := seen[slot]; ok {
+			return nil, fmt.Errorf("duplicate %s migration version %d: %s and %s", direction, version, other, entry.Name())
+		}
+		seen[slot] = entry.Name()
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("error reading migration %s: %v", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if direction == "down" {
+			migration.DownSQL = string(content)
+		} else {
+			migration.SQL = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for version, migration := range byVersion {
+		if _, ok := seen[fmt.Sprintf("%d.up", version)]; !ok {
+			return nil, fmt.Errorf("migration version %d has a down file but no up file", version)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Migrate applies the migrations in dir that are not yet recorded in
+// schema_migrations, in version order, and returns the applied versions
+func Migrate(db *sql.DB, dir string) ([]int, error) {
+	migrations, err := LoadMigrations(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`); err != nil {
+		return nil, fmt.Errorf("error creating schema_migrations table: %v", err)
+	}
+
+	var applied []int
+	for _, migration := range migrations {
+		ok, err := applyMigration(db, migration)
+		if err != nil {
+			return applied, fmt.Errorf("migration %03d_%s failed: %v", migration.Version, migration.Name, err)
+		}
+		if ok {
+			applied = append(applied, migration.Version)
+		}
+	}
+
+	return applied, nil
+}
+
+// Rollback reverts the most recently applied migration using its down file
+// and returns the reverted version, or 0 when no migration is applied
+func Rollback(db *sql.DB, dir string) (int, error) {
+	migrations, err := LoadMigrations(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	versions, err := AppliedVersions(db)
+	if err != nil {
+		return 0, fmt.Errorf("error reading applied migrations: %v", err)
+	}
+	if len(versions) == 0 {
+		return 0, nil
+	}
+
+	latest := versions[len(versions)-1]
+	idx := slices.IndexFunc(migrations, func(m Migration) bool { return m.Version == latest })
+	if idx < 0 {
+		return 0, fmt.Errorf("migration %03d is applied but missing from %s", latest, dir)
+	}
+	migration := migrations[idx]
+	if migration.DownSQL == "" {
+		return 0, fmt.Errorf("migration %03d_%s has no down file", migration.Version, migration.Name)
+	}
+
+	if err := revertMigration(db, migration); err != nil {
+		return 0, fmt.Errorf("rollback of %03d_%s failed: %v", migration.Version, migration.Name, err)
+	}
+	return migration.Version, nil
+}
+
+// AppliedVersions returns the versions recorded in schema_migrations in ascending order
+func AppliedVersions(db *sql.DB) ([]int, error) {
+	rows, err := db.Query(`SELECT version FROM schema_migrations ORDER BY version`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []int
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, rows.Err()
+}
+
+// applyMigration runs a single migration in a transaction unless it was already applied
+func applyMigration(db *sql.DB, migration Migration) (bool, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// Serialize with other instances running migrations at the same time
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1)`, migrationLockID); err != nil {
+		return false, err
+	}
+
+	var exists bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)`, migration.Version).Scan(&exists); err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(migration.SQL); err != nil {
+		return false, err
+	}
+
+	if _, err := tx.Exec(`INSERT INTO schema_migrations (version) VALUES ($1)`, migration.Version); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// revertMigration runs a migration's down file and removes its schema_migrations record
+func revertMigration(db *sql.DB, migration Migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1)`, migrationLockID); err != nil {
+		return err
+	}
+
+	// Another instance may have reverted it while we waited for the lock
+	var exists bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)`, migration.Version).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+
+	// A down file may be empty when there is nothing to undo
+	if strings.TrimSpace(migration.DownSQL) != "" {
+		if _, err := tx.Exec(migration.DownSQL); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM schema_migrations WHERE version = $1`, migration.Version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -12,89 +13,210 @@ type ColumnInfo struct {
 	IsNullable    string
 	ColumnDefault *string
 	IsSerial      bool
+	// SQLType is the type used when generating DDL for the expected column
+	SQLType string
 }
 
 type TableInfo struct {
 	Name    string
 	Columns []ColumnInfo
-	Indexes []string
+	Indexes []IndexInfo
 }
 
-func ValidateSchema(db *sql.DB) error {
-	// Expected schema definition
-	expectedTables := map[string]TableInfo{
-		"ads": {
-			Name: "ads",
-			Columns: []ColumnInfo{
-				{"id", "integer", "NO", nil, true}, // Serial/auto-increment column
-				{"title", "jsonb", "NO", nil, false},
-				{"description", "jsonb", "YES", nil, false},
-				{"properties", "jsonb", "YES", nil, false},
-				{"category_ids", "ARRAY", "YES", nil, false}, // Changed to match PostgreSQL's type
-				{"status", "integer", "NO", strPtr("0"), false},
-				{"price", "jsonb", "YES", nil, false},
-				{"search_vector", "tsvector", "YES", nil, false},
-				{"created_at", "timestamp with time zone", "YES", strPtr("CURRENT_TIMESTAMP"), false},
-				{"updated_at", "timestamp with time zone", "YES", strPtr("CURRENT_TIMESTAMP"), false},
-			},
-			Indexes: []string{
-				"ads_pkey",
-				"idx_ads_status",
-				"idx_ads_category_ids",
-				"idx_ads_search_vector",
-				"idx_ads_title",
-				"idx_ads_properties",
-				"idx_ads_price",
-				"idx_ads_created_at",
-			},
+// IndexInfo is an expected index and the statement creating it.
+// Primary key indexes have no definition since they are created with the table.
+type IndexInfo struct {
+	Name       string
+	Definition string
+}
+
+// SchemaIssueKind classifies a schema difference
+type SchemaIssueKind int
+
+const (
+	IssueMissingTable SchemaIssueKind = iota
+	IssueMissingColumn
+	IssueColumnMismatch
+	IssueExtraColumn
+	IssueMissingIndex
+)
+
+// SchemaIssue is a single difference between the expected and actual schema
+type SchemaIssue struct {
+	Kind    SchemaIssueKind
+	Table   string
+	Message string
+	// Expected is the expected column, or nil for table and index issues
+	Expected *ColumnInfo
+	// Actual is the column found in the database, or nil when it is missing
+	Actual *ColumnInfo
+	// Index is the missing index for IssueMissingIndex
+	Index *IndexInfo
+}
+
+// SchemaError lists every schema difference found by ValidateSchema
+type SchemaError struct {
+	Issues []SchemaIssue
+}
+
+func (e *SchemaError) Error() string {
+	messages := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		messages[i] = issue.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// expectedTables is the schema the application expects
+var expectedTables = map[string]TableInfo{
+	"ads": {
+		Name: "ads",
+		Columns: []ColumnInfo{
+			{"id", "integer", "NO", nil, true, "SERIAL PRIMARY KEY"}, // Serial/auto-increment column
+			{"title", "jsonb", "NO", nil, false, "JSONB"},
+			{"description", "jsonb", "YES", nil, false, "JSONB"},
+			{"properties", "jsonb", "YES", nil, false, "JSONB"},
+			{"media", "jsonb", "NO", strPtr("'[]'::jsonb"), false, "JSONB"},
+			{"images", "jsonb", "NO", strPtr("'[]'::jsonb"), false, "JSONB"},
+			{"category_ids", "ARRAY", "YES", nil, false, "INTEGER[]"}, // Changed to match PostgreSQL's type
+			{"status", "integer", "NO", strPtr("0"), false, "INTEGER"},
+			{"price", "jsonb", "YES", nil, false, "JSONB"},
+			{"search_vector", "tsvector", "YES", nil, false, "TSVECTOR"},
+			{"raw_source", "jsonb", "YES", nil, false, "JSONB"},
+			{"seller_id", "integer", "YES", nil, false, "INTEGER REFERENCES sellers(id) ON DELETE SET NULL"},
+			{"owner_id", "character varying", "YES", nil, false, "VARCHAR(255)"},
+			{"duplicate_of", "integer", "YES", nil, false, "INTEGER REFERENCES ads(id) ON DELETE SET NULL"},
+			{"slug", "character varying", "YES", nil, false, "VARCHAR(255)"},
+			{"phone_encrypted", "text", "YES", nil, false, "TEXT"},
+			{"phone_masked", "character varying", "YES", nil, false, "VARCHAR(50)"},
+			{"view_count", "bigint", "NO", strPtr("0"), false, "BIGINT"},
+			{"version", "integer", "NO", strPtr("1"), false, "INTEGER"},
+			{"status_reason", "character varying", "YES", nil, false, "VARCHAR(100)"},
+			{"expires_at", "timestamp with time zone", "YES", nil, false, "TIMESTAMP WITH TIME ZONE"},
+			{"created_at", "timestamp with time zone", "YES", strPtr("CURRENT_TIMESTAMP"), false, "TIMESTAMP WITH TIME ZONE"},
+			{"updated_at", "timestamp with time zone", "YES", strPtr("CURRENT_TIMESTAMP"), false, "TIMESTAMP WITH TIME ZONE"},
+		},
+		Indexes: []IndexInfo{
+			{"ads_pkey", ""},
+			{"idx_ads_status", "CREATE INDEX idx_ads_status ON ads(status)"},
+			{"idx_ads_category_ids", "CREATE INDEX idx_ads_category_ids ON ads USING GIN(category_ids)"},
+			{"idx_ads_search_vector", "CREATE INDEX idx_ads_search_vector ON ads USING GIN(search_vector)"},
+			{"idx_ads_title", "CREATE INDEX idx_ads_title ON ads USING GIN(title jsonb_path_ops)"},
+			{"idx_ads_properties", "CREATE INDEX idx_ads_properties ON ads USING GIN(properties)"},
+			{"idx_ads_price", "CREATE INDEX idx_ads_price ON ads(price)"},
+			{"idx_ads_created_at", "CREATE INDEX idx_ads_created_at ON ads(created_at)"},
+			{"idx_ads_seller_id", "CREATE INDEX idx_ads_seller_id ON ads(seller_id)"},
+			{"idx_ads_owner_id", "CREATE INDEX idx_ads_owner_id ON ads(owner_id, created_at DESC) WHERE owner_id IS NOT NULL"},
+			{"idx_ads_slug", "CREATE UNIQUE INDEX idx_ads_slug ON ads(slug)"},
+			{"idx_ads_duplicate_of", "CREATE INDEX idx_ads_duplicate_of ON ads(duplicate_of) WHERE duplicate_of IS NOT NULL"},
+			{"idx_ads_category_ids_created_at", "CREATE INDEX idx_ads_category_ids_created_at ON ads USING GIN(category_ids, created_at)"},
+		},
+	},
+	"categories": {
+		Name: "categories",
+		Columns: []ColumnInfo{
+			{"id", "integer", "NO", nil, true, "SERIAL PRIMARY KEY"},
+			{"parent_id", "integer", "YES", nil, false, "INTEGER REFERENCES categories(id)"},
+			{"name", "jsonb", "NO", nil, false, "JSONB"},
+			{"created_at", "timestamp with time zone", "YES", strPtr("CURRENT_TIMESTAMP"), false, "TIMESTAMP WITH TIME ZONE"},
+			{"updated_at", "timestamp with time zone", "YES", strPtr("CURRENT_TIMESTAMP"), false, "TIMESTAMP WITH TIME ZONE"},
+		},
+		Indexes: []IndexInfo{
+			{"categories_pkey", ""},
+			{"idx_categories_parent_id", "CREATE INDEX idx_categories_parent_id ON categories(parent_id)"},
+		},
+	},
+	"category_closure": {
+		Name: "category_closure",
+		Columns: []ColumnInfo{
+			{"ancestor_id", "integer", "NO", nil, false, "INTEGER REFERENCES categories(id) ON DELETE CASCADE"},
+			{"descendant_id", "integer", "NO", nil, false, "INTEGER REFERENCES categories(id) ON DELETE CASCADE"},
+			{"depth", "integer", "NO", nil, false, "INTEGER"},
 		},
-		"category_closure": {
-			Name: "category_closure",
-			Columns: []ColumnInfo{
-				{"ancestor_id", "integer", "NO", nil, false},
-				{"descendant_id", "integer", "NO", nil, false},
-				{"depth", "integer", "NO", nil, false},
-			},
-			Indexes: []string{
-				"category_closure_pkey",
-				"idx_category_closure_ancestor",
-				"idx_category_closure_descendant",
-			},
+		Indexes: []IndexInfo{
+			{"category_closure_pkey", ""},
+			{"idx_category_closure_ancestor", "CREATE INDEX idx_category_closure_ancestor ON category_closure(ancestor_id)"},
+			{"idx_category_closure_descendant", "CREATE INDEX idx_category_closure_descendant ON category_closure(descendant_id)"},
 		},
+	},
+}
+
+// ValidateSchema compares the database schema with the expected one and
+// returns a *SchemaError listing all differences. With failFast it stops
+// at the first difference instead.
+func ValidateSchema(db *sql.DB, failFast bool) error {
+	issues, err := findSchemaIssues(db, failFast)
+	if err != nil {
+		return err
+	}
+	if len(issues) > 0 {
+		return &SchemaError{Issues: issues}
+	}
+	return nil
+}
+
+// findSchemaIssues lists the differences between the expected and actual schema
+func findSchemaIssues(db *sql.DB, failFast bool) ([]SchemaIssue, error) {
+	// Check tables in a stable order so reports are comparable between runs
+	tableNames := make([]string, 0, len(expectedTables))
+	for tableName := range expectedTables {
+		tableNames = append(tableNames, tableName)
 	}
+	sort.Strings(tableNames)
+
+	var issues []SchemaIssue
+	// report records an issue and tells whether validation should stop
+	report := func(issue SchemaIssue, message string, args ...interface{}) bool {
+		issue.Message = fmt.Sprintf(message, args...)
+		issues = append(issues, issue)
+		return failFast
+	}
+
+	for _, tableName := range tableNames {
+		expectedTable := expectedTables[tableName]
 
-	// Check each expected table
-	for tableName, expectedTable := range expectedTables {
 		// Check if table exists
 		if !tableExists(db, tableName) {
-			return fmt.Errorf("table %s does not exist", tableName)
+			if report(SchemaIssue{Kind: IssueMissingTable, Table: tableName}, "table %s does not exist", tableName) {
+				return issues, nil
+			}
+			continue
 		}
 
 		// Get actual columns
 		actualColumns, err := getTableColumns(db, tableName)
 		if err != nil {
-			return fmt.Errorf("error getting columns for table %s: %v", tableName, err)
+			return nil, fmt.Errorf("error getting columns for table %s: %v", tableName, err)
 		}
 
 		// Compare columns
-		for _, expectedCol := range expectedTable.Columns {
+		for i := range expectedTable.Columns {
+			expectedCol := &expectedTable.Columns[i]
 			found := false
-			for _, actualCol := range actualColumns {
+			for j := range actualColumns {
+				actualCol := &actualColumns[j]
 				if expectedCol.Name == actualCol.Name {
 					found = true
-					if err := compareColumns(expectedCol, actualCol); err != nil {
-						return fmt.Errorf("column mismatch in table %s: %v", tableName, err)
+					if err := compareColumns(*expectedCol, *actualCol); err != nil {
+						issue := SchemaIssue{Kind: IssueColumnMismatch, Table: tableName, Expected: expectedCol, Actual: actualCol}
+						if report(issue, "column mismatch in table %s: %v", tableName, err) {
+							return issues, nil
+						}
 					}
 					break
 				}
 			}
 			if !found {
-				return fmt.Errorf("missing column %s in table %s", expectedCol.Name, tableName)
+				issue := SchemaIssue{Kind: IssueMissingColumn, Table: tableName, Expected: expectedCol}
+				if report(issue, "missing column %s in table %s", expectedCol.Name, tableName) {
+					return issues, nil
+				}
 			}
 		}
 
 		// Check for extra columns
-		for _, actualCol := range actualColumns {
+		for j := range actualColumns {
+			actualCol := &actualColumns[j]
 			found := false
 			for _, expectedCol := range expectedTable.Columns {
 				if actualCol.Name == expectedCol.Name {
@@ -103,31 +225,38 @@ func ValidateSchema(db *sql.DB) error {
 				}
 			}
 			if !found {
-				return fmt.Errorf("extra column %s found in table %s", actualCol.Name, tableName)
+				issue := SchemaIssue{Kind: IssueExtraColumn, Table: tableName, Actual: actualCol}
+				if report(issue, "extra column %s found in table %s", actualCol.Name, tableName) {
+					return issues, nil
+				}
 			}
 		}
 
 		// Check indexes
 		actualIndexes, err := getTableIndexes(db, tableName)
 		if err != nil {
-			return fmt.Errorf("error getting indexes for table %s: %v", tableName, err)
+			return nil, fmt.Errorf("error getting indexes for table %s: %v", tableName, err)
 		}
 
-		for _, expectedIdx := range expectedTable.Indexes {
+		for i := range expectedTable.Indexes {
+			expectedIdx := &expectedTable.Indexes[i]
 			found := false
 			for _, actualIdx := range actualIndexes {
-				if strings.EqualFold(expectedIdx, actualIdx) {
+				if strings.EqualFold(expectedIdx.Name, actualIdx) {
 					found = true
 					break
 				}
 			}
 			if !found {
-				return fmt.Errorf("missing index %s in table %s", expectedIdx, tableName)
+				issue := SchemaIssue{Kind: IssueMissingIndex, Table: tableName, Index: expectedIdx}
+				if report(issue, "missing index %s in table %s", expectedIdx.Name, tableName) {
+					return issues, nil
+				}
 			}
 		}
 	}
 
-	return nil
+	return issues, nil
 }
 
 func tableExists(db *sql.DB, tableName string) bool {
@@ -0,0 +1,270 @@
+// Contract of the ads API for internal consumers, mirroring domain.Ad,
+// domain.FilterRequest and domain.PaginatedResponse. Callers authenticate
+// with the bearer tokens of the HTTP API, in the authorization metadata.
+//
+// The Go code in internal/delivery/grpc/adspb is generated with
+// protoc-gen-go v1.30.0 and protoc-gen-go-grpc v1.3.0:
+//
+//   protoc --go_out=. --go_opt=module=github.com/1way-market/v3 \
+//     --go-grpc_out=. --go-grpc_opt=module=github.com/1way-market/v3 \
+//     api/proto/ads/v3/ads.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.23.4
+// source: api/proto/ads/v3/ads.proto
+
+package adspb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	AdsService_GetAds_FullMethodName   = "/ads.v3.AdsService/GetAds"
+	AdsService_GetAd_FullMethodName    = "/ads.v3.AdsService/GetAd"
+	AdsService_CreateAd_FullMethodName = "/ads.v3.AdsService/CreateAd"
+	AdsService_UpdateAd_FullMethodName = "/ads.v3.AdsService/UpdateAd"
+	AdsService_DeleteAd_FullMethodName = "/ads.v3.AdsService/DeleteAd"
+)
+
+// AdsServiceClient is the client API for AdsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdsServiceClient interface {
+	GetAds(ctx context.Context, in *GetAdsRequest, opts ...grpc.CallOption) (*GetAdsResponse, error)
+	GetAd(ctx context.Context, in *GetAdRequest, opts ...grpc.CallOption) (*Ad, error)
+	CreateAd(ctx context.Context, in *CreateAdRequest, opts ...grpc.CallOption) (*Ad, error)
+	// UpdateAd fails with ABORTED when the ad was updated since version
+	UpdateAd(ctx context.Context, in *UpdateAdRequest, opts ...grpc.CallOption) (*Ad, error)
+	DeleteAd(ctx context.Context, in *DeleteAdRequest, opts ...grpc.CallOption) (*DeleteAdResponse, error)
+}
+
+type adsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdsServiceClient(cc grpc.ClientConnInterface) AdsServiceClient {
+	return &adsServiceClient{cc}
+}
+
+func (c *adsServiceClient) GetAds(ctx context.Context, in *GetAdsRequest, opts ...grpc.CallOption) (*GetAdsResponse, error) {
+	out := new(GetAdsResponse)
+	err := c.cc.Invoke(ctx, AdsService_GetAds_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adsServiceClient) GetAd(ctx context.Context, in *GetAdRequest, opts ...grpc.CallOption) (*Ad, error) {
+	out := new(Ad)
+	err := c.cc.Invoke(ctx, AdsService_GetAd_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adsServiceClient) CreateAd(ctx context.Context, in *CreateAdRequest, opts ...grpc.CallOption) (*Ad, error) {
+	out := new(Ad)
+	err := c.cc.Invoke(ctx, AdsService_CreateAd_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adsServiceClient) UpdateAd(ctx context.Context, in *UpdateAdRequest, opts ...grpc.CallOption) (*Ad, error) {
+	out := new(Ad)
+	err := c.cc.Invoke(ctx, AdsService_UpdateAd_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adsServiceClient) DeleteAd(ctx context.Context, in *DeleteAdRequest, opts ...grpc.CallOption) (*DeleteAdResponse, error) {
+	out := new(DeleteAdResponse)
+	err := c.cc.Invoke(ctx, AdsService_DeleteAd_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdsServiceServer is the server API for AdsService service.
+// All implementations must embed UnimplementedAdsServiceServer
+// for forward compatibility
+type AdsServiceServer interface {
+	GetAds(context.Context, *GetAdsRequest) (*GetAdsResponse, error)
+	GetAd(context.Context, *GetAdRequest) (*Ad, error)
+	CreateAd(context.Context, *CreateAdRequest) (*Ad, error)
+	// UpdateAd fails with ABORTED when the ad was updated since version
+	UpdateAd(context.Context, *UpdateAdRequest) (*Ad, error)
+	DeleteAd(context.Context, *DeleteAdRequest) (*DeleteAdResponse, error)
+	mustEmbedUnimplementedAdsServiceServer()
+}
+
+// UnimplementedAdsServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedAdsServiceServer struct {
+}
+
+func (UnimplementedAdsServiceServer) GetAds(context.Context, *GetAdsRequest) (*GetAdsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAds not implemented")
+}
+func (UnimplementedAdsServiceServer) GetAd(context.Context, *GetAdRequest) (*Ad, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAd not implemented")
+}
+func (UnimplementedAdsServiceServer) CreateAd(context.Context, *CreateAdRequest) (*Ad, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAd not implemented")
+}
+func (UnimplementedAdsServiceServer) UpdateAd(context.Context, *UpdateAdRequest) (*Ad, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAd not implemented")
+}
+func (UnimplementedAdsServiceServer) DeleteAd(context.Context, *DeleteAdRequest) (*DeleteAdResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAd not implemented")
+}
+func (UnimplementedAdsServiceServer) mustEmbedUnimplementedAdsServiceServer() {}
+
+// UnsafeAdsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdsServiceServer will
+// result in compilation errors.
+type UnsafeAdsServiceServer interface {
+	mustEmbedUnimplementedAdsServiceServer()
+}
+
+func RegisterAdsServiceServer(s grpc.ServiceRegistrar, srv AdsServiceServer) {
+	s.RegisterService(&AdsService_ServiceDesc, srv)
+}
+
+func _AdsService_GetAds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAdsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdsServiceServer).GetAds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdsService_GetAds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdsServiceServer).GetAds(ctx, req.(*GetAdsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdsService_GetAd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdsServiceServer).GetAd(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdsService_GetAd_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdsServiceServer).GetAd(ctx, req.(*GetAdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdsService_CreateAd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdsServiceServer).CreateAd(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdsService_CreateAd_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdsServiceServer).CreateAd(ctx, req.(*CreateAdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdsService_UpdateAd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdsServiceServer).UpdateAd(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdsService_UpdateAd_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdsServiceServer).UpdateAd(ctx, req.(*UpdateAdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdsService_DeleteAd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdsServiceServer).DeleteAd(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdsService_DeleteAd_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdsServiceServer).DeleteAd(ctx, req.(*DeleteAdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdsService_ServiceDesc is the grpc.ServiceDesc for AdsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ads.v3.AdsService",
+	HandlerType: (*AdsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetAds",
+			Handler:    _AdsService_GetAds_Handler,
+		},
+		{
+			MethodName: "GetAd",
+			Handler:    _AdsService_GetAd_Handler,
+		},
+		{
+			MethodName: "CreateAd",
+			Handler:    _AdsService_CreateAd_Handler,
+		},
+		{
+			MethodName: "UpdateAd",
+			Handler:    _AdsService_UpdateAd_Handler,
+		},
+		{
+			MethodName: "DeleteAd",
+			Handler:    _AdsService_DeleteAd_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/proto/ads/v3/ads.proto",
+}
@@ -0,0 +1,286 @@
+package grpc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/1way-market/v3/internal/delivery/grpc/adspb"
+	"github.com/1way-market/v3/internal/domain"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// adToProto converts an ad to its message. The phone, which is never read
+// back, is left out.
+func adToProto(ad *domain.Ad) *adspb.Ad {
+	msg := &adspb.Ad{
+		Id:           uint32(ad.ID),
+		Title:        multiLangToProto(ad.Title),
+		Description:  multiLangToProto(ad.Description),
+		Properties:   propertiesToProto(ad.Properties),
+		CategoryIds:  intsToProto(ad.CategoryIDs),
+		Status:       statusToProto(ad.Status),
+		StatusReason: ad.StatusReason,
+		Price:        priceToProto(ad.Price),
+		SellerId:     uintToProto(ad.SellerID),
+		Slug:         ad.Slug,
+		PhoneMasked:  ad.PhoneMasked,
+		ViewCount:    ad.ViewCount,
+		Version:      int32(ad.Version),
+		CreatedAt:    timeToProto(ad.CreatedAt),
+		UpdatedAt:    timeToProto(ad.UpdatedAt),
+	}
+	if ad.ExpiresAt != nil {
+		msg.ExpiresAt = timestamppb.New(*ad.ExpiresAt)
+	}
+	return msg
+}
+
+// adFromProto converts an ad message, rejecting unknown languages, statuses
+// and price types
+func adFromProto(msg *adspb.Ad) (*domain.Ad, error) {
+	title, err := multiLangFromProto(msg.GetTitle())
+	if err != nil {
+		return nil, err
+	}
+	description, err := multiLangFromProto(msg.GetDescription())
+	if err != nil {
+		return nil, err
+	}
+	status, err := statusFromProto(msg.GetStatus())
+	if err != nil {
+		return nil, err
+	}
+	price, err := priceFromProto(msg.GetPrice())
+	if err != nil {
+		return nil, err
+	}
+
+	ad := &domain.Ad{
+		ID:           uint(msg.GetId()),
+		Title:        title,
+		Description:  description,
+		Properties:   propertiesFromProto(msg.GetProperties()),
+		CategoryIDs:  intsFromProto(msg.GetCategoryIds()),
+		Status:       status,
+		StatusReason: msg.GetStatusReason(),
+		Price:        price,
+		SellerID:     uintFromProto(msg.SellerId),
+		Slug:         msg.GetSlug(),
+		Phone:        msg.GetPhone(),
+		PhoneMasked:  msg.GetPhoneMasked(),
+		ViewCount:    msg.GetViewCount(),
+		Version:      int(msg.GetVersion()),
+		CreatedAt:    timeFromProto(msg.GetCreatedAt()),
+		UpdatedAt:    timeFromProto(msg.GetUpdatedAt()),
+	}
+	if msg.GetExpiresAt() != nil {
+		expiresAt := msg.GetExpiresAt().AsTime()
+		ad.ExpiresAt = &expiresAt
+	}
+	return ad, nil
+}
+
+// filterFromProto converts a listing request to the filter of GET /v3/ads
+func filterFromProto(req *adspb.GetAdsRequest) (domain.FilterRequest, error) {
+	lang, err := domain.ParseLanguage(req.GetLang())
+	if err != nil {
+		return domain.FilterRequest{}, err
+	}
+
+	filter := domain.FilterRequest{
+		CategoryIDs:           intsFromProto(req.GetCategoryIds()),
+		TextSearch:            req.GetQ(),
+		SortBy:                req.GetSort(),
+		PageToken:             req.GetNextPage(),
+		PageSize:              int(req.GetPageSize()),
+		Lang:                  lang.Code(),
+		Language:              lang,
+		MinPrice:              req.MinPrice,
+		MaxPrice:              req.MaxPrice,
+		Currency:              req.GetCurrency(),
+		SellerID:              uintFromProto(req.SellerId),
+		ExcludePriceOnRequest: req.GetExcludePriceOnRequest(),
+		IncludeExpired:        req.GetIncludeExpired(),
+	}
+	for _, property := range req.GetPropertyFilters() {
+		propertyFilter := domain.PropertyFilter{PropertyID: uint(property.GetPropertyId()), Values: property.GetValues()}
+		for _, id := range property.GetValueIds() {
+			propertyFilter.ValueIDs = append(propertyFilter.ValueIDs, uint(id))
+		}
+		filter.PropertyFilters = append(filter.PropertyFilters, propertyFilter)
+	}
+	if req.GetStatus() != adspb.AdStatus_AD_STATUS_UNSPECIFIED {
+		status, err := statusFromProto(req.GetStatus())
+		if err != nil {
+			return domain.FilterRequest{}, err
+		}
+		filter.Status = &status
+	}
+	return filter, nil
+}
+
+// pageToProto converts a page of ads to its message
+func pageToProto(page *domain.PaginatedResponse) *adspb.GetAdsResponse {
+	resp := &adspb.GetAdsResponse{
+		Items:      make([]*adspb.Ad, len(page.Items)),
+		NextPage:   page.NextPage,
+		TotalCount: page.TotalCount,
+	}
+	for i := range page.Items {
+		resp.Items[i] = adToProto(&page.Items[i])
+	}
+	return resp
+}
+
+// statusToProto converts a status; the enum values are shifted by one so
+// that 0 means unset
+func statusToProto(status domain.AdStatus) adspb.AdStatus {
+	return adspb.AdStatus(status + 1)
+}
+
+// statusFromProto converts a status enum; an unset status is a draft, as
+// for ads created without one over HTTP
+func statusFromProto(status adspb.AdStatus) (domain.AdStatus, error) {
+	if status == adspb.AdStatus_AD_STATUS_UNSPECIFIED {
+		return domain.StatusDraft, nil
+	}
+	if _, ok := adspb.AdStatus_name[int32(status)]; !ok {
+		return 0, fmt.Errorf("invalid status %d", status)
+	}
+	return domain.AdStatus(status - 1), nil
+}
+
+func multiLangToProto(texts domain.MultiLangArray) []*adspb.MultiLangText {
+	if texts == nil {
+		return nil
+	}
+	msgs := make([]*adspb.MultiLangText, len(texts))
+	for i, text := range texts {
+		msgs[i] = &adspb.MultiLangText{Lang: text.Lang.Code(), Text: text.Text}
+	}
+	return msgs
+}
+
+func multiLangFromProto(msgs []*adspb.MultiLangText) (domain.MultiLangArray, error) {
+	if len(msgs) == 0 {
+		return nil, nil
+	}
+	texts := make(domain.MultiLangArray, len(msgs))
+	for i, msg := range msgs {
+		lang, err := domain.ParseLanguage(msg.GetLang())
+		if err != nil {
+			return nil, err
+		}
+		texts[i] = domain.MultiLangText{Lang: lang, Text: msg.GetText()}
+	}
+	return texts, nil
+}
+
+// propertiesToProto converts the properties JSONB array, element by element
+func propertiesToProto(properties domain.AdProperties) []*adspb.AdProperty {
+	if properties == nil {
+		return nil
+	}
+	msgs := make([]*adspb.AdProperty, len(properties))
+	for i, property := range properties {
+		msgs[i] = &adspb.AdProperty{
+			Id:      uint32(property.ID),
+			Value:   property.Value,
+			ValueId: uintToProto(property.ValueID),
+		}
+	}
+	return msgs
+}
+
+func propertiesFromProto(msgs []*adspb.AdProperty) domain.AdProperties {
+	if len(msgs) == 0 {
+		return nil
+	}
+	properties := make(domain.AdProperties, len(msgs))
+	for i, msg := range msgs {
+		properties[i] = domain.AdProperty{
+			ID:      uint(msg.GetId()),
+			Value:   msg.GetValue(),
+			ValueID: uintFromProto(msg.ValueId),
+		}
+	}
+	return properties
+}
+
+// priceToProto converts a price; the price type enum values match domain.PriceType
+func priceToProto(price *domain.Price) *adspb.Price {
+	if price == nil {
+		return nil
+	}
+	return &adspb.Price{Value: price.Value, Currency: price.Currency, Type: adspb.PriceType(price.Type)}
+}
+
+// priceFromProto converts a price message; prices without a type are fixed,
+// as in JSON
+func priceFromProto(msg *adspb.Price) (*domain.Price, error) {
+	if msg == nil {
+		return nil, nil
+	}
+	price := &domain.Price{Value: msg.GetValue(), Currency: msg.GetCurrency(), Type: domain.PriceType(msg.GetType())}
+	switch msg.GetType() {
+	case adspb.PriceType_PRICE_TYPE_UNSPECIFIED:
+		price.Type = domain.PriceFixed
+	case adspb.PriceType_PRICE_TYPE_FIXED, adspb.PriceType_PRICE_TYPE_FREE, adspb.PriceType_PRICE_TYPE_ON_REQUEST:
+	default:
+		return nil, fmt.Errorf("%w: %d", domain.ErrInvalidPriceType, msg.GetType())
+	}
+	return price, nil
+}
+
+func intsToProto(values []int) []int32 {
+	if values == nil {
+		return nil
+	}
+	converted := make([]int32, len(values))
+	for i, value := range values {
+		converted[i] = int32(value)
+	}
+	return converted
+}
+
+func intsFromProto(values []int32) []int {
+	if len(values) == 0 {
+		return nil
+	}
+	converted := make([]int, len(values))
+	for i, value := range values {
+		converted[i] = int(value)
+	}
+	return converted
+}
+
+func uintToProto(value *uint) *uint32 {
+	if value == nil {
+		return nil
+	}
+	converted := uint32(*value)
+	return &converted
+}
+
+func uintFromProto(value *uint32) *uint {
+	if value == nil {
+		return nil
+	}
+	converted := uint(*value)
+	return &converted
+}
+
+// timeToProto converts a timestamp, leaving zero times unset
+func timeToProto(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func timeFromProto(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
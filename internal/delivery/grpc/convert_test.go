@@ -0,0 +1,257 @@
+package grpc
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/1way-market/v3/internal/delivery/grpc/adspb"
+	"github.com/1way-market/v3/internal/domain"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func uintPtr(v uint) *uint { return &v }
+
+func uint32Ptr(v uint32) *uint32 { return &v }
+
+func float64Ptr(v float64) *float64 { return &v }
+
+// newFullAd returns an ad with every field the messages carry set
+func newFullAd() *domain.Ad {
+	expiresAt := time.Date(2026, 11, 14, 12, 0, 0, 500, time.UTC)
+	return &domain.Ad{
+		ID: 42,
+		Title: domain.MultiLangArray{
+			{Lang: domain.LangRussian, Text: "Красный велосипед"},
+			{Lang: domain.LangEnglish, Text: "Red bicycle"},
+		},
+		Description: domain.MultiLangArray{{Lang: domain.LangTurkish, Text: "Kırmızı bisiklet, az kullanılmış"}},
+		Properties: domain.AdProperties{
+			{ID: 1, Value: "28\""},
+			{ID: 2, ValueID: uintPtr(15)},
+			{ID: 3, Value: "алюминий", ValueID: uintPtr(0)},
+			{ID: 4},
+		},
+		CategoryIDs:  []int{1, 5, 12},
+		Status:       domain.StatusActive,
+		StatusReason: "approved by moderator",
+		Price:        &domain.Price{Value: 1500.5, Currency: "TRY", Type: domain.PriceFixed},
+		SellerID:     uintPtr(7),
+		Slug:         "red-bicycle-42",
+		PhoneMasked:  "+90 *** *** 12 34",
+		ViewCount:    1234,
+		Version:      3,
+		ExpiresAt:    &expiresAt,
+		CreatedAt:    time.Date(2026, 10, 15, 8, 30, 0, 0, time.UTC),
+		UpdatedAt:    time.Date(2026, 10, 15, 9, 45, 0, 0, time.UTC),
+	}
+}
+
+func TestAdRoundTrip(t *testing.T) {
+	free := newFullAd()
+	free.Price = &domain.Price{Currency: "TRY", Type: domain.PriceFree}
+	onRequest := newFullAd()
+	onRequest.Price = &domain.Price{Type: domain.PriceOnRequest}
+
+	tests := []struct {
+		name string
+		ad   *domain.Ad
+	}{
+		{"every field", newFullAd()},
+		{"free", free},
+		{"price on request", onRequest},
+		{"only a title", &domain.Ad{Title: domain.MultiLangArray{{Lang: domain.LangEnglish, Text: "Red bicycle"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := adToProto(tt.ad)
+
+			// Through the wire format, as the client sends it back
+			data, err := proto.Marshal(msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decoded adspb.Ad
+			if err := proto.Unmarshal(data, &decoded); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := adFromProto(&decoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.ad) {
+				t.Errorf("round trip = %+v\nwant %+v", got, tt.ad)
+			}
+		})
+	}
+}
+
+func TestAdMessageRoundTrip(t *testing.T) {
+	msg := adToProto(newFullAd())
+	ad, err := adFromProto(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := adToProto(ad); !proto.Equal(got, msg) {
+		t.Errorf("round trip = %v\nwant %v", got, msg)
+	}
+}
+
+func TestPropertiesJSONBRoundTrip(t *testing.T) {
+	msgs := []*adspb.AdProperty{
+		{Id: 1, Value: "28\""},
+		{Id: 2, ValueId: uint32Ptr(15)},
+		// A value ID of 0 is kept apart from no value ID
+		{Id: 3, Value: "алюминий", ValueId: uint32Ptr(0)},
+		{Id: 4, Value: `{"nested": ["json", 1]}`},
+		{Id: 5},
+	}
+
+	// Store the properties as the JSONB column and read them back
+	value, err := propertiesFromProto(msgs).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stored domain.AdProperties
+	if err := stored.Scan(value); err != nil {
+		t.Fatal(err)
+	}
+
+	got := propertiesToProto(stored)
+	if len(got) != len(msgs) {
+		t.Fatalf("%d properties after the round trip, want %d", len(got), len(msgs))
+	}
+	for i := range msgs {
+		if !proto.Equal(got[i], msgs[i]) {
+			t.Errorf("property %d = %v, want %v", i, got[i], msgs[i])
+		}
+	}
+}
+
+func TestStatusRoundTrip(t *testing.T) {
+	for status := domain.StatusDraft; status <= domain.StatusDuplicate; status++ {
+		msg := statusToProto(status)
+		if want := "AD_STATUS_" + map[domain.AdStatus]string{
+			domain.StatusDraft: "DRAFT", domain.StatusPending: "PENDING", domain.StatusFromParser: "FROM_PARSER",
+			domain.StatusActive: "ACTIVE", domain.StatusCompleted: "COMPLETED", domain.StatusRejected: "REJECTED",
+			domain.StatusApproved: "APPROVED", domain.StatusUnknown: "UNKNOWN", domain.StatusDuplicate: "DUPLICATE",
+		}[status]; msg.String() != want {
+			t.Errorf("status %v = %v, want %s", status, msg, want)
+		}
+		if got, err := statusFromProto(msg); err != nil || got != status {
+			t.Errorf("status %v round trip = %v, %v", status, got, err)
+		}
+	}
+
+	if got, err := statusFromProto(adspb.AdStatus_AD_STATUS_UNSPECIFIED); err != nil || got != domain.StatusDraft {
+		t.Errorf("unset status = %v, %v, want a draft", got, err)
+	}
+	if _, err := statusFromProto(adspb.AdStatus(42)); err == nil {
+		t.Error("unknown status accepted")
+	}
+}
+
+func TestAdFromProtoRejectsInvalidValues(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  *adspb.Ad
+	}{
+		{"unknown language", &adspb.Ad{Title: []*adspb.MultiLangText{{Lang: "xx", Text: "Red bicycle"}}}},
+		{"unknown status", &adspb.Ad{Status: adspb.AdStatus(42)}},
+		{"unknown price type", &adspb.Ad{Price: &adspb.Price{Value: 10, Type: adspb.PriceType(42)}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ad, err := adFromProto(tt.msg); err == nil {
+				t.Errorf("converted to %+v, want an error", ad)
+			}
+		})
+	}
+}
+
+func TestPriceWithoutTypeIsFixed(t *testing.T) {
+	price, err := priceFromProto(&adspb.Price{Value: 10, Currency: "USD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (domain.Price{Value: 10, Currency: "USD", Type: domain.PriceFixed}); *price != want {
+		t.Errorf("price = %+v, want %+v", *price, want)
+	}
+}
+
+func TestTimestampsRoundTrip(t *testing.T) {
+	msg := &adspb.Ad{
+		ExpiresAt: timestamppb.New(time.Date(2026, 11, 14, 12, 0, 0, 0, time.UTC)),
+		CreatedAt: timestamppb.New(time.Date(2026, 10, 15, 8, 30, 0, 999, time.UTC)),
+	}
+	ad, err := adFromProto(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ad.UpdatedAt.IsZero() {
+		t.Errorf("unset updated_at = %v, want the zero time", ad.UpdatedAt)
+	}
+	if got := adToProto(ad); !proto.Equal(got.ExpiresAt, msg.ExpiresAt) || !proto.Equal(got.CreatedAt, msg.CreatedAt) || got.UpdatedAt != nil {
+		t.Errorf("timestamps = %v, %v, %v", got.ExpiresAt, got.CreatedAt, got.UpdatedAt)
+	}
+}
+
+func TestFilterFromProto(t *testing.T) {
+	req := &adspb.GetAdsRequest{
+		CategoryIds: []int32{1, 5},
+		PropertyFilters: []*adspb.PropertyFilter{
+			{PropertyId: 1, Values: []string{"28\""}},
+			{PropertyId: 2, ValueIds: []uint32{15, 16}},
+		},
+		Q:                     "велосипед",
+		Sort:                  "price_asc",
+		NextPage:              "token",
+		PageSize:              50,
+		Lang:                  "RU",
+		MinPrice:              float64Ptr(0),
+		MaxPrice:              float64Ptr(2000),
+		Currency:              "TRY",
+		Status:                adspb.AdStatus_AD_STATUS_ACTIVE,
+		SellerId:              uint32Ptr(7),
+		ExcludePriceOnRequest: true,
+		IncludeExpired:        true,
+	}
+	status := domain.StatusActive
+	want := domain.FilterRequest{
+		CategoryIDs: []int{1, 5},
+		PropertyFilters: []domain.PropertyFilter{
+			{PropertyID: 1, Values: []string{"28\""}},
+			{PropertyID: 2, ValueIDs: []uint{15, 16}},
+		},
+		TextSearch:            "велосипед",
+		SortBy:                "price_asc",
+		PageToken:             "token",
+		PageSize:              50,
+		Lang:                  "ru",
+		Language:              domain.LangRussian,
+		MinPrice:              float64Ptr(0),
+		MaxPrice:              float64Ptr(2000),
+		Currency:              "TRY",
+		Status:                &status,
+		SellerID:              uintPtr(7),
+		ExcludePriceOnRequest: true,
+		IncludeExpired:        true,
+	}
+
+	got, err := filterFromProto(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("filter = %+v\nwant %+v", got, want)
+	}
+
+	if got, err := filterFromProto(&adspb.GetAdsRequest{Lang: "en"}); err != nil || got.Status != nil || got.MinPrice != nil {
+		t.Errorf("filter without status and prices = %+v, %v", got, err)
+	}
+	if _, err := filterFromProto(&adspb.GetAdsRequest{}); err == nil {
+		t.Error("filter without a language accepted")
+	}
+}
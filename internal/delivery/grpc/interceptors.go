@@ -0,0 +1,82 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/1way-market/v3/internal/auth"
+	"github.com/1way-market/v3/internal/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// recoverPanics turns a panicking call into an Internal error instead of
+// taking the process down, like gin.Recovery for the HTTP API
+func recoverPanics(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Error: %s panicked: %v\n%s", info.FullMethod, r, debug.Stack())
+			err = status.Error(codes.Internal, "internal error")
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// defaultDeadline gives calls that come without a deadline one timeout from
+// now. The client's deadline, or this one, is carried by the context down to
+// the repositories.
+func defaultDeadline(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if _, ok := ctx.Deadline(); !ok && timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return handler(ctx, req)
+	}
+}
+
+// identity stores the caller's principal in the context, from a bearer token
+// in the authorization metadata verified as by the HTTP Identity middleware.
+// Calls without a token are anonymous; invalid or expired tokens are rejected.
+func identity(verifier *auth.Verifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		token, ok := bearerToken(ctx)
+		if !ok {
+			return handler(ctx, req)
+		}
+		if verifier == nil {
+			return nil, status.Error(codes.Unauthenticated, "token authentication is not configured")
+		}
+		claims, err := verifier.Verify(ctx, token)
+		if err != nil {
+			if errors.Is(err, auth.ErrInvalidToken) || errors.Is(err, auth.ErrExpiredToken) {
+				return nil, status.Error(codes.Unauthenticated, err.Error())
+			}
+			// The signing keys could not be fetched
+			log.Printf("Warning: token verification failed: %v", err)
+			return nil, status.Error(codes.Unavailable, "token verification unavailable")
+		}
+		principal := &domain.Principal{UserID: claims.Subject, SellerID: claims.SellerID, Roles: claims.Roles}
+		return handler(domain.WithPrincipal(ctx, principal), req)
+	}
+}
+
+// bearerToken returns the token of a "Bearer" authorization metadata value
+func bearerToken(ctx context.Context) (string, bool) {
+	values := metadata.ValueFromIncomingContext(ctx, "authorization")
+	if len(values) == 0 {
+		return "", false
+	}
+	scheme, token, ok := strings.Cut(values[0], " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
@@ -0,0 +1,180 @@
+// Package grpc serves the ads API to internal consumers over gRPC, with the
+// contract in api/proto/ads/v3/ads.proto. It delegates to the same AdUseCase
+// as the HTTP API, so both apply the same validation and authorization.
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/1way-market/v3/internal/auth"
+	"github.com/1way-market/v3/internal/config"
+	"github.com/1way-market/v3/internal/delivery/grpc/adspb"
+	"github.com/1way-market/v3/internal/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type AdUseCase interface {
+	GetAds(ctx context.Context, filter domain.FilterRequest) (*domain.PaginatedResponse, error)
+	GetAd(ctx context.Context, id uint) (*domain.Ad, error)
+	CreateAd(ctx context.Context, ad *domain.Ad) error
+	CreateAdIdempotent(ctx context.Context, key string, ad *domain.Ad) (*domain.Ad, bool, error)
+	UpdateAd(ctx context.Context, ad *domain.Ad) error
+	DeleteAd(ctx context.Context, id uint) error
+}
+
+// NewServer returns a gRPC server of the ads API. Callers are identified by
+// their bearer tokens, verified against JWT_JWKS_URL as for the HTTP API, and
+// calls without a deadline get GRPC_REQUEST_TIMEOUT; the deadline bounds the
+// database and cache calls made for them.
+func NewServer(cfg *config.Config, useCase AdUseCase) *grpc.Server {
+	verifier := auth.NewJWKSVerifier(cfg.JWT.JWKSURL, cfg.JWT.Issuer, cfg.JWT.Audience)
+	return newServer(useCase, verifier, cfg.GRPCRequestTimeout)
+}
+
+func newServer(useCase AdUseCase, verifier *auth.Verifier, requestTimeout time.Duration) *grpc.Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		recoverPanics,
+		defaultDeadline(requestTimeout),
+		identity(verifier),
+	))
+	adspb.RegisterAdsServiceServer(server, NewAdsServer(useCase))
+	return server
+}
+
+// AdsServer implements adspb.AdsServiceServer over an AdUseCase
+type AdsServer struct {
+	adspb.UnimplementedAdsServiceServer
+	useCase AdUseCase
+}
+
+func NewAdsServer(useCase AdUseCase) *AdsServer {
+	return &AdsServer{useCase: useCase}
+}
+
+func (s *AdsServer) GetAds(ctx context.Context, req *adspb.GetAdsRequest) (*adspb.GetAdsResponse, error) {
+	filter, err := filterFromProto(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	page, err := s.useCase.GetAds(ctx, filter)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return pageToProto(page), nil
+}
+
+func (s *AdsServer) GetAd(ctx context.Context, req *adspb.GetAdRequest) (*adspb.Ad, error) {
+	ad, err := s.useCase.GetAd(ctx, uint(req.GetId()))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return adToProto(ad), nil
+}
+
+// CreateAd creates an ad for a seller or parser, like POST /v3/ads
+func (s *AdsServer) CreateAd(ctx context.Context, req *adspb.CreateAdRequest) (*adspb.Ad, error) {
+	if err := requireRole(ctx, domain.RoleSeller, domain.RoleParser); err != nil {
+		return nil, err
+	}
+	ad, err := adFromRequest(req.GetAd())
+	if err != nil {
+		return nil, err
+	}
+	ad.ID = 0
+
+	if key := req.GetIdempotencyKey(); key != "" {
+		created, _, err := s.useCase.CreateAdIdempotent(ctx, key, ad)
+		if err != nil {
+			return nil, statusError(err)
+		}
+		return adToProto(created), nil
+	}
+
+	if err := s.useCase.CreateAd(ctx, ad); err != nil {
+		return nil, statusError(err)
+	}
+	return adToProto(ad), nil
+}
+
+func (s *AdsServer) UpdateAd(ctx context.Context, req *adspb.UpdateAdRequest) (*adspb.Ad, error) {
+	if err := requireRole(ctx); err != nil {
+		return nil, err
+	}
+	ad, err := adFromRequest(req.GetAd())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.useCase.UpdateAd(ctx, ad); err != nil {
+		return nil, statusError(err)
+	}
+	return adToProto(ad), nil
+}
+
+func (s *AdsServer) DeleteAd(ctx context.Context, req *adspb.DeleteAdRequest) (*adspb.DeleteAdResponse, error) {
+	if err := requireRole(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.useCase.DeleteAd(ctx, uint(req.GetId())); err != nil {
+		return nil, statusError(err)
+	}
+	return &adspb.DeleteAdResponse{}, nil
+}
+
+// adFromRequest converts the ad of a create or update request
+func adFromRequest(msg *adspb.Ad) (*domain.Ad, error) {
+	if msg == nil {
+		return nil, status.Error(codes.InvalidArgument, "ad is required")
+	}
+	ad, err := adFromProto(msg)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return ad, nil
+}
+
+// requireRole rejects anonymous callers and, given roles, callers without any of them
+func requireRole(ctx context.Context, roles ...string) error {
+	principal := domain.PrincipalFromContext(ctx)
+	if principal == nil {
+		return status.Error(codes.Unauthenticated, "authentication required")
+	}
+	if len(roles) > 0 && !principal.HasAnyRole(roles...) {
+		return status.Error(codes.PermissionDenied, "insufficient role")
+	}
+	return nil
+}
+
+// statusError maps use case errors to gRPC statuses, as writeAdError maps
+// them to HTTP responses
+func statusError(err error) error {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr), errors.As(err, new(*domain.PageSizeError)),
+		errors.Is(err, domain.ErrInvalidCurrency), errors.Is(err, domain.ErrInvalidPhone), errors.Is(err, domain.ErrConstraint),
+		errors.Is(err, domain.ErrCurrencyNotAllowed), errors.Is(err, domain.ErrInvalidSort), errors.Is(err, domain.ErrRelevanceSortRequiresSearch),
+		errors.Is(err, domain.ErrInvalidPageToken):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, domain.ErrConflict), errors.Is(err, domain.ErrRequestInProgress):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, domain.ErrDuplicate):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, domain.ErrIdempotencyUnavailable):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,225 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/1way-market/v3/internal/auth"
+	"github.com/1way-market/v3/internal/auth/authtest"
+	"github.com/1way-market/v3/internal/delivery/grpc/adspb"
+	"github.com/1way-market/v3/internal/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeAdUseCase records the context of the calls it serves; methods it does
+// not override panic through the nil interface
+type fakeAdUseCase struct {
+	AdUseCase
+	ctx context.Context
+	ad  *domain.Ad
+	err error
+}
+
+func (f *fakeAdUseCase) GetAd(ctx context.Context, id uint) (*domain.Ad, error) {
+	f.ctx = ctx
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &domain.Ad{ID: id, Status: domain.StatusActive}, nil
+}
+
+func (f *fakeAdUseCase) CreateAd(ctx context.Context, ad *domain.Ad) error {
+	f.ctx = ctx
+	f.ad = ad
+	ad.ID = 1
+	return f.err
+}
+
+func (f *fakeAdUseCase) DeleteAd(ctx context.Context, id uint) error {
+	f.ctx = ctx
+	return f.err
+}
+
+// newTestClient serves the use case over an in-memory connection
+func newTestClient(t *testing.T, useCase AdUseCase, verifier *auth.Verifier, requestTimeout time.Duration) adspb.AdsServiceClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := newServer(useCase, verifier, requestTimeout)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return adspb.NewAdsServiceClient(conn)
+}
+
+func withToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+func TestClientDeadlineReachesUseCase(t *testing.T) {
+	useCase := &fakeAdUseCase{}
+	client := newTestClient(t, useCase, nil, time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	want, _ := ctx.Deadline()
+	if _, err := client.GetAd(ctx, &adspb.GetAdRequest{Id: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, ok := useCase.ctx.Deadline()
+	if !ok {
+		t.Fatal("use case called without a deadline")
+	}
+	// The deadline travels as a timeout, so it moves by the transport's latency
+	if diff := got.Sub(want); diff < -time.Second || diff > time.Second {
+		t.Errorf("deadline = %v, want about %v", got, want)
+	}
+}
+
+func TestDefaultDeadline(t *testing.T) {
+	useCase := &fakeAdUseCase{}
+	client := newTestClient(t, useCase, nil, 3*time.Second)
+
+	start := time.Now()
+	if _, err := client.GetAd(context.Background(), &adspb.GetAdRequest{Id: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, ok := useCase.ctx.Deadline()
+	if !ok {
+		t.Fatal("use case called without a deadline")
+	}
+	if remaining := got.Sub(start); remaining < 2*time.Second || remaining > 4*time.Second {
+		t.Errorf("deadline %v after the call, want about 3s", remaining)
+	}
+}
+
+func TestCallerIdentity(t *testing.T) {
+	id := authtest.New(t)
+	expired := authtest.Claims("user-1", domain.RoleSeller)
+	expired["exp"] = time.Now().Add(-time.Minute).Unix()
+
+	tests := []struct {
+		name     string
+		token    string
+		call     func(adspb.AdsServiceClient, context.Context) error
+		wantCode codes.Code
+	}{
+		{"anonymous read", "", getAd, codes.OK},
+		{"anonymous delete", "", deleteAd, codes.Unauthenticated},
+		{"invalid token", "not-a-token", getAd, codes.Unauthenticated},
+		{"expired token", id.Sign(t, expired), getAd, codes.Unauthenticated},
+		{"user delete", id.Token(t, "user-1"), deleteAd, codes.OK},
+		{"user create", id.Token(t, "user-1"), createAd, codes.PermissionDenied},
+		{"seller create", id.Token(t, "user-1", domain.RoleSeller), createAd, codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useCase := &fakeAdUseCase{}
+			client := newTestClient(t, useCase, id.Verifier(), time.Minute)
+
+			ctx := context.Background()
+			if tt.token != "" {
+				ctx = withToken(ctx, tt.token)
+			}
+			err := tt.call(client, ctx)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v (%v), want %v", code, err, tt.wantCode)
+			}
+			if tt.wantCode == codes.OK && tt.token != "" {
+				if principal := domain.PrincipalFromContext(useCase.ctx); principal == nil || principal.UserID != "user-1" {
+					t.Errorf("principal = %+v, want user-1", principal)
+				}
+			}
+		})
+	}
+}
+
+func getAd(client adspb.AdsServiceClient, ctx context.Context) error {
+	_, err := client.GetAd(ctx, &adspb.GetAdRequest{Id: 1})
+	return err
+}
+
+func deleteAd(client adspb.AdsServiceClient, ctx context.Context) error {
+	_, err := client.DeleteAd(ctx, &adspb.DeleteAdRequest{Id: 1})
+	return err
+}
+
+func createAd(client adspb.AdsServiceClient, ctx context.Context) error {
+	_, err := client.CreateAd(ctx, &adspb.CreateAdRequest{Ad: &adspb.Ad{
+		Title: []*adspb.MultiLangText{{Lang: "en", Text: "Red bicycle"}},
+	}})
+	return err
+}
+
+func TestCreateAdDelegatesToUseCase(t *testing.T) {
+	id := authtest.New(t)
+	useCase := &fakeAdUseCase{}
+	client := newTestClient(t, useCase, id.Verifier(), time.Minute)
+
+	ctx := withToken(context.Background(), id.Token(t, "user-1", domain.RoleSeller))
+	got, err := client.CreateAd(ctx, &adspb.CreateAdRequest{Ad: &adspb.Ad{
+		Id:         99,
+		Title:      []*adspb.MultiLangText{{Lang: "en", Text: "Red bicycle"}},
+		Properties: []*adspb.AdProperty{{Id: 3, ValueId: uint32Ptr(0)}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GetId() != 1 {
+		t.Errorf("ID = %d, want the one given by the use case", got.GetId())
+	}
+	if property := useCase.ad.Properties[0]; property.ValueID == nil || *property.ValueID != 0 {
+		t.Errorf("property = %+v, want value ID 0", property)
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want codes.Code
+	}{
+		{&domain.ValidationError{Fields: []domain.FieldError{{Field: "title"}}}, codes.InvalidArgument},
+		{&domain.PageSizeError{Max: 100}, codes.InvalidArgument},
+		{domain.ErrInvalidCurrency, codes.InvalidArgument},
+		{domain.ErrInvalidPageToken, codes.InvalidArgument},
+		{domain.ErrNotFound, codes.NotFound},
+		{domain.ErrForbidden, codes.PermissionDenied},
+		{domain.ErrConflict, codes.Aborted},
+		{domain.ErrRequestInProgress, codes.Aborted},
+		{domain.ErrDuplicate, codes.AlreadyExists},
+		{domain.ErrIdempotencyUnavailable, codes.Unavailable},
+		{context.DeadlineExceeded, codes.DeadlineExceeded},
+		{context.Canceled, codes.Canceled},
+		{errors.New("error getting ad: connection refused"), codes.Internal},
+	}
+	for _, tt := range tests {
+		if got := status.Code(statusError(tt.err)); got != tt.want {
+			t.Errorf("statusError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestUseCaseErrorsReachClient(t *testing.T) {
+	useCase := &fakeAdUseCase{err: domain.ErrNotFound}
+	client := newTestClient(t, useCase, nil, time.Minute)
+
+	if _, err := client.GetAd(context.Background(), &adspb.GetAdRequest{Id: 1}); status.Code(err) != codes.NotFound {
+		t.Errorf("error = %v, want NotFound", err)
+	}
+}
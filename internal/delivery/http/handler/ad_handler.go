@@ -1,9 +1,20 @@
 package handler
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/1way-market/v3/internal/domain"
 	"github.com/gin-gonic/gin"
@@ -11,80 +22,903 @@ import (
 
 type AdUseCase interface {
 	GetAds(ctx context.Context, filter domain.FilterRequest) (*domain.PaginatedResponse, error)
+	GetMyAds(ctx context.Context, filter domain.FilterRequest) (*domain.PaginatedResponse, error)
+	LocalizeAds(ctx context.Context, response *domain.PaginatedResponse, lang domain.Language) (*domain.LocalizedPaginatedResponse, error)
+	AdsExist(ctx context.Context, filter domain.FilterRequest) (bool, error)
+	ExplainAds(ctx context.Context, filter domain.FilterRequest) (*domain.QueryPlan, error)
+	GetSimilarAds(ctx context.Context, id uint, limit int) ([]domain.Ad, error)
+	ExportAds(ctx context.Context, filter domain.FilterRequest, fn func(*domain.Ad) error) error
+	GetAdChanges(ctx context.Context, since string, limit int) (*domain.AdChangesPage, error)
 	CreateAd(ctx context.Context, ad *domain.Ad) error
+	ImportAds(ctx context.Context, ads []*domain.Ad) []error
+	CreateAdIdempotent(ctx context.Context, key string, ad *domain.Ad) (*domain.Ad, bool, error)
 	UpdateAd(ctx context.Context, ad *domain.Ad) error
 	DeleteAd(ctx context.Context, id uint) error
+	GetAd(ctx context.Context, id uint) (*domain.Ad, error)
+	GetAdBySlug(ctx context.Context, slug string) (*domain.Ad, error)
+	RecordView(ctx context.Context, id uint)
+	GetFavoriteCount(ctx context.Context, adID uint) (int64, error)
+	GetFavorited(ctx context.Context, adIDs []uint) (map[uint]bool, error)
+	GetAdRawSource(ctx context.Context, id uint) (domain.RawSource, error)
+	RevealPhone(ctx context.Context, id uint) (string, error)
+	GetAdStats(ctx context.Context, id uint) (*domain.AdStats, error)
+	GetAdsStats(ctx context.Context, days int) (*domain.AdsStats, error)
+	GetSuggestions(ctx context.Context, prefix string, lang domain.Language, limit int) ([]domain.Suggestion, error)
+	ReportAd(ctx context.Context, report *domain.AdReport) error
+	ListReports(ctx context.Context, pageSize int, pageToken string) (*domain.AdReportPage, error)
+	AddImage(ctx context.Context, adID uint, data []byte) (*domain.Image, error)
+	DeleteImage(ctx context.Context, adID uint, key string) error
+	RenewAd(ctx context.Context, id uint) (*domain.Ad, error)
+	GetDuplicates(ctx context.Context, id uint) (*domain.AdDuplicates, error)
+	ClearDuplicate(ctx context.Context, id uint) (*domain.Ad, error)
+	StartSearchVectorRebuild(ctx context.Context, batchSize int) (*domain.Job, error)
+	GetJob(ctx context.Context, id string) (*domain.Job, error)
+	ReorderImages(ctx context.Context, adID uint, keys []string) (domain.Images, error)
 }
 
+// createAdRequest is an ad plus the raw payload the parser built it from
+type createAdRequest struct {
+	domain.Ad
+	RawSource domain.RawSource `json:"raw_source"`
+}
+
+const (
+	// CacheStatusHeader marks responses served from an expired cache entry
+	CacheStatusHeader = "X-Cache-Status"
+	// CacheHeader reports whether the response came from the cache: HIT, MISS or BYPASS
+	CacheHeader = "X-Cache"
+)
+
 type AdHandler struct {
 	useCase AdUseCase
+	// maxImportItems caps the lines of an import; 0 disables the limit
+	maxImportItems int
 }
 
-func NewAdHandler(useCase AdUseCase) *AdHandler {
-	return &AdHandler{useCase: useCase}
+func NewAdHandler(useCase AdUseCase, maxImportItems int) *AdHandler {
+	return &AdHandler{useCase: useCase, maxImportItems: maxImportItems}
 }
 
 // @Summary Get filtered ads
-// @Description Get a paginated list of ads with filters
+// @Description Get a paginated list of ads with filters. Authenticated callers also get whether they favorited each ad, in is_favorited.
 // @Tags ads
 // @Accept json
 // @Produce json
 // @Param categories query []int false "Category IDs"
-// @Param properties query object false "Dynamic properties filter"
+// @Param properties query []string false "Property filters as JSON objects, e.g. {\"property_id\":5,\"values\":[\"red\"]} or {\"property_id\":7,\"value_ids\":[3,4]}"
 // @Param q query string false "Text search"
-// @Param sort query string false "Sort order (price_asc, price_desc, date_desc)"
+// @Param sort query string false "Sort order (price_asc, price_desc, date_desc, date_asc, or relevance with q)"
 // @Param next_page query string false "Page token for pagination"
-// @Param page_size query int false "Number of items per page"
-// @Param lang query string true "Language code (e.g., 'ru', 'en')"
+// @Param page_size query int false "Number of items per page (default 20); capped at MAX_PAGE_SIZE, and rejected above ten times it"
+// @Param lang query string true "Language code (ru, en, tr)"
+// @Param lang_meta query bool false "With view=localized, add _lang_meta telling which language each field was served in"
+// @Param fields query string false "Comma-separated fields to return, e.g. id,title_multi,price,status,created_at"
+// @Param view query string false "Response view: full (default, all translations) or localized (title and description in the requested language)"
+// @Param currency query string false "Currency as ISO 4217 numeric or alphabetic code (e.g., '840', 'USD')"
+// @Param status query string false "Ad status name or code, e.g. active or 3; only DEFAULT_VISIBLE_STATUSES are listed to callers other than moderators"
+// @Param exclude_price_on_request query bool false "Exclude price-on-request ads; implied by min_price and max_price"
+// @Param include_expired query bool false "Include ads past their expires_at, which are left out by default"
+// @Param include_images query string false "Images listed per ad: primary (default) or all"
+// @Param status_format query string false "Status rendering: code (default, e.g. 3), name (e.g. \"active\") or object (e.g. {\"code\":3,\"name\":\"active\"})"
+// @Param refresh query bool false "Skip the cached result, like Cache-Control: no-cache; honored when cache bypass is allowed"
 // @Success 200 {object} domain.PaginatedResponse
+// @Success 200 {object} domain.LocalizedPaginatedResponse "With view=localized"
 // @Router /v3/ads [get]
 func (h *AdHandler) GetAds(c *gin.Context) {
+	h.listAds(c, h.useCase.GetAds, nil)
+}
+
+// @Summary Get my ads
+// @Description Get a paginated list of the caller's ads in every status, including expired ones. Takes the same filters as GET /v3/ads.
+// @Tags ads
+// @Produce json
+// @Param lang query string true "Language code (ru, en, tr)"
+// @Param status query string false "Ad status name or code, e.g. draft or 0"
+// @Param next_page query string false "Page token for pagination"
+// @Param page_size query int false "Number of items per page (default 20)"
+// @Success 200 {object} domain.PaginatedResponse
+// @Router /v3/my/ads [get]
+func (h *AdHandler) GetMyAds(c *gin.Context) {
+	h.listAds(c, h.useCase.GetMyAds, nil)
+}
+
+// @Summary Get ads as admin
+// @Description Get a paginated list of ads like GET /v3/ads, optionally restricted to the ads of one user
+// @Tags admin
+// @Produce json
+// @Param owner_id query string false "ID of the user who posted the ads"
+// @Param lang query string true "Language code (ru, en, tr)"
+// @Param status query string false "Ad status name or code, e.g. active or 3"
+// @Param next_page query string false "Page token for pagination"
+// @Param page_size query int false "Number of items per page (default 20)"
+// @Success 200 {object} domain.PaginatedResponse
+// @Router /v3/admin/ads [get]
+func (h *AdHandler) GetAdminAds(c *gin.Context) {
+	h.listAds(c, h.useCase.GetAds, func(filter *domain.FilterRequest) {
+		filter.OwnerID = c.Query("owner_id")
+		filter.AnyStatus = true
+	})
+}
+
+// listAds writes the page of ads list returns for the request filters, after
+// scope adjusted them
+func (h *AdHandler) listAds(c *gin.Context, list func(context.Context, domain.FilterRequest) (*domain.PaginatedResponse, error), scope func(*domain.FilterRequest)) {
+	filter, ok := bindFilter(c)
+	if !ok {
+		return
+	}
+	statusFormat, ok := bindStatusFormat(c)
+	if !ok {
+		return
+	}
+	if scope != nil {
+		scope(&filter)
+	}
+
+	response, err := list(c.Request.Context(), filter)
+	writeCacheHeader(c)
+	if err != nil {
+		if writeValidationError(c, http.StatusBadRequest, err) {
+			return
+		}
+		if isFilterError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if response.Stale {
+		c.Header(CacheStatusHeader, "stale")
+	}
+	writePaginationHeaders(c, response.PageSize, response.TotalCount, response.NextPage)
+
+	// The page may be shared with concurrent requests, so the caller's
+	// favorites are marked on a copy
+	page := *response
+	page.Items = slices.Clone(response.Items)
+	h.markFavorites(c, page.Items)
+	response = &page
+
+	if filter.View == domain.ViewLocalized {
+		localized, err := h.useCase.LocalizeAds(c.Request.Context(), response, filter.Language)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if !filter.LangMeta {
+			for i := range localized.Items {
+				localized.Items[i].LangMeta = nil
+			}
+		}
+		writeJSONWithStatusFormat(c, localized, statusFormat)
+		return
+	}
+	if len(filter.SelectedFields) > 0 {
+		projected, err := projectAds(response.Items, filter.SelectedFields)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		body := gin.H{
+			"items":       projected,
+			"next_page":   response.NextPage,
+			"total_count": response.TotalCount,
+		}
+		if len(response.Facets) > 0 {
+			body["facets"] = response.Facets
+		}
+		writeJSONWithStatusFormat(c, body, statusFormat)
+		return
+	}
+	writeJSONWithStatusFormat(c, response, statusFormat)
+}
+
+// writeCacheHeader reports the cache status set by the use case, if any
+func writeCacheHeader(c *gin.Context) {
+	if control := domain.CacheControlFromContext(c.Request.Context()); control != nil && control.Status != "" {
+		c.Header(CacheHeader, control.Status)
+	}
+}
+
+// writePaginationHeaders sets X-Total-Count, X-Page-Size and, when there is a
+// next page, a Link header pointing to it, so that clients need not parse the body
+func writePaginationHeaders(c *gin.Context, pageSize int, totalCount int64, nextPage string) {
+	if pageSize == 0 {
+		pageSize = domain.DefaultPageSize
+	}
+	c.Header("X-Total-Count", strconv.FormatInt(totalCount, 10))
+	c.Header("X-Page-Size", strconv.Itoa(pageSize))
+
+	if nextPage != "" {
+		next := *c.Request.URL
+		query := next.Query()
+		query.Set("next_page", nextPage)
+		next.RawQuery = query.Encode()
+		c.Header("Link", fmt.Sprintf(`<%s>; rel="next"`, next.RequestURI()))
+	}
+}
+
+// projectAds renders ads keeping only the given JSON fields
+func projectAds(ads []domain.Ad, fields []string) ([]map[string]json.RawMessage, error) {
+	projected := make([]map[string]json.RawMessage, 0, len(ads))
+	for _, ad := range ads {
+		data, err := json.Marshal(ad)
+		if err != nil {
+			return nil, err
+		}
+		var all map[string]json.RawMessage
+		if err := json.Unmarshal(data, &all); err != nil {
+			return nil, err
+		}
+
+		item := make(map[string]json.RawMessage, len(fields))
+		for _, field := range fields {
+			if value, ok := all[field]; ok {
+				item[field] = value
+			}
+		}
+		// The caller's favorite is not a field of the ad, so it is not selected
+		if value, ok := all["is_favorited"]; ok {
+			item["is_favorited"] = value
+		}
+		projected = append(projected, item)
+	}
+	return projected, nil
+}
+
+// markFavorites flags the ads the caller favorited, for authenticated callers.
+// The ads are changed in place. They are left unflagged when the favorites
+// cannot be read.
+func (h *AdHandler) markFavorites(c *gin.Context, ads []domain.Ad) {
+	ids := make([]uint, len(ads))
+	for i := range ads {
+		ids[i] = ads[i].ID
+	}
+	favorited, err := h.useCase.GetFavorited(c.Request.Context(), ids)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if favorited == nil {
+		return
+	}
+	for i := range ads {
+		isFavorited := favorited[ads[i].ID]
+		ads[i].IsFavorited = &isFavorited
+	}
+}
+
+// bindFilter binds the ad filter query parameters and resolves the language,
+// writing a 400 response and returning false when they are invalid
+func bindFilter(c *gin.Context) (domain.FilterRequest, bool) {
 	var filter domain.FilterRequest
 	if err := c.ShouldBindQuery(&filter); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return filter, false
+	}
+
+	lang, err := domain.ParseLanguage(filter.Lang)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"supported": domain.SupportedLanguageCodes(),
+		})
+		return filter, false
+	}
+	filter.Language = lang
+	filter.Lang = lang.Code()
+
+	if filter.RawStatus != "" {
+		status, err := domain.ParseAdStatus(filter.RawStatus)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return filter, false
+		}
+		filter.Status = &status
+	}
+
+	switch filter.View {
+	case "", domain.ViewFull, domain.ViewLocalized:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid view, expected full or localized"})
+		return filter, false
+	}
+
+	if filter.Fields != "" {
+		if filter.View == domain.ViewLocalized {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "fields cannot be combined with view=localized"})
+			return filter, false
+		}
+		fields, err := domain.ParseAdFields(filter.Fields)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return filter, false
+		}
+		filter.SelectedFields = fields
+	}
+
+	return filter, true
+}
+
+// @Summary Check whether ads exist
+// @Description Check whether any ad matches the filters without counting or fetching them
+// @Tags ads
+// @Produce json
+// @Param categories query []int false "Category IDs"
+// @Param q query string false "Text search"
+// @Param lang query string true "Language code (ru, en, tr)"
+// @Success 200 {object} map[string]bool
+// @Router /v3/ads/exists [get]
+func (h *AdHandler) AdsExist(c *gin.Context) {
+	filter, ok := bindFilter(c)
+	if !ok {
 		return
 	}
 
-	response, err := h.useCase.GetAds(c.Request.Context(), filter)
+	exists, err := h.useCase.AdsExist(c.Request.Context(), filter)
 	if err != nil {
+		if writeValidationError(c, http.StatusBadRequest, err) {
+			return
+		}
+		if isFilterError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, gin.H{"exists": exists})
+}
+
+// @Summary Explain ads query
+// @Description Run EXPLAIN ANALYZE on the ads listing query for the same parameters as GET /v3/ads. Not available in production unless enabled.
+// @Tags debug
+// @Produce json
+// @Param lang query string true "Language code (ru, en, tr)"
+// @Success 200 {object} domain.QueryPlan
+// @Router /debug/explain [get]
+func (h *AdHandler) ExplainAds(c *gin.Context) {
+	filter, ok := bindFilter(c)
+	if !ok {
+		return
+	}
+
+	plan, err := h.useCase.ExplainAds(c.Request.Context(), filter)
+	if err != nil {
+		if isFilterError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+const (
+	// defaultSimilarLimit is the number of similar ads returned by default
+	defaultSimilarLimit = 10
+	// maxSimilarLimit caps the limit parameter of the similar ads endpoint
+	maxSimilarLimit = 50
+	// defaultChangesLimit is the number of changes returned by default
+	defaultChangesLimit = 100
+	// maxChangesLimit caps the limit parameter of the ads change feed
+	maxChangesLimit = 1000
+	// defaultStatsDays is how many days the ads statistics histogram covers by default
+	defaultStatsDays = 30
+	// defaultSuggestLimit is the number of search suggestions returned by default
+	defaultSuggestLimit = 10
+	// maxSuggestLimit caps the limit parameter of the search suggestions endpoint
+	maxSuggestLimit = 20
+	// maxSuggestPrefix caps the length, in characters, of the typed prefix
+	maxSuggestPrefix = 100
+)
+
+// @Summary Get ad
+// @Description Get an advertisement by ID or by slug, e.g. red-bicycle-42. Authenticated callers also get whether they favorited it, in is_favorited. Ads that are not publicly visible, such as drafts, are only shown to the user who posted them, moderators and admins; anyone else gets 404.
+// @Tags ads
+// @Produce json
+// @Param id path string true "Advertisement ID or slug"
+// @Param status_format query string false "Status rendering: code (default), name or object"
+// @Param refresh query bool false "Skip the cached ad, like Cache-Control: no-cache; honored when cache bypass is allowed"
+// @Success 200 {object} domain.Ad
+// @Failure 404 {object} map[string]string
+// @Router /v3/ads/{id} [get]
+func (h *AdHandler) GetAd(c *gin.Context) {
+	statusFormat, ok := bindStatusFormat(c)
+	if !ok {
+		return
+	}
+
+	ad, err := h.getAdByParam(c)
+	if errors.Is(err, errInvalidAdParam) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	writeCacheHeader(c)
+	if err != nil {
+		writeAdError(c, err)
+		return
+	}
+	h.useCase.RecordView(c.Request.Context(), ad.ID)
+
+	// The favorite count and the caller's favorite change independently of
+	// the ad, which may be shared with concurrent requests, so they are set on
+	// a copy. The ad is served without them when they cannot be read.
+	views := []domain.Ad{*ad}
+	h.markFavorites(c, views)
+	view := views[0]
+	if count, err := h.useCase.GetFavoriteCount(c.Request.Context(), ad.ID); err == nil {
+		view.FavoriteCount = &count
+	} else {
+		c.Error(err)
+	}
+
+	// An ad changes only along with its updated_at, apart from its favorite
+	// count and the caller's favorite; the status format changes its representation
+	etag := fmt.Sprintf(`"%d-%d"`, ad.ID, ad.UpdatedAt.UnixMicro())
+	if view.FavoriteCount != nil {
+		etag = fmt.Sprintf(`"%d-%d-%d"`, ad.ID, ad.UpdatedAt.UnixMicro(), *view.FavoriteCount)
+	}
+	if view.IsFavorited != nil {
+		etag = strings.TrimSuffix(etag, `"`) + fmt.Sprintf("-%t", *view.IsFavorited) + `"`
+	}
+	if statusFormat != domain.StatusFormatCode {
+		etag = strings.TrimSuffix(etag, `"`) + "-" + string(statusFormat) + `"`
+	}
+	if notModified(c, etag) {
+		return
+	}
+
+	body, err := withStatusFormat(&view, statusFormat)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+// @Summary Check ad existence
+// @Description Check whether an advertisement exists by ID or slug without transferring it. Ads that are not publicly visible exist only for the user who posted them, moderators and admins.
+// @Tags ads
+// @Param id path string true "Advertisement ID or slug"
+// @Success 200 "The ad exists"
+// @Failure 404 "The ad does not exist"
+// @Router /v3/ads/{id} [head]
+func (h *AdHandler) HeadAd(c *gin.Context) {
+	_, err := h.getAdByParam(c)
+	writeCacheHeader(c)
+	switch {
+	case err == nil:
+		c.Status(http.StatusOK)
+	case errors.Is(err, errInvalidAdParam):
+		c.Status(http.StatusBadRequest)
+	case errors.Is(err, domain.ErrNotFound):
+		c.Status(http.StatusNotFound)
+	default:
+		c.Status(http.StatusInternalServerError)
+	}
+}
+
+// errInvalidAdParam is returned for an id parameter that is neither an ID nor a slug
+var errInvalidAdParam = errors.New("invalid id")
+
+// getAdByParam returns the ad named by the id parameter, which is an ID or a slug
+func (h *AdHandler) getAdByParam(c *gin.Context) (*domain.Ad, error) {
+	param := c.Param("id")
+	if strings.Contains(param, "-") {
+		return h.useCase.GetAdBySlug(c.Request.Context(), param)
+	}
+
+	id, err := strconv.ParseUint(param, 10, 32)
+	if err != nil {
+		return nil, errInvalidAdParam
+	}
+	return h.useCase.GetAd(c.Request.Context(), uint(id))
+}
+
+// @Summary Get similar ads
+// @Description Get active ads sharing a category with the ad, in a similar price range, ranked by text similarity
+// @Tags ads
+// @Produce json
+// @Param id path int true "Advertisement ID"
+// @Param limit query int false "Maximum number of ads (default 10, max 50)"
+// @Success 200 {object} map[string][]domain.Ad
+// @Router /v3/ads/{id}/similar [get]
+func (h *AdHandler) GetSimilarAds(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	limit := defaultSimilarLimit
+	if value := c.Query("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > maxSimilarLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", maxSimilarLimit)})
+			return
+		}
+	}
+
+	ads, err := h.useCase.GetSimilarAds(c.Request.Context(), uint(id), limit)
+	if err != nil {
+		writeAdError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": ads})
+}
+
+// exportFlushRows is how many exported ads are buffered before flushing to the client
+const exportFlushRows = 500
+
+const (
+	// exportFormatNDJSON exports one ad JSON object per line, with every translation
+	exportFormatNDJSON = "ndjson"
+	// exportFormatCSV exports one row per ad, with texts in the requested language
+	exportFormatCSV = "csv"
+)
+
+// exportHeader is the header row of the ads CSV export
+var exportHeader = []string{"id", "title", "description", "category_ids", "status", "price", "currency", "created_at", "updated_at", "properties"}
+
+// @Summary Get ad changes
+// @Description Get the ads created, updated or deleted after a cursor, for systems mirroring the ads. Changed ads are returned in full as they are now, deleted ads as {"id":1,"deleted":true}, each ad at most once per page. Pass next as since to resume; no change is missed or repeated across pages, even when many share a timestamp. Changes appear once every transaction started before them has ended. Without since, the feed starts with every existing ad.
+// @Tags admin
+// @Produce json
+// @Param since query string false "Cursor from the next field of the previous response"
+// @Param limit query int false "Maximum number of changes (1-1000, default 100)"
+// @Success 200 {object} domain.AdChangesPage
+// @Router /v3/ads/changes [get]
+func (h *AdHandler) GetAdChanges(c *gin.Context) {
+	limit := defaultChangesLimit
+	if value := c.Query("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > maxChangesLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", maxChangesLimit)})
+			return
+		}
+	}
+
+	page, err := h.useCase.GetAdChanges(c.Request.Context(), c.Query("since"), limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidPageToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid since cursor"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// @Summary Export ads
+// @Description Stream every ad matching the filters, up to EXPORT_MAX_ROWS, as NDJSON or CSV. The export reads the database directly and bypasses the cache.
+// @Tags admin
+// @Produce application/x-ndjson
+// @Produce text/csv
+// @Param format query string false "Export format: ndjson (default) or csv"
+// @Param categories query []int false "Category IDs"
+// @Param q query string false "Text search"
+// @Param sort query string false "Sort order (price_asc, price_desc, date_desc, date_asc, or relevance with q)"
+// @Param lang query string true "Language of the CSV title and description (ru, en, tr)"
+// @Param status query string false "Ad status name or code, e.g. active or 3"
+// @Success 200 {string} string "NDJSON or CSV file"
+// @Router /v3/ads/export [get]
+func (h *AdHandler) Export(c *gin.Context) {
+	format := c.DefaultQuery("format", exportFormatNDJSON)
+	if format != exportFormatNDJSON && format != exportFormatCSV {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be ndjson or csv"})
+		return
+	}
+	h.export(c, format)
+}
+
+// @Summary Export ads as CSV
+// @Description Stream the ads matching the filters as a CSV file, up to EXPORT_MAX_ROWS rows
+// @Tags admin
+// @Produce text/csv
+// @Param categories query []int false "Category IDs"
+// @Param q query string false "Text search"
+// @Param sort query string false "Sort order (price_asc, price_desc, date_desc, date_asc, or relevance with q)"
+// @Param lang query string true "Language of the exported title and description (ru, en, tr)"
+// @Param status query string false "Ad status name or code, e.g. active or 3"
+// @Success 200 {string} string "CSV file"
+// @Router /v3/ads/export.csv [get]
+func (h *AdHandler) ExportCSV(c *gin.Context) {
+	h.export(c, exportFormatCSV)
+}
+
+// export streams the ads matching the request filters in the given format.
+// An aborted download cancels the request context, which stops the export.
+func (h *AdHandler) export(c *gin.Context, format string) {
+	filter, ok := bindFilter(c)
+	if !ok {
+		return
+	}
+
+	var (
+		contentType string
+		writeHeader func() error
+		writeAd     func(ad *domain.Ad) error
+		flush       func() error
+	)
+	switch format {
+	case exportFormatCSV:
+		writer := csv.NewWriter(c.Writer)
+		contentType = "text/csv; charset=utf-8"
+		writeHeader = func() error { return writer.Write(exportHeader) }
+		writeAd = func(ad *domain.Ad) error {
+			record, err := exportRecord(ad, filter.Language)
+			if err != nil {
+				return err
+			}
+			return writer.Write(record)
+		}
+		flush = func() error {
+			writer.Flush()
+			return writer.Error()
+		}
+	default:
+		writer := bufio.NewWriter(c.Writer)
+		encoder := json.NewEncoder(writer)
+		contentType = "application/x-ndjson"
+		writeHeader = func() error { return nil }
+		writeAd = func(ad *domain.Ad) error { return encoder.Encode(ad) }
+		flush = writer.Flush
+	}
+
+	rows := 0
+	// Headers are sent with the first row so that a failing query can still return an error status
+	start := func() error {
+		c.Header("Content-Type", contentType)
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="ads-%s.%s"`, time.Now().Format("2006-01-02"), format))
+		c.Header("Transfer-Encoding", "chunked")
+		c.Status(http.StatusOK)
+		return writeHeader()
+	}
+
+	err := h.useCase.ExportAds(c.Request.Context(), filter, func(ad *domain.Ad) error {
+		if rows == 0 {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		rows++
+
+		if err := writeAd(ad); err != nil {
+			return err
+		}
+		if rows%exportFlushRows == 0 {
+			if err := flush(); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		if rows == 0 {
+			writeAdError(c, err)
+			return
+		}
+		// The response is already streaming, so the error can only be logged
+		c.Error(err)
+		return
+	}
+
+	if rows == 0 {
+		if err := start(); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+	if err := flush(); err != nil {
+		c.Error(err)
+	}
+}
+
+// exportRecord renders an ad as a CSV row matching exportHeader, with its
+// properties as a JSON array
+func exportRecord(ad *domain.Ad, lang domain.Language) ([]string, error) {
+	categoryIDs := make([]string, len(ad.CategoryIDs))
+	for i, id := range ad.CategoryIDs {
+		categoryIDs[i] = strconv.Itoa(id)
+	}
+
+	var price, currency string
+	if ad.Price != nil && ad.Price.Type != domain.PriceOnRequest {
+		price = strconv.FormatFloat(ad.Price.Value, 'f', -1, 64)
+		currency = domain.Currency(ad.Price.Currency).Alpha()
+	}
+
+	var properties string
+	if len(ad.Properties) > 0 {
+		data, err := json.Marshal(ad.Properties)
+		if err != nil {
+			return nil, err
+		}
+		properties = string(data)
+	}
+
+	return []string{
+		strconv.FormatUint(uint64(ad.ID), 10),
+		ad.Title.GetText(lang),
+		ad.Description.GetText(lang),
+		strings.Join(categoryIDs, ","),
+		ad.Status.String(),
+		price,
+		currency,
+		ad.CreatedAt.Format(time.RFC3339),
+		ad.UpdatedAt.Format(time.RFC3339),
+		properties,
+	}, nil
 }
 
 // @Summary Create new ad
-// @Description Create a new advertisement
+// @Description Create a new advertisement. Only moderators may create ads in a status other than draft or pending; parsers may also use from_parser.
 // @Tags ads
 // @Accept json
 // @Produce json
-// @Param ad body domain.Ad true "Advertisement object"
+// @Param Idempotency-Key header string false "Key making retries of the same create safe"
+// @Param ad body domain.Ad true "Advertisement object, optionally with the parser's raw_source payload"
+// @Success 200 {object} domain.Ad "Replay of a previous request with the same Idempotency-Key"
 // @Success 201 {object} domain.Ad
+// @Header 200,201 {string} Location "URL path of the ad"
+// @Failure 403 {object} map[string]string
+// @Failure 503 {object} map[string]string "The Idempotency-Key cannot be checked; nothing was created"
 // @Router /v3/ads [post]
 func (h *AdHandler) CreateAd(c *gin.Context) {
-	var ad domain.Ad
-	if err := c.ShouldBindJSON(&ad); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req createAdRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+	ad := req.Ad
+	ad.RawSource = req.RawSource
+
+	if key := c.GetHeader("Idempotency-Key"); key != "" {
+		created, replayed, err := h.useCase.CreateAdIdempotent(c.Request.Context(), key, &ad)
+		if err != nil {
+			writeAdError(c, err)
+			return
+		}
+
+		status := http.StatusCreated
+		if replayed {
+			status = http.StatusOK
+		}
+		c.Header("Location", adLocation(created.ID))
+		c.JSON(status, created)
 		return
 	}
 
 	if err := h.useCase.CreateAd(c.Request.Context(), &ad); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeAdError(c, err)
 		return
 	}
 
+	c.Header("Location", adLocation(ad.ID))
 	c.JSON(http.StatusCreated, ad)
 }
 
+// importBatchSize is the number of ads created per import transaction
+const importBatchSize = 500
+
+// @Summary Import ads
+// @Description Create ads from an NDJSON body, one ad object per line as for POST /v3/ads, in transactions of 500 ads. A line that is not valid JSON, fails validation or is rejected by the database is reported and skipped; the other lines are still imported. The response has the created ID or the error of every non-empty line. The body is capped at MAX_IMPORT_SIZE bytes and MAX_IMPORT_ITEMS lines, and each client may run IMPORT_CONCURRENCY imports at a time.
+// @Tags ads
+// @Accept application/x-ndjson
+// @Produce json
+// @Param ads body string true "One ad object per line, optionally with the parser's raw_source payload"
+// @Success 207 {object} domain.ImportSummary
+// @Failure 403 {object} map[string]string
+// @Failure 413 {object} map[string]string "More than MAX_IMPORT_ITEMS lines; nothing is imported"
+// @Failure 429 {object} map[string]string "The client already runs IMPORT_CONCURRENCY imports"
+// @Router /v3/ads/import [post]
+func (h *AdHandler) ImportAds(c *gin.Context) {
+	summary := domain.ImportSummary{Results: []domain.ImportLineResult{}}
+
+	// The whole body is read before importing, so that an import over the
+	// item limit is rejected without creating any of its ads
+	var (
+		ads     []*domain.Ad
+		pending []int
+	)
+	reader := bufio.NewReader(c.Request.Body)
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			// A line cut short by the error is not imported
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				summary.Error = fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit)
+			} else {
+				summary.Error = err.Error()
+			}
+			break
+		}
+
+		if len(bytes.TrimSpace(data)) > 0 {
+			if h.maxImportItems > 0 && len(summary.Results) == h.maxImportItems {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+					"error": fmt.Sprintf("import exceeds %d ads", h.maxImportItems),
+				})
+				return
+			}
+			summary.Results = append(summary.Results, domain.ImportLineResult{Line: line})
+			var req createAdRequest
+			if err := json.Unmarshal(data, &req); err != nil {
+				setImportError(&summary.Results[len(summary.Results)-1], err)
+			} else {
+				ad := req.Ad
+				ad.RawSource = req.RawSource
+				ads = append(ads, &ad)
+				pending = append(pending, len(summary.Results)-1)
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	for start := 0; start < len(ads); start += importBatchSize {
+		end := min(start+importBatchSize, len(ads))
+		batch := ads[start:end]
+		errs := h.useCase.ImportAds(c.Request.Context(), batch)
+		for i, ad := range batch {
+			result := &summary.Results[pending[start+i]]
+			result.ID = ad.ID
+			if errs[i] != nil {
+				setImportError(result, errs[i])
+			}
+		}
+	}
+
+	for _, result := range summary.Results {
+		if result.Error == "" {
+			summary.Created++
+		} else {
+			summary.Failed++
+		}
+	}
+	c.JSON(http.StatusMultiStatus, summary)
+}
+
+// setImportError records why an import line was not imported, listing the
+// invalid fields of a *domain.ValidationError
+func setImportError(result *domain.ImportLineResult, err error) {
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		result.Error = "validation failed"
+		result.Details = validationErr.Fields
+		return
+	}
+	result.Error = err.Error()
+}
+
+// adLocation is the URL path of the ad with the given ID
+func adLocation(id uint) string {
+	return fmt.Sprintf("/v3/ads/%d", id)
+}
+
 // @Summary Update ad
-// @Description Update an existing advertisement
+// @Description Update an existing advertisement. The body must carry the version the ad was read at; the ad is rejected with 409 when it was updated since. Only the user who posted the ad and moderators may update it, and only moderators may change a status other than draft or pending.
 // @Tags ads
 // @Accept json
 // @Produce json
 // @Param id path int true "Advertisement ID"
 // @Param ad body domain.Ad true "Advertisement object"
 // @Success 200 {object} domain.Ad
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /v3/ads/{id} [put]
 func (h *AdHandler) UpdateAd(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -95,13 +929,87 @@ func (h *AdHandler) UpdateAd(c *gin.Context) {
 
 	var ad domain.Ad
 	if err := c.ShouldBindJSON(&ad); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeBindError(c, err)
 		return
 	}
 
 	ad.ID = uint(id)
 	if err := h.useCase.UpdateAd(c.Request.Context(), &ad); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeAdError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ad)
+}
+
+// @Summary Renew ad
+// @Description Extend an active ad for another AD_LIFETIME from now. An ad completed when it expired is reactivated. Only the user who posted the ad and moderators may renew it.
+// @Tags ads
+// @Produce json
+// @Param id path int true "Advertisement ID"
+// @Success 200 {object} domain.Ad
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string "The ad is neither active nor expired"
+// @Router /v3/ads/{id}/renew [post]
+func (h *AdHandler) RenewAd(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	ad, err := h.useCase.RenewAd(c.Request.Context(), uint(id))
+	if err != nil {
+		writeAdError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ad)
+}
+
+// @Summary Get ad duplicates
+// @Description Review the duplicate detection of a parser import: the ad it was found to duplicate, if any, and the ads found to duplicate it
+// @Tags admin
+// @Produce json
+// @Param id path int true "Advertisement ID"
+// @Success 200 {object} domain.AdDuplicates
+// @Failure 404 {object} map[string]string
+// @Router /v3/ads/{id}/duplicates [get]
+func (h *AdHandler) GetDuplicates(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	review, err := h.useCase.GetDuplicates(c.Request.Context(), uint(id))
+	if err != nil {
+		writeAdError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// @Summary Mark ad as not a duplicate
+// @Description Override the duplicate detection for an ad marked as a duplicate, returning it to pending moderation
+// @Tags admin
+// @Produce json
+// @Param id path int true "Advertisement ID"
+// @Success 200 {object} domain.Ad
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string "The ad is not marked as a duplicate"
+// @Router /v3/ads/{id}/not-duplicate [post]
+func (h *AdHandler) ClearDuplicate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	ad, err := h.useCase.ClearDuplicate(c.Request.Context(), uint(id))
+	if err != nil {
+		writeAdError(c, err)
 		return
 	}
 
@@ -109,11 +1017,13 @@ func (h *AdHandler) UpdateAd(c *gin.Context) {
 }
 
 // @Summary Delete ad
-// @Description Delete an advertisement
+// @Description Delete an advertisement. Only the user who posted the ad and moderators may delete it.
 // @Tags ads
 // @Produce json
 // @Param id path int true "Advertisement ID"
 // @Success 204 "No Content"
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Router /v3/ads/{id} [delete]
 func (h *AdHandler) DeleteAd(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -123,9 +1033,271 @@ func (h *AdHandler) DeleteAd(c *gin.Context) {
 	}
 
 	if err := h.useCase.DeleteAd(c.Request.Context(), uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeAdError(c, err)
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
+
+// @Summary Get ad raw source
+// @Description Get the original parser payload an advertisement was ingested from
+// @Tags admin
+// @Produce json
+// @Param id path int true "Advertisement ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /v3/admin/ads/{id}/raw_source [get]
+func (h *AdHandler) GetRawSource(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	rawSource, err := h.useCase.GetAdRawSource(c.Request.Context(), uint(id))
+	if err != nil {
+		writeAdError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"id": uint(id), "raw_source": rawSource})
+}
+
+// @Summary Reveal ad phone
+// @Description Get the full contact phone of an advertisement; listings only show it masked. Each reveal is recorded.
+// @Tags ads
+// @Produce json
+// @Param id path int true "Advertisement ID"
+// @Success 200 {object} map[string]string
+// @Router /v3/ads/{id}/reveal-phone [post]
+func (h *AdHandler) RevealPhone(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	phone, err := h.useCase.RevealPhone(c.Request.Context(), uint(id))
+	if err != nil {
+		writeAdError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{"id": uint(id), "phone": phone})
+}
+
+// @Summary Get ad stats
+// @Description Get the engagement counters of an advertisement; only the user who posted it, moderators and admins may view them
+// @Tags ads
+// @Produce json
+// @Param id path int true "Advertisement ID"
+// @Success 200 {object} domain.AdStats
+// @Router /v3/ads/{id}/stats [get]
+func (h *AdHandler) GetAdStats(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	stats, err := h.useCase.GetAdStats(c.Request.Context(), uint(id))
+	if err != nil {
+		writeAdError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// @Summary Get ads statistics
+// @Description Get the number of ads per status and the number of ads created on each of the last days (UTC); cached for a minute
+// @Tags ads
+// @Produce json
+// @Param days query int false "Days covered by the created histogram, today included (1-90, default 30)"
+// @Success 200 {object} domain.AdsStats
+// @Router /v3/ads/stats [get]
+func (h *AdHandler) GetAdsStats(c *gin.Context) {
+	days := defaultStatsDays
+	if value := c.Query("days"); value != "" {
+		var err error
+		days, err = strconv.Atoi(value)
+		if err != nil || days <= 0 || days > domain.MaxStatsDays {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("days must be between 1 and %d", domain.MaxStatsDays)})
+			return
+		}
+	}
+
+	stats, err := h.useCase.GetAdsStats(c.Request.Context(), days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// @Summary Get search suggestions
+// @Description Get ad titles in the given language that start with the typed prefix or have a word starting with it, most frequent first; cached briefly
+// @Tags ads
+// @Produce json
+// @Param q query string true "Typed prefix, at least 2 characters"
+// @Param lang query string true "Language code (ru, en, tr)"
+// @Param limit query int false "Maximum number of suggestions (1-20, default 10)"
+// @Success 200 {array} domain.Suggestion
+// @Router /v3/ads/suggest [get]
+func (h *AdHandler) GetSuggestions(c *gin.Context) {
+	prefix := strings.TrimSpace(c.Query("q"))
+	if length := utf8.RuneCountInString(prefix); length < domain.MinSuggestionPrefix || length > maxSuggestPrefix {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("q must be between %d and %d characters", domain.MinSuggestionPrefix, maxSuggestPrefix)})
+		return
+	}
+
+	lang, err := domain.ParseLanguage(c.Query("lang"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"supported": domain.SupportedLanguageCodes(),
+		})
+		return
+	}
+
+	limit := defaultSuggestLimit
+	if value := c.Query("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > maxSuggestLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", maxSuggestLimit)})
+			return
+		}
+	}
+
+	suggestions, err := h.useCase.GetSuggestions(c.Request.Context(), prefix, lang, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestions)
+}
+
+// @Summary Report ad
+// @Description Flag an advertisement for moderation, once per user. Reason is a code or name: spam (1), fraud (2), prohibited_content (3), wrong_category (4), duplicate (5) or other (99). An ad with more than AUTO_REJECT_THRESHOLD reports is rejected automatically.
+// @Tags ads
+// @Accept json
+// @Produce json
+// @Param id path int true "Advertisement ID"
+// @Param report body domain.AdReport true "Report with reason and optional description"
+// @Success 201 {object} domain.AdReport
+// @Failure 409 {object} map[string]string
+// @Router /v3/ads/{id}/report [post]
+func (h *AdHandler) ReportAd(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var report domain.AdReport
+	if err := c.ShouldBindJSON(&report); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	report.AdID = uint(id)
+	if err := h.useCase.ReportAd(c.Request.Context(), &report); err != nil {
+		writeAdError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
+
+// @Summary List ad reports
+// @Description Get the moderation queue: reports awaiting review, oldest first
+// @Tags admin
+// @Produce json
+// @Param next_page query string false "Page token for pagination"
+// @Param page_size query int false "Number of items per page (at most 200)"
+// @Success 200 {object} domain.AdReportPage
+// @Router /v3/admin/reports [get]
+func (h *AdHandler) ListReports(c *gin.Context) {
+	var query struct {
+		PageToken string `form:"next_page"`
+		PageSize  int    `form:"page_size" binding:"omitempty,min=1,max=200"`
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.useCase.ListReports(c.Request.Context(), query.PageSize, query.PageToken)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidPageToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// isFilterError reports whether err rejects the filter parameters of an ads listing
+func isFilterError(err error) bool {
+	return errors.Is(err, domain.ErrInvalidCurrency) || errors.Is(err, domain.ErrInvalidPageToken) ||
+		errors.Is(err, domain.ErrInvalidSort) || errors.Is(err, domain.ErrRelevanceSortRequiresSearch) ||
+		errors.As(err, new(*domain.PageSizeError))
+}
+
+// writeBindError rejects a request body that could not be bound, with 413
+// when it exceeds the body size limit
+func writeBindError(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit)})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// writeValidationError writes the invalid fields of a *domain.ValidationError
+// with the given status and reports whether err was one
+func writeValidationError(c *gin.Context, status int, err error) bool {
+	var validationErr *domain.ValidationError
+	if !errors.As(err, &validationErr) {
+		return false
+	}
+	c.JSON(status, gin.H{"error": "validation failed", "details": validationErr.Fields})
+	return true
+}
+
+// writeAdError maps ad mutation errors to HTTP responses
+func writeAdError(c *gin.Context, err error) {
+	if writeValidationError(c, http.StatusUnprocessableEntity, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrInvalidCurrency), errors.Is(err, domain.ErrInvalidPhone), errors.Is(err, domain.ErrConstraint):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrCurrencyNotAllowed), errors.Is(err, domain.ErrInvalidSort), errors.Is(err, domain.ErrRelevanceSortRequiresSearch):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrRequestInProgress), errors.Is(err, domain.ErrConflict), errors.Is(err, domain.ErrDuplicate), errors.Is(err, domain.ErrAlreadyReported),
+		errors.Is(err, domain.ErrTooManyImages), errors.Is(err, domain.ErrNotRenewable),
+		errors.Is(err, domain.ErrNotDuplicate):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrNoPhone):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrImageTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrUnsupportedImageType):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrStorageUnavailable), errors.Is(err, domain.ErrIdempotencyUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,488 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// fakeAdUseCase implements the methods the tests exercise; calling any other
+// method panics on the nil embedded interface
+type fakeAdUseCase struct {
+	AdUseCase
+
+	updateErr error
+	renewErr  error
+	deleteErr error
+	existsErr error
+	ads       []domain.Ad
+	// favorites are the IDs of the ads favorited, by user
+	favorites map[string][]uint
+}
+
+func (f *fakeAdUseCase) GetAds(ctx context.Context, filter domain.FilterRequest) (*domain.PaginatedResponse, error) {
+	return &domain.PaginatedResponse{Items: f.ads, PageSize: len(f.ads), TotalCount: int64(len(f.ads))}, nil
+}
+
+func (f *fakeAdUseCase) AdsExist(ctx context.Context, filter domain.FilterRequest) (bool, error) {
+	return len(f.ads) > 0, f.existsErr
+}
+
+func (f *fakeAdUseCase) GetAd(ctx context.Context, id uint) (*domain.Ad, error) {
+	for i := range f.ads {
+		if f.ads[i].ID == id {
+			return &f.ads[i], nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (f *fakeAdUseCase) RecordView(ctx context.Context, id uint) {}
+
+func (f *fakeAdUseCase) GetFavoriteCount(ctx context.Context, adID uint) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeAdUseCase) GetFavorited(ctx context.Context, adIDs []uint) (map[uint]bool, error) {
+	principal := domain.PrincipalFromContext(ctx)
+	if principal == nil {
+		return nil, nil
+	}
+	favorited := make(map[uint]bool)
+	for _, id := range f.favorites[principal.UserID] {
+		if slices.Contains(adIDs, id) {
+			favorited[id] = true
+		}
+	}
+	return favorited, nil
+}
+
+func (f *fakeAdUseCase) UpdateAd(ctx context.Context, ad *domain.Ad) error {
+	return f.updateErr
+}
+
+func (f *fakeAdUseCase) DeleteAd(ctx context.Context, id uint) error {
+	return f.deleteErr
+}
+
+func (f *fakeAdUseCase) RenewAd(ctx context.Context, id uint) (*domain.Ad, error) {
+	if f.renewErr != nil {
+		return nil, f.renewErr
+	}
+	return &domain.Ad{ID: id, Status: domain.StatusActive}, nil
+}
+
+// ImportAds creates every ad but the ones titled "rejected", which it fails
+// as the database would
+func (f *fakeAdUseCase) ImportAds(ctx context.Context, ads []*domain.Ad) []error {
+	errs := make([]error, len(ads))
+	for i, ad := range ads {
+		if ad.Title.GetText(domain.LangEnglish) == "rejected" {
+			errs[i] = fmt.Errorf("error creating ad: %w", domain.ErrConstraint)
+			continue
+		}
+		f.ads = append(f.ads, *ad)
+		ad.ID = uint(len(f.ads))
+	}
+	return errs
+}
+
+func (f *fakeAdUseCase) ExportAds(ctx context.Context, filter domain.FilterRequest, fn func(*domain.Ad) error) error {
+	for i := range f.ads {
+		if filter.Status != nil && f.ads[i].Status != *filter.Status {
+			continue
+		}
+		if err := fn(&f.ads[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// serve routes one request through a router with the given handler
+func serve(method, route, target string, handle gin.HandlerFunc) *httptest.ResponseRecorder {
+	return serveBody(method, route, target, "", handle)
+}
+
+// serveBody routes one request with the given body through a router with the given handler
+func serveBody(method, route, target, body string, handle gin.HandlerFunc) *httptest.ResponseRecorder {
+	return serveAs(nil, method, route, target, body, handle)
+}
+
+// serveAs routes one request made by principal, nil for anonymous, through a
+// router with the given handler
+func serveAs(principal *domain.Principal, method, route, target, body string, handle gin.HandlerFunc) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Handle(method, route, func(c *gin.Context) {
+		if principal != nil {
+			c.Request = c.Request.WithContext(domain.WithPrincipal(c.Request.Context(), principal))
+		}
+	}, handle)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return rec
+}
+
+func TestUpdateAdStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"updated", nil, http.StatusOK},
+		{"status change reserved to moderators", domain.ErrForbidden, http.StatusForbidden},
+		{"outdated version", domain.ErrConflict, http.StatusConflict},
+		{"missing", domain.ErrNotFound, http.StatusNotFound},
+		{"duplicate", fmt.Errorf("error updating ad: %w", domain.ErrDuplicate), http.StatusConflict},
+		{"constraint violated", fmt.Errorf("error updating ad: %w", domain.ErrConstraint), http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewAdHandler(&fakeAdUseCase{updateErr: tt.err}, 0)
+			body := `{"title_multi":[{"lang":2,"text":"Bike"}],"status":5,"version":1}`
+			rec := serveBody(http.MethodPut, "/v3/ads/:id", "/v3/ads/1", body, h.UpdateAd)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestDeleteAdStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"deleted", nil, http.StatusNoContent},
+		{"not owner", domain.ErrForbidden, http.StatusForbidden},
+		{"missing", domain.ErrNotFound, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewAdHandler(&fakeAdUseCase{deleteErr: tt.err}, 0)
+			rec := serve(http.MethodDelete, "/v3/ads/:id", "/v3/ads/1", h.DeleteAd)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdsExistStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		ads  []domain.Ad
+		err  error
+		want int
+		body string
+	}{
+		{"match", []domain.Ad{{ID: 1}}, nil, http.StatusOK, `{"exists":true}`},
+		{"no match", nil, nil, http.StatusOK, `{"exists":false}`},
+		{"invalid currency", nil, domain.ErrInvalidCurrency, http.StatusBadRequest, ""},
+		{"invalid sort", nil, domain.ErrInvalidSort, http.StatusBadRequest, ""},
+		{"relevance without search", nil, domain.ErrRelevanceSortRequiresSearch, http.StatusBadRequest, ""},
+		{"page size", nil, &domain.PageSizeError{Max: 100}, http.StatusBadRequest, ""},
+		{"database", nil, errors.New("connection reset"), http.StatusInternalServerError, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewAdHandler(&fakeAdUseCase{ads: tt.ads, existsErr: tt.err}, 0)
+			rec := serve(http.MethodGet, "/v3/ads/exists", "/v3/ads/exists?lang=en", h.AdsExist)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.body != "" && rec.Body.String() != tt.body {
+				t.Errorf("body = %s, want %s", rec.Body, tt.body)
+			}
+		})
+	}
+}
+
+func TestRenewAd(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		err    error
+		want   int
+	}{
+		{"renewed", "/v3/ads/1/renew", nil, http.StatusOK},
+		{"invalid id", "/v3/ads/one/renew", nil, http.StatusBadRequest},
+		{"not the owner", "/v3/ads/1/renew", domain.ErrForbidden, http.StatusForbidden},
+		{"missing", "/v3/ads/1/renew", domain.ErrNotFound, http.StatusNotFound},
+		{"neither active nor expired", "/v3/ads/1/renew", domain.ErrNotRenewable, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewAdHandler(&fakeAdUseCase{renewErr: tt.err}, 0)
+			rec := serve(http.MethodPost, "/v3/ads/:id/renew", tt.target, h.RenewAd)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestExportStreamsFilteredAds(t *testing.T) {
+	// More ads than exportFlushRows, so the stream is flushed on the way
+	ads := make([]domain.Ad, 1201)
+	active := 0
+	for i := range ads {
+		ads[i] = domain.Ad{
+			ID:    uint(i + 1),
+			Title: domain.MultiLangArray{{Lang: domain.LangEnglish, Text: "Bike"}},
+		}
+		if i%3 == 0 {
+			ads[i].Status = domain.StatusActive
+			active++
+		}
+	}
+
+	tests := []struct {
+		name        string
+		target      string
+		contentType string
+		lines       int
+	}{
+		{"ndjson", "/v3/ads/export?lang=en&status=active", "application/x-ndjson", active},
+		{"csv with header", "/v3/ads/export?lang=en&status=active&format=csv", "text/csv; charset=utf-8", active + 1},
+		{"unfiltered", "/v3/ads/export?lang=en", "application/x-ndjson", len(ads)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewAdHandler(&fakeAdUseCase{ads: ads}, 0)
+			rec := serve(http.MethodGet, "/v3/ads/export", tt.target, h.Export)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.contentType)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n"), "\n")
+			if len(lines) != tt.lines {
+				t.Errorf("streamed %d lines, want %d", len(lines), tt.lines)
+			}
+			if tt.contentType == "application/x-ndjson" {
+				var ad domain.Ad
+				if err := json.Unmarshal([]byte(lines[len(lines)-1]), &ad); err != nil {
+					t.Errorf("last line is not an ad: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestExportCSVParses(t *testing.T) {
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	ad := domain.Ad{
+		ID:          7,
+		Title:       domain.MultiLangArray{{Lang: domain.LangEnglish, Text: `Bike, "like new"`}},
+		Description: domain.MultiLangArray{{Lang: domain.LangEnglish, Text: "Two wheels\none bell"}},
+		CategoryIDs: []int{3, 5},
+		Status:      domain.StatusActive,
+		Price:       &domain.Price{Value: 120.5, Currency: string(domain.CurrencyEUR), Type: domain.PriceFixed},
+		CreatedAt:   created,
+		UpdatedAt:   created,
+	}
+	h := NewAdHandler(&fakeAdUseCase{ads: []domain.Ad{ad}}, 0)
+	rec := serve(http.MethodGet, "/v3/ads/export.csv", "/v3/ads/export.csv?lang=en", h.ExportCSV)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		exportHeader,
+		{"7", `Bike, "like new"`, "Two wheels\none bell", "3,5", "active", "120.5", "EUR", "2026-03-01T12:00:00Z", "2026-03-01T12:00:00Z", ""},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("records = %q, want %q", records, want)
+	}
+}
+
+func TestImportAdsMixedLines(t *testing.T) {
+	body := strings.Join([]string{
+		`{"title_multi":[{"lang":2,"text":"Bike"}],"category_ids":[1]}`,
+		`{"title_multi":`,
+		``,
+		`{"title_multi":[{"lang":2,"text":"rejected"}],"category_ids":[1]}`,
+		`{"title_multi":[{"lang":2,"text":"Lamp"}],"category_ids":[2],"raw_source":{"url":"https://example.com/lamp"}}`,
+	}, "\n")
+	h := NewAdHandler(&fakeAdUseCase{}, 0)
+	rec := serveBody(http.MethodPost, "/v3/ads/import", "/v3/ads/import", body, h.ImportAds)
+	if rec.Code != http.StatusMultiStatus {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+
+	var summary domain.ImportSummary
+	if err := json.Unmarshal(rec.Body.Bytes(), &summary); err != nil {
+		t.Fatal(err)
+	}
+	if summary.Created != 2 || summary.Failed != 2 || summary.Error != "" {
+		t.Errorf("summary = %+v, want 2 created and 2 failed", summary)
+	}
+	want := []struct {
+		line   int
+		id     uint
+		failed bool
+	}{{1, 1, false}, {2, 0, true}, {4, 0, true}, {5, 2, false}}
+	if len(summary.Results) != len(want) {
+		t.Fatalf("results = %+v, want %d", summary.Results, len(want))
+	}
+	for i, w := range want {
+		got := summary.Results[i]
+		if got.Line != w.line || got.ID != w.id || (got.Error != "") != w.failed {
+			t.Errorf("result %d = %+v, want line %d with ID %d, failed %v", i, got, w.line, w.id, w.failed)
+		}
+	}
+}
+
+func TestImportAdsItemLimit(t *testing.T) {
+	line := `{"title":{"en":"Bike"},"category_ids":[1]}` + "\n"
+	tests := []struct {
+		name     string
+		lines    int
+		want     int
+		imported int
+	}{
+		{"under the limit", 2, http.StatusMultiStatus, 2},
+		{"at the limit", 3, http.StatusMultiStatus, 3},
+		{"over the limit", 4, http.StatusRequestEntityTooLarge, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useCase := &fakeAdUseCase{}
+			h := NewAdHandler(useCase, 3)
+			body := strings.Repeat(line, tt.lines) + "\n\n"
+			rec := serveBody(http.MethodPost, "/v3/ads/import", "/v3/ads/import", body, h.ImportAds)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if len(useCase.ads) != tt.imported {
+				t.Errorf("imported %d ads, want %d", len(useCase.ads), tt.imported)
+			}
+		})
+	}
+}
+
+func TestGetAdsIsFavorited(t *testing.T) {
+	useCase := &fakeAdUseCase{
+		ads:       []domain.Ad{{ID: 1}, {ID: 2}, {ID: 3}},
+		favorites: map[string][]uint{"user-1": {1, 3, 7}},
+	}
+	h := NewAdHandler(useCase, 0)
+
+	tests := []struct {
+		name      string
+		principal *domain.Principal
+		target    string
+		want      map[uint]*bool
+	}{
+		{"authenticated", &domain.Principal{UserID: "user-1"}, "/v3/ads?lang=en", map[uint]*bool{1: ptr(true), 2: ptr(false), 3: ptr(true)}},
+		{"no favorites", &domain.Principal{UserID: "user-2"}, "/v3/ads?lang=en", map[uint]*bool{1: ptr(false), 2: ptr(false), 3: ptr(false)}},
+		{"anonymous", nil, "/v3/ads?lang=en", map[uint]*bool{1: nil, 2: nil, 3: nil}},
+		{"selected fields", &domain.Principal{UserID: "user-1"}, "/v3/ads?lang=en&fields=id", map[uint]*bool{1: ptr(true), 2: ptr(false), 3: ptr(true)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveAs(tt.principal, http.MethodGet, "/v3/ads", tt.target, "", h.GetAds)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+			}
+			var page struct {
+				Items []struct {
+					ID          uint  `json:"id"`
+					IsFavorited *bool `json:"is_favorited"`
+				} `json:"items"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+				t.Fatal(err)
+			}
+			if len(page.Items) != len(tt.want) {
+				t.Fatalf("got %d ads, want %d", len(page.Items), len(tt.want))
+			}
+			for _, item := range page.Items {
+				checkFavorited(t, item.ID, item.IsFavorited, tt.want[item.ID])
+			}
+		})
+	}
+
+	// The page served to the user must not leak into the shared one
+	for _, ad := range useCase.ads {
+		if ad.IsFavorited != nil {
+			t.Errorf("ad %d of the shared page was marked", ad.ID)
+		}
+	}
+}
+
+func TestGetAdIsFavorited(t *testing.T) {
+	useCase := &fakeAdUseCase{
+		ads:       []domain.Ad{{ID: 1}, {ID: 2}},
+		favorites: map[string][]uint{"user-1": {2}},
+	}
+	h := NewAdHandler(useCase, 0)
+
+	tests := []struct {
+		name      string
+		principal *domain.Principal
+		id        uint
+		want      *bool
+	}{
+		{"favorited", &domain.Principal{UserID: "user-1"}, 2, ptr(true)},
+		{"not favorited", &domain.Principal{UserID: "user-1"}, 1, ptr(false)},
+		{"anonymous", nil, 2, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := "/v3/ads/" + strconv.Itoa(int(tt.id))
+			rec := serveAs(tt.principal, http.MethodGet, "/v3/ads/:id", target, "", h.GetAd)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+			}
+			var ad struct {
+				IsFavorited *bool `json:"is_favorited"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &ad); err != nil {
+				t.Fatal(err)
+			}
+			checkFavorited(t, tt.id, ad.IsFavorited, tt.want)
+		})
+	}
+}
+
+func checkFavorited(t *testing.T, id uint, got, want *bool) {
+	t.Helper()
+	switch {
+	case want == nil && got != nil:
+		t.Errorf("ad %d: is_favorited = %v, want it omitted", id, *got)
+	case want != nil && got == nil:
+		t.Errorf("ad %d: is_favorited omitted, want %v", id, *want)
+	case want != nil && *got != *want:
+		t.Errorf("ad %d: is_favorited = %v, want %v", id, *got, *want)
+	}
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryUseCase interface {
+	GetCountsBatch(ctx context.Context, ids []int) (map[int]int64, error)
+	GetCategory(ctx context.Context, id int) (*domain.Category, error)
+	CreateCategory(ctx context.Context, category *domain.Category) error
+	UpdateCategory(ctx context.Context, category *domain.Category) error
+	DeleteCategory(ctx context.Context, id int) error
+	GetPath(ctx context.Context, id int) ([]domain.Category, error)
+}
+
+type CategoryHandler struct {
+	useCase CategoryUseCase
+}
+
+func NewCategoryHandler(useCase CategoryUseCase) *CategoryHandler {
+	return &CategoryHandler{useCase: useCase}
+}
+
+// @Summary Get category ad counts
+// @Description Get the number of active ads for each requested category
+// @Tags categories
+// @Produce json
+// @Param ids query string true "Comma-separated category IDs"
+// @Success 200 {object} map[string]int64
+// @Router /v3/categories/counts [get]
+func (h *CategoryHandler) GetCounts(c *gin.Context) {
+	var ids []int
+	for _, part := range strings.Split(c.Query("ids"), ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		id, err := strconv.Atoi(part)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category id: " + part})
+			return
+		}
+		ids = append(ids, id)
+	}
+
+	counts, err := h.useCase.GetCountsBatch(c.Request.Context(), ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"counts": counts})
+}
+
+// @Summary Get category
+// @Description Get a category by ID
+// @Tags categories
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} domain.Category
+// @Failure 404 {object} map[string]string
+// @Router /v3/categories/{id} [get]
+func (h *CategoryHandler) GetCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category id"})
+		return
+	}
+
+	category, err := h.useCase.GetCategory(c.Request.Context(), id)
+	if err != nil {
+		writeCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+// @Summary Get category path
+// @Description Get the category's ancestors, from the root category down to the category itself, e.g. for breadcrumbs
+// @Tags categories
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} map[string][]domain.Category
+// @Failure 404 {object} map[string]string
+// @Router /v3/categories/{id}/path [get]
+func (h *CategoryHandler) GetPath(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category id"})
+		return
+	}
+
+	path, err := h.useCase.GetPath(c.Request.Context(), id)
+	if err != nil {
+		writeCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"path": path})
+}
+
+// @Summary Create category
+// @Description Create a category below parent_id, or at the root without one
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param category body domain.Category true "Category object"
+// @Success 201 {object} domain.Category
+// @Failure 422 {object} map[string]string
+// @Router /v3/admin/categories [post]
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	var category domain.Category
+	if err := c.ShouldBindJSON(&category); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	category.ID = 0
+	if err := h.useCase.CreateCategory(c.Request.Context(), &category); err != nil {
+		writeCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, category)
+}
+
+// @Summary Update category
+// @Description Rename a category, or move it with its subcategories below another parent_id
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param category body domain.Category true "Category object"
+// @Success 200 {object} domain.Category
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /v3/admin/categories/{id} [put]
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category id"})
+		return
+	}
+
+	var category domain.Category
+	if err := c.ShouldBindJSON(&category); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	category.ID = id
+	if err := h.useCase.UpdateCategory(c.Request.Context(), &category); err != nil {
+		writeCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+// @Summary Delete category
+// @Description Delete a category without subcategories
+// @Tags categories
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /v3/admin/categories/{id} [delete]
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category id"})
+		return
+	}
+
+	if err := h.useCase.DeleteCategory(c.Request.Context(), id); err != nil {
+		writeCategoryError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// writeCategoryError maps category errors to HTTP responses
+func writeCategoryError(c *gin.Context, err error) {
+	if writeValidationError(c, http.StatusUnprocessableEntity, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+	case errors.Is(err, domain.ErrConstraint):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "parent category not found"})
+	case errors.Is(err, domain.ErrCategoryHasChildren), errors.Is(err, domain.ErrCategoryCycle):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type FavoriteUseCase interface {
+	AddFavorite(ctx context.Context, userID string, adID uint) error
+	RemoveFavorite(ctx context.Context, userID string, adID uint) error
+	ListFavorites(ctx context.Context, userID string, pageSize int, pageToken string) (*domain.PaginatedResponse, error)
+}
+
+type FavoriteHandler struct {
+	useCase FavoriteUseCase
+}
+
+func NewFavoriteHandler(useCase FavoriteUseCase) *FavoriteHandler {
+	return &FavoriteHandler{useCase: useCase}
+}
+
+// @Summary Add ad to favorites
+// @Description Save an advertisement to the current user's favorites
+// @Tags favorites
+// @Produce json
+// @Param id path int true "Advertisement ID"
+// @Success 204 "No Content"
+// @Router /v3/ads/{id}/favorite [post]
+func (h *FavoriteHandler) AddFavorite(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	h.addFavorite(c, uint(id))
+}
+
+// @Summary Add ad to favorites
+// @Description Save an advertisement to the current user's favorites
+// @Tags favorites
+// @Accept json
+// @Produce json
+// @Param favorite body object true "The ad to save, e.g. {\"ad_id\":42}"
+// @Success 204 "No Content"
+// @Router /v3/favorites [post]
+func (h *FavoriteHandler) CreateFavorite(c *gin.Context) {
+	var request struct {
+		AdID uint `json:"ad_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		writeBindError(c, err)
+		return
+	}
+	h.addFavorite(c, request.AdID)
+}
+
+func (h *FavoriteHandler) addFavorite(c *gin.Context, adID uint) {
+	principal := domain.PrincipalFromContext(c.Request.Context())
+	if err := h.useCase.AddFavorite(c.Request.Context(), principal.UserID, adID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "ad not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Remove ad from favorites
+// @Description Remove an advertisement from the current user's favorites
+// @Tags favorites
+// @Produce json
+// @Param id path int true "Advertisement ID"
+// @Success 204 "No Content"
+// @Router /v3/ads/{id}/favorite [delete]
+// @Router /v3/favorites/{id} [delete]
+func (h *FavoriteHandler) RemoveFavorite(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	principal := domain.PrincipalFromContext(c.Request.Context())
+	if err := h.useCase.RemoveFavorite(c.Request.Context(), principal.UserID, uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary List favorite ads
+// @Description Get a paginated list of the current user's favorited ads
+// @Tags favorites
+// @Produce json
+// @Param next_page query string false "Page token for pagination"
+// @Param page_size query int false "Number of items per page"
+// @Success 200 {object} domain.PaginatedResponse
+// @Router /v3/me/favorites [get]
+// @Router /v3/favorites [get]
+func (h *FavoriteHandler) ListFavorites(c *gin.Context) {
+	var query struct {
+		PageToken string `form:"next_page"`
+		PageSize  int    `form:"page_size"`
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	principal := domain.PrincipalFromContext(c.Request.Context())
+	response, err := h.useCase.ListFavorites(c.Request.Context(), principal.UserID, query.PageSize, query.PageToken)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidPageToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type FeedUseCase interface {
+	AdsRSS(ctx context.Context, categoryID int, lang domain.Language) ([]byte, error)
+}
+
+type FeedHandler struct {
+	useCase FeedUseCase
+}
+
+func NewFeedHandler(useCase FeedUseCase) *FeedHandler {
+	return &FeedHandler{useCase: useCase}
+}
+
+// @Summary RSS feed of new ads
+// @Description Get an RSS 2.0 feed of the 50 most recent active ads, optionally limited to a category
+// @Tags feeds
+// @Produce application/rss+xml
+// @Param category query int false "Category ID"
+// @Param lang query string true "Language code (ru, en, tr) or numeric language"
+// @Success 200 {string} string "RSS 2.0 document"
+// @Router /v3/feeds/ads.rss [get]
+func (h *FeedHandler) AdsRSS(c *gin.Context) {
+	var categoryID int
+	if value := c.Query("category"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil || id <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category id: " + value})
+			return
+		}
+		categoryID = id
+	}
+
+	lang, err := domain.ParseLanguage(c.Query("lang"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"supported": domain.SupportedLanguageCodes(),
+		})
+		return
+	}
+
+	feed, err := h.useCase.AdsRSS(c.Request.Context(), categoryID, lang)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, "application/rss+xml", feed)
+}
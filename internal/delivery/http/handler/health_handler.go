@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type HealthChecker interface {
+	Check(ctx context.Context) domain.HealthReport
+}
+
+type HealthHandler struct {
+	checker HealthChecker
+}
+
+func NewHealthHandler(checker HealthChecker) *HealthHandler {
+	return &HealthHandler{checker: checker}
+}
+
+// @Summary Health report
+// @Description Report database and Redis connectivity; 503 when the service is unhealthy
+// @Tags health
+// @Produce json
+// @Success 200 {object} domain.HealthReport
+// @Failure 503 {object} domain.HealthReport
+// @Router /health [get]
+func (h *HealthHandler) Health(c *gin.Context) {
+	report := h.checker.Check(c.Request.Context())
+
+	status := http.StatusOK
+	if report.Overall == domain.HealthUnhealthy {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
+
+// @Summary Liveness probe
+// @Description Always succeeds while the process is serving requests
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /health/live [get]
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// @Summary Readiness probe
+// @Description Succeeds only when both the database and Redis are reachable
+// @Tags health
+// @Produce json
+// @Success 200 {object} domain.HealthReport
+// @Failure 503 {object} domain.HealthReport
+// @Router /health/ready [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	report := h.checker.Check(c.Request.Context())
+
+	status := http.StatusOK
+	if report.Overall != domain.HealthHealthy {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImageFormField is the multipart form field carrying an uploaded image
+const ImageFormField = "image"
+
+// reorderImagesRequest lists the keys of an ad's images in their new order
+type reorderImagesRequest struct {
+	Keys []string `json:"keys" binding:"required"`
+}
+
+// @Summary Upload ad image
+// @Description Add an image to an advertisement. The image must be a JPEG, PNG or GIF of at most MAX_IMAGE_SIZE bytes; an ad has at most 20 images, the first being its primary one.
+// @Tags ads
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Advertisement ID"
+// @Param image formData file true "Image file"
+// @Success 201 {object} domain.Image
+// @Failure 409 {object} map[string]string "The ad has the maximum number of images"
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Router /v3/ads/{id}/images [post]
+func (h *AdHandler) UploadImage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	header, err := c.FormFile(ImageFormField)
+	if err != nil {
+		writeBindError(c, err)
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	image, err := h.useCase.AddImage(c.Request.Context(), uint(id), data)
+	if err != nil {
+		writeAdError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, image)
+}
+
+// @Summary Delete ad image
+// @Description Remove an image from an advertisement and the storage. When the primary image is removed, the next one becomes primary.
+// @Tags ads
+// @Produce json
+// @Param id path int true "Advertisement ID"
+// @Param key path string true "Image key"
+// @Success 204 "No Content"
+// @Router /v3/ads/{id}/images/{key} [delete]
+func (h *AdHandler) DeleteImage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.useCase.DeleteImage(c.Request.Context(), uint(id), c.Param("key")); err != nil {
+		writeAdError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Reorder ad images
+// @Description Put the images of an advertisement in the given order, listing each image key once. The first image becomes the primary one.
+// @Tags ads
+// @Accept json
+// @Produce json
+// @Param id path int true "Advertisement ID"
+// @Param order body reorderImagesRequest true "Image keys in their new order"
+// @Success 200 {array} domain.Image
+// @Failure 422 {object} map[string]string
+// @Router /v3/ads/{id}/images/order [put]
+func (h *AdHandler) ReorderImages(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req reorderImagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	images, err := h.useCase.ReorderImages(c.Request.Context(), uint(id), req.Keys)
+	if err != nil {
+		writeAdError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, images)
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultRebuildBatchSize is the number of ads per search vector rebuild batch
+	defaultRebuildBatchSize = 500
+	// maxRebuildBatchSize caps the batch_size of search vector rebuilds
+	maxRebuildBatchSize = 5000
+)
+
+// @Summary Rebuild search vectors
+// @Description Start recomputing the search vector of every ad, e.g. after a text search configuration was added for a language. The ads are updated in ID order, batch_size at a time, in the background; poll the returned job for progress.
+// @Tags admin
+// @Produce json
+// @Param batch_size query int false "Ads updated per statement (1-5000, default 500)"
+// @Success 202 {object} domain.Job
+// @Header 202 {string} Location "URL path of the job"
+// @Router /v3/admin/ads/reindex-search-vectors [post]
+func (h *AdHandler) RebuildSearchVectors(c *gin.Context) {
+	batchSize := defaultRebuildBatchSize
+	if value := c.Query("batch_size"); value != "" {
+		var err error
+		batchSize, err = strconv.Atoi(value)
+		if err != nil || batchSize <= 0 || batchSize > maxRebuildBatchSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("batch_size must be between 1 and %d", maxRebuildBatchSize)})
+			return
+		}
+	}
+
+	job, err := h.useCase.StartSearchVectorRebuild(c.Request.Context(), batchSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Location", "/v3/admin/jobs/"+job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// @Summary Get job
+// @Description Get the status and progress of a background job started through the admin API. Jobs are kept for a day after their last update.
+// @Tags admin
+// @Produce json
+// @Param job_id path string true "Job ID"
+// @Success 200 {object} domain.Job
+// @Failure 404 {object} map[string]string
+// @Router /v3/admin/jobs/{job_id} [get]
+func (h *AdHandler) GetJob(c *gin.Context) {
+	job, err := h.useCase.GetJob(c.Request.Context(), c.Param("job_id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
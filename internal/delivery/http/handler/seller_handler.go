@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type SellerUseCase interface {
+	CreateSeller(ctx context.Context, seller *domain.Seller) error
+	UpdateSeller(ctx context.Context, seller *domain.Seller) error
+	DeleteSeller(ctx context.Context, id uint) error
+	GetSeller(ctx context.Context, id uint) (*domain.Seller, error)
+	GetSellerAds(ctx context.Context, id uint, filter domain.FilterRequest) (*domain.PaginatedResponse, error)
+}
+
+type SellerHandler struct {
+	useCase SellerUseCase
+}
+
+func NewSellerHandler(useCase SellerUseCase) *SellerHandler {
+	return &SellerHandler{useCase: useCase}
+}
+
+// @Summary Get seller
+// @Description Get a seller by ID
+// @Tags sellers
+// @Produce json
+// @Param id path int true "Seller ID"
+// @Success 200 {object} domain.Seller
+// @Router /v3/sellers/{id} [get]
+func (h *SellerHandler) GetSeller(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	seller, err := h.useCase.GetSeller(c.Request.Context(), uint(id))
+	if err != nil {
+		writeSellerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, seller)
+}
+
+// @Summary Get seller ads
+// @Description Get a paginated list of the seller's ads, with the same filters as GET /v3/ads
+// @Tags sellers
+// @Produce json
+// @Param id path int true "Seller ID"
+// @Param lang query string true "Language code (ru, en, tr)"
+// @Param next_page query string false "Page token for pagination"
+// @Param page_size query int false "Number of items per page (default 20); capped at MAX_PAGE_SIZE, and rejected above ten times it"
+// @Success 200 {object} domain.PaginatedResponse
+// @Router /v3/sellers/{id}/ads [get]
+func (h *SellerHandler) GetSellerAds(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	filter, ok := bindFilter(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.useCase.GetSellerAds(c.Request.Context(), uint(id), filter)
+	if err != nil {
+		if writeValidationError(c, http.StatusBadRequest, err) {
+			return
+		}
+		if isFilterError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		writeSellerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Create seller
+// @Description Create a new seller
+// @Tags sellers
+// @Accept json
+// @Produce json
+// @Param seller body domain.Seller true "Seller object"
+// @Success 201 {object} domain.Seller
+// @Router /v3/sellers [post]
+func (h *SellerHandler) CreateSeller(c *gin.Context) {
+	var seller domain.Seller
+	if err := c.ShouldBindJSON(&seller); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	seller.ID = 0
+	if err := h.useCase.CreateSeller(c.Request.Context(), &seller); err != nil {
+		writeSellerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, seller)
+}
+
+// @Summary Update seller
+// @Description Update an existing seller
+// @Tags sellers
+// @Accept json
+// @Produce json
+// @Param id path int true "Seller ID"
+// @Param seller body domain.Seller true "Seller object"
+// @Success 200 {object} domain.Seller
+// @Router /v3/sellers/{id} [put]
+func (h *SellerHandler) UpdateSeller(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var seller domain.Seller
+	if err := c.ShouldBindJSON(&seller); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	seller.ID = uint(id)
+	if err := h.useCase.UpdateSeller(c.Request.Context(), &seller); err != nil {
+		writeSellerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, seller)
+}
+
+// @Summary Delete seller
+// @Description Delete a seller; their ads are kept without a seller
+// @Tags sellers
+// @Produce json
+// @Param id path int true "Seller ID"
+// @Success 204 "No Content"
+// @Router /v3/sellers/{id} [delete]
+func (h *SellerHandler) DeleteSeller(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.useCase.DeleteSeller(c.Request.Context(), uint(id)); err != nil {
+		writeSellerError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// writeSellerError maps seller errors to HTTP responses
+func writeSellerError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "seller not found"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
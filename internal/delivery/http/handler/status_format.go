@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// bindStatusFormat parses the status_format query parameter, writing a 400
+// response and returning false when it is invalid
+func bindStatusFormat(c *gin.Context) (domain.StatusFormat, bool) {
+	format, err := domain.ParseStatusFormat(c.Query("status_format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return "", false
+	}
+	return format, true
+}
+
+// withStatusFormat renders the status of an ad, or of every item of a page of
+// ads, in the given format. obj is returned as is for the default format.
+func withStatusFormat(obj interface{}, format domain.StatusFormat) (interface{}, error) {
+	if format == domain.StatusFormatCode {
+		return obj, nil
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	rawItems, isPage := fields["items"]
+	if !isPage {
+		return fields, formatStatusField(fields, format)
+	}
+
+	var items []map[string]json.RawMessage
+	if err := json.Unmarshal(rawItems, &items); err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if err := formatStatusField(item, format); err != nil {
+			return nil, err
+		}
+	}
+	if fields["items"], err = json.Marshal(items); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// formatStatusField re-renders the status field of a marshaled ad, if present
+func formatStatusField(fields map[string]json.RawMessage, format domain.StatusFormat) error {
+	raw, ok := fields["status"]
+	if !ok {
+		return nil
+	}
+
+	var status domain.AdStatus
+	if err := json.Unmarshal(raw, &status); err != nil {
+		return err
+	}
+	formatted, err := json.Marshal(status.Format(format))
+	if err != nil {
+		return err
+	}
+	fields["status"] = formatted
+	return nil
+}
+
+// writeJSONWithStatusFormat is writeJSONWithETag rendering statuses in the given format
+func writeJSONWithStatusFormat(c *gin.Context, obj interface{}, format domain.StatusFormat) {
+	formatted, err := withStatusFormat(obj, format)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	writeJSONWithETag(c, formatted)
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// AdminKeyHeader carries the admin API key
+const AdminKeyHeader = "X-Admin-Key"
+
+// RequireAdmin rejects requests without the configured admin API key or a
+// token with the admin role. Without a key configured only tokens are accepted.
+// Requests carrying neither a token nor a key are unauthenticated, the others
+// are forbidden.
+func RequireAdmin(apiKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isAdmin(c, apiKey) {
+			if domain.PrincipalFromContext(c.Request.Context()) == nil && c.GetHeader(AdminKeyHeader) == "" {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// isAdmin reports whether the request carries the configured admin API key
+// or comes from a principal with the admin role
+func isAdmin(c *gin.Context, apiKey string) bool {
+	if principal := domain.PrincipalFromContext(c.Request.Context()); principal != nil && principal.HasAnyRole(domain.RoleAdmin) {
+		return true
+	}
+	provided := c.GetHeader(AdminKeyHeader)
+	return apiKey != "" && subtle.ConstantTimeCompare([]byte(provided), []byte(apiKey)) == 1
+}
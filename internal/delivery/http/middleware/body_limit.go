@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimit caps request bodies at maxBytes. Requests announcing a larger body
+// are rejected with 413 up front; reading past the limit of a body of unknown
+// length fails with *http.MaxBytesError, which handlers answer with 413.
+// A limit of 0 disables the check. routeLimits replaces maxBytes for the
+// routes it lists, by path pattern.
+func BodyLimit(maxBytes int64, routeLimits map[string]int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		maxBytes := maxBytes
+		if limit, ok := routeLimits[c.FullPath()]; ok {
+			maxBytes = limit
+		}
+		if maxBytes <= 0 || c.Request.Body == nil {
+			c.Next()
+			return
+		}
+		if c.Request.ContentLength > maxBytes {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body too large"})
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		c.Next()
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// CacheControl stores the request's cache mode in the request context.
+// "Cache-Control: no-cache" or refresh=true skips the cache read, "no-store" also skips the write.
+// The bypass is honored only when enabled or for callers with the admin API key,
+// so public clients cannot send every request to the database.
+func CacheControl(bypassEnabled bool, adminAPIKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		control := &domain.CacheControl{}
+		if bypassEnabled || isAdmin(c, adminAPIKey) {
+			control.Mode = requestCacheMode(c)
+		}
+		c.Request = c.Request.WithContext(domain.WithCacheControl(c.Request.Context(), control))
+		c.Next()
+	}
+}
+
+// requestCacheMode parses the Cache-Control header and the refresh parameter
+func requestCacheMode(c *gin.Context) domain.CacheMode {
+	mode := domain.CacheDefault
+	for _, directive := range strings.Split(c.GetHeader("Cache-Control"), ",") {
+		switch strings.ToLower(strings.TrimSpace(directive)) {
+		case "no-store":
+			return domain.CacheNoStore
+		case "no-cache":
+			mode = domain.CacheNoCache
+		}
+	}
+	if refresh, _ := strconv.ParseBool(c.Query("refresh")); refresh {
+		mode = domain.CacheNoCache
+	}
+	return mode
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// ConcurrencyLimit rejects with 429 the requests of a client already running
+// limit requests through the route. Clients are told apart by user ID, or by
+// IP address when anonymous. The count is kept per instance. A limit of 0
+// disables the check.
+func ConcurrencyLimit(limit int) gin.HandlerFunc {
+	var (
+		mu      sync.Mutex
+		running = make(map[string]int)
+	)
+	return func(c *gin.Context) {
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+
+		client := "ip:" + c.ClientIP()
+		if principal := domain.PrincipalFromContext(c.Request.Context()); principal != nil {
+			client = "user:" + principal.UserID
+		}
+
+		mu.Lock()
+		if running[client] >= limit {
+			mu.Unlock()
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "too many concurrent requests"})
+			return
+		}
+		running[client]++
+		mu.Unlock()
+
+		defer func() {
+			mu.Lock()
+			if running[client]--; running[client] == 0 {
+				delete(running, client)
+			}
+			mu.Unlock()
+		}()
+		c.Next()
+	}
+}
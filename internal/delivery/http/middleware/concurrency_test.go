@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	const limit = 2
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if user := c.GetHeader("X-Test-User"); user != "" {
+			ctx := domain.WithPrincipal(c.Request.Context(), &domain.Principal{UserID: user})
+			c.Request = c.Request.WithContext(ctx)
+		}
+	})
+	router.POST("/import", ConcurrencyLimit(limit), func(c *gin.Context) {
+		if c.GetHeader("X-Test-Block") != "" {
+			entered <- struct{}{}
+			<-release
+		}
+		c.Status(http.StatusOK)
+	})
+	send := func(user string, block bool) int {
+		req := httptest.NewRequest(http.MethodPost, "/import", nil)
+		req.Header.Set("X-Test-User", user)
+		if block {
+			req.Header.Set("X-Test-Block", "1")
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Fill the limit of user-1 with requests that wait for release
+	var wg sync.WaitGroup
+	codes := make([]int, limit)
+	for i := range codes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes[i] = send("user-1", true)
+		}()
+		<-entered
+	}
+
+	if code := send("user-1", false); code != http.StatusTooManyRequests {
+		t.Errorf("request over the limit: status = %d, want %d", code, http.StatusTooManyRequests)
+	}
+	if code := send("user-2", false); code != http.StatusOK {
+		t.Errorf("request of another client: status = %d, want %d", code, http.StatusOK)
+	}
+
+	close(release)
+	wg.Wait()
+	for i, code := range codes {
+		if code != http.StatusOK {
+			t.Errorf("request %d within the limit: status = %d, want %d", i, code, http.StatusOK)
+		}
+	}
+	if code := send("user-1", false); code != http.StatusOK {
+		t.Errorf("request after the others finished: status = %d, want %d", code, http.StatusOK)
+	}
+}
+
+func TestConcurrencyLimitDisabled(t *testing.T) {
+	router := gin.New()
+	router.POST("/import", ConcurrencyLimit(0), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/import", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/1way-market/v3/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+func TestCORS(t *testing.T) {
+	tests := []struct {
+		name            string
+		cfg             config.CORSConfig
+		origin          string
+		wantOrigin      string
+		wantCredentials string
+	}{
+		{"denied by default", config.CORSConfig{}, "https://evil.example", "", ""},
+		{"listed", config.CORSConfig{AllowedOrigins: []string{"https://1way.market"}, AllowCredentials: true},
+			"https://1way.market", "https://1way.market", "true"},
+		{"unlisted", config.CORSConfig{AllowedOrigins: []string{"https://1way.market"}, AllowCredentials: true},
+			"https://evil.example", "", ""},
+		{"any", config.CORSConfig{AllowedOrigins: []string{"*"}}, "https://evil.example", "*", ""},
+		// Config.Validate rejects this, but the origin must not be echoed either way
+		{"any with credentials", config.CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true},
+			"https://evil.example", "*", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(CORS(tt.cfg))
+			router.GET("/v3/ads", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/v3/ads", nil)
+			req.Header.Set("Origin", tt.origin)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != tt.wantCredentials {
+				t.Errorf("Access-Control-Allow-Credentials = %q, want %q", got, tt.wantCredentials)
+			}
+		})
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	router := gin.New()
+	router.Use(CORS(config.CORSConfig{AllowedOrigins: []string{"https://1way.market"}, AllowedMethods: []string{"GET", "POST"}}))
+	router.POST("/v3/ads", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	for origin, want := range map[string]int{"https://1way.market": http.StatusNoContent, "https://evil.example": http.StatusForbidden} {
+		req := httptest.NewRequest(http.MethodOptions, "/v3/ads", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("preflight from %s: status = %d, want %d", origin, rec.Code, want)
+		}
+	}
+}
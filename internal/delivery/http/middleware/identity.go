@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/1way-market/v3/internal/auth"
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// UserIDHeader carries the authenticated user ID set by the API gateway
+const UserIDHeader = "X-User-ID"
+
+// Identity stores the caller's principal in the request context.
+// A bearer token is verified with verifier and supplies the user ID, roles and
+// seller ID from its sub, roles and seller_id claims; invalid or expired tokens
+// are rejected with 401. Without a token, the principal comes from the API
+// gateway when trustGateway is set, which forwards the user ID in
+// UserIDHeader; otherwise the request is anonymous. Only tokens grant roles
+// and a seller ID.
+func Identity(verifier *auth.Verifier, trustGateway bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var principal *domain.Principal
+		if token, ok := bearerToken(c); ok {
+			if verifier == nil {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token authentication is not configured"})
+				return
+			}
+			claims, err := verifier.Verify(c.Request.Context(), token)
+			if err != nil {
+				writeTokenError(c, err)
+				return
+			}
+			principal = &domain.Principal{UserID: claims.Subject, SellerID: claims.SellerID, Roles: claims.Roles}
+		} else if userID := c.GetHeader(UserIDHeader); trustGateway && userID != "" {
+			principal = &domain.Principal{UserID: userID}
+		}
+
+		if principal != nil {
+			ctx := domain.WithPrincipal(c.Request.Context(), principal)
+			c.Request = c.Request.WithContext(ctx)
+		}
+		c.Next()
+	}
+}
+
+// bearerToken returns the token of an "Authorization: Bearer" header
+func bearerToken(c *gin.Context) (string, bool) {
+	scheme, token, ok := strings.Cut(c.GetHeader("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+// writeTokenError rejects a request whose token could not be verified
+func writeTokenError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, auth.ErrExpiredToken):
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token expired"})
+	case errors.Is(err, auth.ErrInvalidToken):
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	default:
+		// The signing keys could not be fetched
+		log.Printf("Warning: token verification failed: %v", err)
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "token verification unavailable"})
+	}
+}
+
+// RequireUser rejects anonymous requests
+func RequireUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if domain.PrincipalFromContext(c.Request.Context()) == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireRole rejects anonymous requests and callers without any of the roles
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := domain.PrincipalFromContext(c.Request.Context())
+		if principal == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+			return
+		}
+		if !principal.HasAnyRole(roles...) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":    "insufficient role",
+				"required": roles,
+			})
+			return
+		}
+		c.Next()
+	}
+}
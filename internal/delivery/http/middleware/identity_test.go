@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/1way-market/v3/internal/auth"
+	"github.com/1way-market/v3/internal/auth/authtest"
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// newRoleRouter returns a router authenticating with verifier and guarding
+// routes as router.Setup does
+func newRoleRouter(verifier *auth.Verifier) *gin.Engine {
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router := gin.New()
+	router.Use(Identity(verifier, false))
+	router.GET("/v3/ads/stats", RequireUser(), ok)
+	router.POST("/v3/ads", RequireRole(domain.RoleSeller, domain.RoleParser), ok)
+	router.POST("/v3/ads/import", RequireRole(domain.RoleParser), ok)
+	router.GET("/v3/ads/:id/duplicates", RequireRole(domain.RoleModerator), ok)
+	router.PUT("/v3/ads/:id", RequireUser(), ok)
+	router.DELETE("/v3/ads/:id/images/:key", RequireUser(), ok)
+	router.DELETE("/v3/admin/categories/:id", RequireAdmin("admin-key"), ok)
+	return router
+}
+
+func TestRolePaths(t *testing.T) {
+	identity := authtest.New(t)
+	router := newRoleRouter(identity.Verifier())
+
+	expired := authtest.Claims("user-1", domain.RoleAdmin)
+	expired["exp"] = time.Now().Add(-time.Hour).Unix()
+	tokens := map[string]string{
+		"anonymous": "",
+		"user":      identity.Token(t, "user-1"),
+		"seller":    identity.Token(t, "user-1", domain.RoleSeller),
+		"parser":    identity.Token(t, "parser-1", domain.RoleParser),
+		"moderator": identity.Token(t, "user-2", domain.RoleModerator),
+		"admin":     identity.Token(t, "user-3", domain.RoleAdmin),
+		"expired":   identity.Sign(t, expired),
+		"malformed": "not-a-token",
+	}
+
+	tests := []struct {
+		method, target string
+		want           map[string]int
+	}{
+		{http.MethodGet, "/v3/ads/stats", map[string]int{
+			"anonymous": 401, "user": 200, "seller": 200, "admin": 200, "expired": 401, "malformed": 401,
+		}},
+		{http.MethodPost, "/v3/ads", map[string]int{
+			"anonymous": 401, "user": 403, "seller": 200, "parser": 200, "moderator": 403, "expired": 401, "malformed": 401,
+		}},
+		{http.MethodPost, "/v3/ads/import", map[string]int{
+			"anonymous": 401, "seller": 403, "parser": 200, "admin": 403,
+		}},
+		{http.MethodGet, "/v3/ads/1/duplicates", map[string]int{
+			"anonymous": 401, "seller": 403, "moderator": 200, "admin": 403,
+		}},
+		{http.MethodPut, "/v3/ads/1", map[string]int{
+			"anonymous": 401, "user": 200, "seller": 200, "expired": 401, "malformed": 401,
+		}},
+		{http.MethodDelete, "/v3/ads/1/images/photo.jpg", map[string]int{
+			"anonymous": 401, "user": 200, "expired": 401,
+		}},
+		{http.MethodDelete, "/v3/admin/categories/1", map[string]int{
+			"anonymous": 401, "seller": 403, "moderator": 403, "admin": 200, "expired": 401,
+		}},
+	}
+	for _, tt := range tests {
+		for caller, want := range tt.want {
+			t.Run(tt.method+" "+tt.target+" as "+caller, func(t *testing.T) {
+				req := httptest.NewRequest(tt.method, tt.target, nil)
+				if token := tokens[caller]; token != "" {
+					req.Header.Set("Authorization", "Bearer "+token)
+				}
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+				if rec.Code != want {
+					t.Errorf("status = %d, want %d: %s", rec.Code, want, rec.Body)
+				}
+			})
+		}
+	}
+}
+
+func TestRequireAdminKey(t *testing.T) {
+	router := newRoleRouter(nil)
+	for key, want := range map[string]int{"": 401, "wrong-key": 403, "admin-key": 200} {
+		req := httptest.NewRequest(http.MethodDelete, "/v3/admin/categories/1", nil)
+		if key != "" {
+			req.Header.Set(AdminKeyHeader, key)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("status with key %q = %d, want %d", key, rec.Code, want)
+		}
+	}
+}
+
+func TestIdentityPrincipal(t *testing.T) {
+	identity := authtest.New(t)
+	claims := authtest.Claims("user-1", domain.RoleSeller)
+	claims["seller_id"] = 7
+
+	var principal *domain.Principal
+	router := gin.New()
+	router.Use(Identity(identity.Verifier(), true))
+	router.GET("/", func(c *gin.Context) { principal = domain.PrincipalFromContext(c.Request.Context()) })
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+identity.Sign(t, claims))
+	// A token wins over the gateway headers, which grant no roles
+	req.Header.Set(UserIDHeader, "user-2")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if principal == nil || principal.UserID != "user-1" || principal.SellerID == nil || *principal.SellerID != 7 ||
+		!principal.HasAnyRole(domain.RoleSeller) {
+		t.Errorf("principal = %+v, want user-1 selling as 7 with the seller role", principal)
+	}
+}
+
+func TestIdentityGatewayHeaders(t *testing.T) {
+	tests := []struct {
+		name         string
+		trustGateway bool
+		want         *domain.Principal
+	}{
+		{"trusted gateway", true, &domain.Principal{UserID: "user-2"}},
+		{"forged without a gateway", false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var principal *domain.Principal
+			router := gin.New()
+			router.Use(Identity(nil, tt.trustGateway))
+			router.GET("/", func(c *gin.Context) { principal = domain.PrincipalFromContext(c.Request.Context()) })
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(UserIDHeader, "user-2")
+			// Only a verified token sets the seller
+			req.Header.Set("X-Seller-ID", "7")
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			if (principal == nil) != (tt.want == nil) || (principal != nil && (principal.UserID != tt.want.UserID || principal.SellerID != nil)) {
+				t.Errorf("principal = %+v, want %+v", principal, tt.want)
+			}
+		})
+	}
+}
+
+func TestForgedUserHeaderIsAnonymous(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/v3/ads/stats", nil)
+	req.Header.Set(UserIDHeader, "user-1")
+	rec := httptest.NewRecorder()
+	newRoleRouter(nil).ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d: %s", rec.Code, http.StatusUnauthorized, rec.Body)
+	}
+}
+
+func TestIdentityWithoutKeys(t *testing.T) {
+	identity := authtest.New(t)
+	token := identity.Token(t, "user-1", domain.RoleSeller)
+
+	tests := []struct {
+		name     string
+		verifier *auth.Verifier
+		want     int
+	}{
+		{"token authentication disabled", nil, http.StatusUnauthorized},
+		{"identity service down", auth.NewVerifier(auth.NewKeySet("http://127.0.0.1:1/jwks"), authtest.Issuer, authtest.Audience),
+			http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v3/ads", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			rec := httptest.NewRecorder()
+			newRoleRouter(tt.verifier).ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
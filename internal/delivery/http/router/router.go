@@ -1,31 +1,133 @@
 package router
 
 import (
+	"github.com/1way-market/v3/internal/auth"
+	"github.com/1way-market/v3/internal/config"
 	"github.com/1way-market/v3/internal/delivery/http/handler"
+	"github.com/1way-market/v3/internal/delivery/http/middleware"
+	"github.com/1way-market/v3/internal/domain"
 	"github.com/1way-market/v3/internal/usecase"
 	"github.com/gin-gonic/gin"
 )
 
-func Setup(useCases *usecase.UseCases) *gin.Engine {
+// imageUploadOverhead is the body size allowed for the multipart framing of an image upload
+const imageUploadOverhead = 64 << 10
+
+func Setup(cfg *config.Config, useCases *usecase.UseCases) *gin.Engine {
 	r := gin.New()
 	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
+	// Registered globally so preflight requests reach it even without an OPTIONS route
+	r.Use(middleware.CORS(cfg.CORS))
+	// Image uploads get room for the image and the multipart framing around it,
+	// imports for their batch of ads
+	r.Use(middleware.BodyLimit(cfg.MaxRequestBodySize, map[string]int64{
+		"/v3/ads/:id/images": cfg.MaxImageSize + imageUploadOverhead,
+		"/v3/ads/import":     cfg.MaxImportSize,
+	}))
+	r.Use(middleware.Compression(cfg.CompressionMinBytes))
+	r.Use(middleware.Identity(auth.NewJWKSVerifier(cfg.JWT.JWKSURL, cfg.JWT.Issuer, cfg.JWT.Audience), cfg.JWT.TrustGatewayHeaders))
+
+	// Health checks
+	healthHandler := handler.NewHealthHandler(useCases.HealthChecker)
+	r.GET("/health", healthHandler.Health)
+	r.GET("/health/live", healthHandler.Live)
+	r.GET("/health/ready", healthHandler.Ready)
 
-	// Health check
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "ok"})
-	})
+	adHandler := handler.NewAdHandler(useCases.AdUseCase, cfg.MaxImportItems)
+
+	// Debug endpoints expose query plans and are disabled in production by default
+	if cfg.DebugEndpoints {
+		r.GET("/debug/explain", adHandler.ExplainAds)
+	}
 
 	// API v3 routes
 	v3 := r.Group("/v3")
 	{
-		adHandler := handler.NewAdHandler(useCases.AdUseCase)
+		cacheControl := middleware.CacheControl(cfg.CacheBypassEnabled, cfg.AdminAPIKey)
 		ads := v3.Group("/ads")
 		{
-			ads.GET("", adHandler.GetAds)
-			ads.POST("", adHandler.CreateAd)
-			ads.PUT("/:id", adHandler.UpdateAd)
-			ads.DELETE("/:id", adHandler.DeleteAd)
+			ads.GET("", cacheControl, adHandler.GetAds)
+			ads.GET("/exists", adHandler.AdsExist)
+			ads.GET("/stats", middleware.RequireUser(), adHandler.GetAdsStats)
+			ads.GET("/suggest", adHandler.GetSuggestions)
+			ads.GET("/changes", middleware.RequireAdmin(cfg.AdminAPIKey), adHandler.GetAdChanges)
+			ads.GET("/export", middleware.RequireAdmin(cfg.AdminAPIKey), adHandler.Export)
+			ads.GET("/export.csv", middleware.RequireAdmin(cfg.AdminAPIKey), adHandler.ExportCSV)
+			ads.GET("/:id", cacheControl, adHandler.GetAd)
+			ads.HEAD("/:id", cacheControl, adHandler.HeadAd)
+			ads.GET("/:id/similar", adHandler.GetSimilarAds)
+			ads.GET("/:id/stats", middleware.RequireUser(), adHandler.GetAdStats)
+			ads.POST("/:id/reveal-phone", middleware.RequireUser(), adHandler.RevealPhone)
+			ads.POST("/:id/report", middleware.RequireUser(), adHandler.ReportAd)
+			ads.POST("", middleware.RequireRole(domain.RoleSeller, domain.RoleParser), adHandler.CreateAd)
+			ads.POST("/import", middleware.RequireRole(domain.RoleParser), middleware.ConcurrencyLimit(cfg.ImportConcurrency), adHandler.ImportAds)
+			ads.PUT("/:id", middleware.RequireUser(), adHandler.UpdateAd)
+			ads.DELETE("/:id", middleware.RequireUser(), adHandler.DeleteAd)
+			ads.POST("/:id/renew", middleware.RequireUser(), adHandler.RenewAd)
+			ads.GET("/:id/duplicates", middleware.RequireRole(domain.RoleModerator), adHandler.GetDuplicates)
+			ads.POST("/:id/not-duplicate", middleware.RequireRole(domain.RoleModerator), adHandler.ClearDuplicate)
+			ads.POST("/:id/images", middleware.RequireUser(), adHandler.UploadImage)
+			ads.PUT("/:id/images/order", middleware.RequireUser(), adHandler.ReorderImages)
+			ads.DELETE("/:id/images/:key", middleware.RequireUser(), adHandler.DeleteImage)
+		}
+
+		my := v3.Group("/my", middleware.RequireUser())
+		{
+			my.GET("/ads", adHandler.GetMyAds)
+		}
+
+		favoriteHandler := handler.NewFavoriteHandler(useCases.FavoriteUseCase)
+		favorites := v3.Group("", middleware.RequireUser())
+		{
+			favorites.POST("/ads/:id/favorite", favoriteHandler.AddFavorite)
+			favorites.DELETE("/ads/:id/favorite", favoriteHandler.RemoveFavorite)
+			favorites.GET("/me/favorites", favoriteHandler.ListFavorites)
+			favorites.POST("/favorites", favoriteHandler.CreateFavorite)
+			favorites.DELETE("/favorites/:id", favoriteHandler.RemoveFavorite)
+			favorites.GET("/favorites", favoriteHandler.ListFavorites)
+		}
+
+		admin := v3.Group("/admin", middleware.RequireAdmin(cfg.AdminAPIKey))
+		{
+			admin.GET("/ads/:id/raw_source", adHandler.GetRawSource)
+			admin.GET("/reports", adHandler.ListReports)
+			admin.GET("/ads", adHandler.GetAdminAds)
+			admin.POST("/ads/reindex-search-vectors", adHandler.RebuildSearchVectors)
+			admin.GET("/jobs/:job_id", adHandler.GetJob)
+		}
+
+		sellerHandler := handler.NewSellerHandler(useCases.SellerUseCase)
+		sellers := v3.Group("/sellers")
+		{
+			sellers.GET("/:id", sellerHandler.GetSeller)
+			sellers.GET("/:id/ads", sellerHandler.GetSellerAds)
+		}
+		sellerAdmin := v3.Group("/sellers", middleware.RequireAdmin(cfg.AdminAPIKey))
+		{
+			sellerAdmin.POST("", sellerHandler.CreateSeller)
+			sellerAdmin.PUT("/:id", sellerHandler.UpdateSeller)
+			sellerAdmin.DELETE("/:id", sellerHandler.DeleteSeller)
+		}
+
+		feedHandler := handler.NewFeedHandler(useCases.FeedUseCase)
+		feeds := v3.Group("/feeds")
+		{
+			feeds.GET("/ads.rss", feedHandler.AdsRSS)
+		}
+
+		categoryHandler := handler.NewCategoryHandler(useCases.CategoryUseCase)
+		categories := v3.Group("/categories")
+		{
+			categories.GET("/counts", categoryHandler.GetCounts)
+			categories.GET("/:id", categoryHandler.GetCategory)
+			categories.GET("/:id/path", categoryHandler.GetPath)
+		}
+		categoryAdmin := v3.Group("/admin/categories", middleware.RequireAdmin(cfg.AdminAPIKey))
+		{
+			categoryAdmin.POST("", categoryHandler.CreateCategory)
+			categoryAdmin.PUT("/:id", categoryHandler.UpdateCategory)
+			categoryAdmin.DELETE("/:id", categoryHandler.DeleteCategory)
 		}
 	}
 
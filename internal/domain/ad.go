@@ -3,71 +3,91 @@ package domain
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 )
 
-// MultiLangText represents text in a specific language
-type MultiLangText struct {
-	Lang int    `json:"lang"`
-	Text string `json:"text"`
+// Ad represents the main advertisement entity.
+// Phone is only accepted on create and update; it is stored encrypted in
+// PhoneEncrypted and served in full through a phone reveal. SearchRank is
+// only read, as the text search rank, when sorting by relevance. Active ads
+// past ExpiresAt are completed automatically. Images are only changed through
+// the image endpoints. OwnerID is the user who created the ad, who alone may
+// change it besides moderators. DuplicateOf is the ad a parser import was found
+// to duplicate.
+type Ad struct {
+	ID             uint           `json:"id" gorm:"primaryKey"`
+	Title          MultiLangArray `json:"title_multi" gorm:"type:jsonb;not null;column:title" validate:"required,min=1,max=5,unique=Lang,dive"`
+	Description    MultiLangArray `json:"body_multi,omitempty" gorm:"type:jsonb;column:description" validate:"max=5,unique=Lang,dive"`
+	Properties     AdProperties   `json:"properties,omitempty" gorm:"type:jsonb" validate:"max=100,dive"`
+	CategoryIDs    []int          `json:"category_ids,omitempty" gorm:"type:integer[]" validate:"max=20"`
+	Media          Media          `json:"media,omitempty" gorm:"type:jsonb" validate:"max=20,dive"`
+	Images         Images         `json:"images,omitempty" gorm:"type:jsonb"`
+	Status         AdStatus       `json:"status" gorm:"type:integer;index;default:0"`
+	StatusReason   string         `json:"status_reason,omitempty"`
+	Price          *Price         `json:"price,omitempty" gorm:"type:jsonb"`
+	SellerID       *uint          `json:"seller_id,omitempty"`
+	OwnerID        string         `json:"owner_id,omitempty" gorm:"default:null"`
+	DuplicateOf    *uint          `json:"duplicate_of,omitempty"`
+	Slug           string         `json:"slug,omitempty"`
+	Phone          string         `json:"phone,omitempty" gorm:"-"`
+	PhoneEncrypted string         `json:"-"`
+	PhoneMasked    string         `json:"phone_masked,omitempty"`
+	ViewCount      int64          `json:"view_count" gorm:"default:0"`
+	Version        int            `json:"version" gorm:"default:1"`
+	ExpiresAt      *time.Time     `json:"expires_at,omitempty"`
+	FavoriteCount  *int64         `json:"favorite_count,omitempty" gorm:"-"`
+	IsFavorited    *bool          `json:"is_favorited,omitempty" gorm:"-"`
+	SearchVector   string         `json:"-" gorm:"type:tsvector"`
+	RawSource      RawSource      `json:"-" gorm:"type:jsonb"`
+	SearchRank     float64        `json:"-" gorm:"->;column:search_rank"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
 }
 
-// MultiLangArray represents an array of multilingual texts
-type MultiLangArray []MultiLangText
+// RawSource is the original parser payload an ad was ingested from
+type RawSource json.RawMessage
 
 // Value implements the driver.Valuer interface for JSONB storage
-func (m MultiLangArray) Value() (driver.Value, error) {
-	return json.Marshal(m)
+func (r RawSource) Value() (driver.Value, error) {
+	if len(r) == 0 {
+		return nil, nil
+	}
+	return string(r), nil
 }
 
 // Scan implements the sql.Scanner interface for JSONB storage
-func (m *MultiLangArray) Scan(value interface{}) error {
-	if value == nil {
-		return nil
-	}
-	bytes, ok := value.([]byte)
-	if !ok {
-		return nil
+func (r *RawSource) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*r = nil
+	case []byte:
+		*r = append(RawSource(nil), v...)
+	case string:
+		*r = RawSource(v)
 	}
-	return json.Unmarshal(bytes, &m)
+	return nil
 }
 
-// Ad represents the main advertisement entity
-type Ad struct {
-	ID           uint           `json:"id" gorm:"primaryKey"`
-	Title        MultiLangArray `json:"title_multi" gorm:"type:jsonb;not null;column:title"`
-	Description  MultiLangArray `json:"body_multi,omitempty" gorm:"type:jsonb;column:description"`
-	Properties   AdProperties   `json:"properties,omitempty" gorm:"type:jsonb"`
-	CategoryIDs  []int          `json:"category_ids,omitempty" gorm:"type:integer[]"`
-	Status       AdStatus       `json:"status" gorm:"type:integer;index;default:0"`
-	Price        *Price         `json:"price,omitempty" gorm:"type:jsonb"`
-	SearchVector string         `json:"-" gorm:"type:tsvector"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-}
-
-// GetText returns the text for the specified language, falling back to English if not found
-func (m MultiLangArray) GetText(lang int) string {
-	// First try to find exact match
-	for _, t := range m {
-		if t.Lang == lang {
-			return t.Text
-		}
-	}
-
-	// Fallback to English (lang 2)
-	for _, t := range m {
-		if t.Lang == 2 {
-			return t.Text
-		}
+// MarshalJSON returns the payload as is
+func (r RawSource) MarshalJSON() ([]byte, error) {
+	if len(r) == 0 {
+		return []byte("null"), nil
 	}
+	return r, nil
+}
 
-	// If no English, return the first available text
-	if len(m) > 0 {
-		return m[0].Text
+// UnmarshalJSON stores a copy of the payload
+func (r *RawSource) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*r = nil
+		return nil
 	}
-
-	return ""
+	*r = append(RawSource(nil), data...)
+	return nil
 }
 
 // FilterRequest represents the query parameters for ad filtering
@@ -78,11 +98,226 @@ type FilterRequest struct {
 	SortBy          string           `form:"sort"`
 	PageToken       string           `form:"next_page"`
 	PageSize        int              `form:"page_size"`
-	Lang            string           `form:"lang" binding:"required"`
-	MinPrice        *float64         `form:"min_price"`
-	MaxPrice        *float64         `form:"max_price"`
+	Lang            string           `form:"lang" binding:"required" validate:"required,language"`
+	View            string           `form:"view"`
+	LangMeta        bool             `form:"lang_meta"`
+	Fields          string           `form:"fields"`
+	MinPrice        *float64         `form:"min_price" validate:"omitempty,gte=0"`
+	MaxPrice        *float64         `form:"max_price" validate:"omitempty,gte=0"`
 	Currency        string           `form:"currency"`
-	Status          *AdStatus        `form:"status"`
+	RawStatus       string           `form:"status"`
+	SellerID        *uint            `form:"seller_id"`
+
+	// ExcludePriceOnRequest drops price-on-request ads; implied by MinPrice and MaxPrice
+	ExcludePriceOnRequest bool `form:"exclude_price_on_request"`
+	// IncludeExpired keeps ads past their expiry date, which are left out by default
+	IncludeExpired bool `form:"include_expired"`
+	// IncludeImages is all to list every image of the ads instead of the primary one
+	IncludeImages string `form:"include_images" validate:"omitempty,oneof=primary all"`
+
+	// Language is the parsed Lang
+	Language Language `form:"-"`
+	// Status is the parsed RawStatus, given as a name or a code
+	Status *AdStatus `form:"-"`
+	// SelectedFields are the validated JSON field names parsed from Fields
+	SelectedFields []string `form:"-"`
+	// Statuses restricts results to any of the given statuses, on top of Status
+	Statuses []AdStatus `form:"-"`
+	// OwnerID restricts results to the ads of a user; only set by the my ads
+	// and admin listings
+	OwnerID string `form:"-"`
+	// AnyStatus lifts the default visibility of the listing; only set by the
+	// admin listing
+	AnyStatus bool `form:"-"`
+}
+
+// ErrUnknownField is returned when a field selection names an unknown ad field
+var ErrUnknownField = errors.New("unknown field")
+
+var (
+	// ErrInvalidSort is returned for a sort mode that is not in validSortModes
+	ErrInvalidSort = errors.New("invalid sort: must be one of price_asc, price_desc, date_desc, date_asc, relevance")
+	// ErrRelevanceSortRequiresSearch is returned for the relevance sort without a text search to rank by
+	ErrRelevanceSortRequiresSearch = errors.New("sort=relevance requires a text search (q)")
+)
+
+// validSortModes are the accepted values of FilterRequest.SortBy; empty sorts by date_desc
+var validSortModes = map[string]bool{"price_asc": true, "price_desc": true, "date_desc": true, "date_asc": true, "relevance": true}
+
+// ValidateSort checks the filter's sort mode
+func (f FilterRequest) ValidateSort() error {
+	if f.SortBy == "" {
+		return nil
+	}
+	if !validSortModes[f.SortBy] {
+		return ErrInvalidSort
+	}
+	if f.SortBy == "relevance" && f.TextSearch == "" {
+		return ErrRelevanceSortRequiresSearch
+	}
+	return nil
+}
+
+// adFieldColumns maps the JSON field names of Ad to their database columns
+var adFieldColumns = map[string]string{
+	"id":            "id",
+	"title_multi":   "title",
+	"body_multi":    "description",
+	"properties":    "properties",
+	"category_ids":  "category_ids",
+	"media":         "media",
+	"images":        "images",
+	"status":        "status",
+	"price":         "price",
+	"seller_id":     "seller_id",
+	"owner_id":      "owner_id",
+	"duplicate_of":  "duplicate_of",
+	"slug":          "slug",
+	"phone_masked":  "phone_masked",
+	"view_count":    "view_count",
+	"version":       "version",
+	"status_reason": "status_reason",
+	"expires_at":    "expires_at",
+	"created_at":    "created_at",
+	"updated_at":    "updated_at",
+}
+
+// ParseAdFields parses a comma-separated list of Ad JSON field names into a
+// sorted list without duplicates
+func ParseAdFields(value string) ([]string, error) {
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		if _, ok := adFieldColumns[field]; !ok {
+			return nil, fmt.Errorf("%w %q", ErrUnknownField, field)
+		}
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+	slices.Sort(fields)
+	return fields, nil
+}
+
+// AdFieldColumns returns the database columns needed to serve the given JSON fields.
+// The id column is always included since pagination depends on it, and
+// expires_at since the listing cache does.
+func AdFieldColumns(fields []string) []string {
+	columns := []string{"id", "expires_at"}
+	for _, field := range fields {
+		if column := adFieldColumns[field]; !slices.Contains(columns, column) {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// Response views of ad listings
+const (
+	// ViewFull returns every translation of the multilingual fields
+	ViewFull = "full"
+	// ViewLocalized resolves multilingual fields to the requested language
+	ViewLocalized = "localized"
+)
+
+// LocalizedAd is an ad with its multilingual texts resolved to a single language
+type LocalizedAd struct {
+	ID uint `json:"id"`
+	// Lang is the language the title was resolved to, which differs from the
+	// requested one when a fallback was used
+	Lang        string              `json:"lang"`
+	Title       string              `json:"title"`
+	Description string              `json:"description,omitempty"`
+	Properties  []LocalizedProperty `json:"properties,omitempty"`
+	CategoryIDs []int               `json:"category_ids,omitempty"`
+	Status      AdStatus            `json:"status"`
+	Price       *Price              `json:"price,omitempty"`
+	PhoneMasked string              `json:"phone_masked,omitempty"`
+	Media       Media               `json:"media,omitempty"`
+	Images      Images              `json:"images,omitempty"`
+	IsFavorited *bool               `json:"is_favorited,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+	// LangMeta tells, per localized field, which language was served
+	LangMeta map[string]LangMeta `json:"_lang_meta,omitempty"`
+}
+
+// LangMeta describes the language a localized field was served in
+type LangMeta struct {
+	Requested string `json:"requested"`
+	Used      string `json:"used"`
+	Fallback  bool   `json:"fallback"`
+}
+
+// newLangMeta describes a field resolved to text for the requested language
+func newLangMeta(requested Language, text MultiLangText) LangMeta {
+	return LangMeta{
+		Requested: requested.Code(),
+		Used:      text.Lang.Code(),
+		Fallback:  text.Lang != requested,
+	}
+}
+
+// LocalizedProperty is an ad property with its display name
+type LocalizedProperty struct {
+	ID      uint   `json:"id"`
+	Name    string `json:"name,omitempty"`
+	Value   string `json:"value,omitempty"`
+	ValueID *uint  `json:"value_id,omitempty"`
+}
+
+// Localize resolves the ad's texts to the given language and names its
+// properties using propertyNames
+func (a Ad) Localize(lang Language, propertyNames map[uint]string) LocalizedAd {
+	title := a.Title.Resolve(lang)
+	description := a.Description.Resolve(lang)
+
+	meta := map[string]LangMeta{"title": newLangMeta(lang, title)}
+	if len(a.Description) > 0 {
+		meta["description"] = newLangMeta(lang, description)
+	}
+
+	var properties []LocalizedProperty
+	for _, prop := range a.Properties {
+		properties = append(properties, LocalizedProperty{
+			ID:      prop.ID,
+			Name:    propertyNames[prop.ID],
+			Value:   prop.Value,
+			ValueID: prop.ValueID,
+		})
+	}
+
+	return LocalizedAd{
+		ID:          a.ID,
+		Lang:        title.Lang.Code(),
+		Title:       title.Text,
+		Description: description.Text,
+		Properties:  properties,
+		CategoryIDs: a.CategoryIDs,
+		Status:      a.Status,
+		Price:       a.Price,
+		PhoneMasked: a.PhoneMasked,
+		Media:       a.Media,
+		Images:      a.Images,
+		IsFavorited: a.IsFavorited,
+		CreatedAt:   a.CreatedAt,
+		UpdatedAt:   a.UpdatedAt,
+		LangMeta:    meta,
+	}
+}
+
+// DefaultPageSize is the number of ads per page when the page size is not given
+const DefaultPageSize = 20
+
+// PageSizeError is returned for a page size outside of 1 to Max
+type PageSizeError struct {
+	Max int
+}
+
+func (e *PageSizeError) Error() string {
+	return fmt.Sprintf("page_size must be between 1 and %d", e.Max)
 }
 
 // PaginatedResponse represents a paginated list of ads
@@ -90,4 +325,33 @@ type PaginatedResponse struct {
 	Items      []Ad   `json:"items"`
 	NextPage   string `json:"next_page,omitempty"`
 	TotalCount int64  `json:"total_count"`
+	// Stale is set when the page was served from an expired cache entry
+	Stale bool `json:"-"`
+	// PageSize is the number of ads the page was requested with, after capping
+	PageSize int `json:"-"`
+	// Facets is set when the page was found through a search index
+	Facets map[string][]FacetCount `json:"facets,omitempty"`
+}
+
+// LocalizedPaginatedResponse represents a paginated list of localized ads
+type LocalizedPaginatedResponse struct {
+	Items      []LocalizedAd `json:"items"`
+	NextPage   string        `json:"next_page,omitempty"`
+	TotalCount int64         `json:"total_count"`
+	// Facets is set when the page was found through a search index
+	Facets map[string][]FacetCount `json:"facets,omitempty"`
+}
+
+// Localize resolves every ad on the page to the given language
+func (r PaginatedResponse) Localize(lang Language, propertyNames map[uint]string) *LocalizedPaginatedResponse {
+	items := make([]LocalizedAd, 0, len(r.Items))
+	for _, ad := range r.Items {
+		items = append(items, ad.Localize(lang, propertyNames))
+	}
+	return &LocalizedPaginatedResponse{
+		Items:      items,
+		NextPage:   r.NextPage,
+		TotalCount: r.TotalCount,
+		Facets:     r.Facets,
+	}
 }
@@ -0,0 +1,42 @@
+package domain
+
+import "context"
+
+// CacheMode tells how a request may use the cache
+type CacheMode int
+
+const (
+	// CacheDefault reads from and writes to the cache
+	CacheDefault CacheMode = iota
+	// CacheNoCache skips the cache read but stores the fresh result
+	CacheNoCache
+	// CacheNoStore neither reads from nor writes to the cache
+	CacheNoStore
+)
+
+// Cache statuses reported in CacheControl.Status
+const (
+	CacheHit    = "HIT"
+	CacheMiss   = "MISS"
+	CacheBypass = "BYPASS"
+)
+
+// CacheControl carries the cache mode of a request to the use cases,
+// which report back in Status how the cache was used
+type CacheControl struct {
+	Mode   CacheMode
+	Status string
+}
+
+type cacheControlKey struct{}
+
+// WithCacheControl returns a copy of ctx carrying the cache control
+func WithCacheControl(ctx context.Context, control *CacheControl) context.Context {
+	return context.WithValue(ctx, cacheControlKey{}, control)
+}
+
+// CacheControlFromContext returns the cache control stored in ctx, or nil when there is none
+func CacheControlFromContext(ctx context.Context) *CacheControl {
+	control, _ := ctx.Value(cacheControlKey{}).(*CacheControl)
+	return control
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrCategoryHasChildren is returned when deleting a category that still has subcategories
+	ErrCategoryHasChildren = errors.New("category has subcategories")
+	// ErrCategoryCycle is returned when moving a category under itself or one of its subcategories
+	ErrCategoryCycle = errors.New("a category cannot be moved under itself or its subcategories")
+)
+
+// Category is a node of the category tree that ads are filed under through
+// their category_ids. ParentID is nil for root categories; the ancestors of
+// every category are kept in category_closure.
+type Category struct {
+	ID        int            `json:"id" gorm:"primaryKey"`
+	ParentID  *int           `json:"parent_id"`
+	Name      MultiLangArray `json:"name_multi" gorm:"type:jsonb;not null" validate:"required,min=1,max=5,unique=Lang,dive"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
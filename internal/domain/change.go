@@ -0,0 +1,18 @@
+package domain
+
+// AdChange is an entry of the ads change feed: the current state of a changed
+// ad, or a tombstone with only the ID for a deleted one
+type AdChange struct {
+	*Ad
+	ID      uint `json:"id"`
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// AdChangesPage is a page of the ads change feed
+type AdChangesPage struct {
+	Items []AdChange `json:"items"`
+	// Next resumes right after the last item. It is set even when the page is
+	// empty so that consumers can keep polling with it.
+	Next    string `json:"next"`
+	HasMore bool   `json:"has_more"`
+}
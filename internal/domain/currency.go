@@ -2,31 +2,127 @@ package domain
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"strconv"
+	"strings"
 )
 
+// Currency is an ISO 4217 currency identified by its numeric code
+type Currency string
+
 // Currency codes according to ISO 4217
 const (
-	CurrencyUSD = "840" // United States Dollar
-	CurrencyEUR = "978" // Euro
-	CurrencyTRY = "949" // Turkish Lira
-	CurrencyRUB = "643" // Russian Ruble
-	CurrencyGBP = "826" // British Pound
+	CurrencyUSD Currency = "840" // United States Dollar
+	CurrencyEUR Currency = "978" // Euro
+	CurrencyTRY Currency = "949" // Turkish Lira
+	CurrencyRUB Currency = "643" // Russian Ruble
+	CurrencyGBP Currency = "826" // British Pound
+)
+
+var (
+	// ErrInvalidCurrency is returned for codes outside the ISO 4217 set
+	ErrInvalidCurrency = errors.New("invalid currency code")
+	// ErrCurrencyNotAllowed is returned when a category does not accept the price currency
+	ErrCurrencyNotAllowed = errors.New("currency not allowed in category")
+)
+
+// currencyAlpha maps ISO 4217 numeric codes to their alphabetic codes
+var currencyAlpha = map[Currency]string{
+	"008": "ALL", "012": "DZD", "032": "ARS", "036": "AUD", "044": "BSD",
+	"048": "BHD", "050": "BDT", "051": "AMD", "052": "BBD", "060": "BMD",
+	"064": "BTN", "068": "BOB", "072": "BWP", "084": "BZD", "090": "SBD",
+	"096": "BND", "104": "MMK", "108": "BIF", "116": "KHR", "124": "CAD",
+	"132": "CVE", "136": "KYD", "144": "LKR", "152": "CLP", "156": "CNY",
+	"170": "COP", "174": "KMF", "188": "CRC", "192": "CUP", "203": "CZK",
+	"208": "DKK", "214": "DOP", "222": "SVC", "230": "ETB", "232": "ERN",
+	"238": "FKP", "242": "FJD", "262": "DJF", "270": "GMD", "292": "GIP",
+	"320": "GTQ", "324": "GNF", "328": "GYD", "332": "HTG", "340": "HNL",
+	"344": "HKD", "348": "HUF", "352": "ISK", "356": "INR", "360": "IDR",
+	"364": "IRR", "368": "IQD", "376": "ILS", "388": "JMD", "392": "JPY",
+	"398": "KZT", "400": "JOD", "404": "KES", "408": "KPW", "410": "KRW",
+	"414": "KWD", "417": "KGS", "418": "LAK", "422": "LBP", "426": "LSL",
+	"430": "LRD", "434": "LYD", "446": "MOP", "454": "MWK", "458": "MYR",
+	"462": "MVR", "480": "MUR", "484": "MXN", "496": "MNT", "498": "MDL",
+	"504": "MAD", "512": "OMR", "516": "NAD", "524": "NPR", "532": "ANG",
+	"533": "AWG", "548": "VUV", "554": "NZD", "558": "NIO", "566": "NGN",
+	"578": "NOK", "586": "PKR", "590": "PAB", "598": "PGK", "600": "PYG",
+	"604": "PEN", "608": "PHP", "634": "QAR", "643": "RUB", "646": "RWF",
+	"654": "SHP", "682": "SAR", "690": "SCR", "702": "SGD", "704": "VND",
+	"706": "SOS", "710": "ZAR", "728": "SSP", "748": "SZL", "752": "SEK",
+	"756": "CHF", "760": "SYP", "764": "THB", "776": "TOP", "780": "TTD",
+	"784": "AED", "788": "TND", "800": "UGX", "807": "MKD", "818": "EGP",
+	"826": "GBP", "834": "TZS", "840": "USD", "858": "UYU", "860": "UZS",
+	"882": "WST", "886": "YER", "901": "TWD", "924": "ZWG", "925": "SLE",
+	"926": "VED", "927": "UYW", "928": "VES", "929": "MRU", "930": "STN",
+	"931": "CUC", "932": "ZWL", "933": "BYN", "934": "TMT", "936": "GHS",
+	"938": "SDG", "940": "UYI", "941": "RSD", "943": "MZN", "944": "AZN",
+	"946": "RON", "947": "CHE", "948": "CHW", "949": "TRY", "950": "XAF",
+	"951": "XCD", "952": "XOF", "953": "XPF", "967": "ZMW", "968": "SRD",
+	"969": "MGA", "970": "COU", "971": "AFN", "972": "TJS", "973": "AOA",
+	"975": "BGN", "976": "CDF", "977": "BAM", "978": "EUR", "979": "MXV",
+	"980": "UAH", "981": "GEL", "984": "BOV", "985": "PLN", "986": "BRL",
+	"990": "CLF", "994": "XSU", "997": "USN",
+}
+
+// currencyNumeric maps ISO 4217 alphabetic codes to their numeric codes
+var currencyNumeric = func() map[string]Currency {
+	m := make(map[string]Currency, len(currencyAlpha))
+	for numeric, alpha := range currencyAlpha {
+		m[alpha] = numeric
+	}
+	return m
+}()
+
+// ParseCurrency accepts either a numeric ("840") or alphabetic ("USD") ISO 4217 code
+func ParseCurrency(code string) (Currency, error) {
+	code = strings.TrimSpace(code)
+	if c := Currency(code); c.IsValid() {
+		return c, nil
+	}
+	if c, ok := currencyNumeric[strings.ToUpper(code)]; ok {
+		return c, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidCurrency, code)
+}
+
+// IsValid reports whether the currency is a known ISO 4217 numeric code
+func (c Currency) IsValid() bool {
+	_, ok := currencyAlpha[c]
+	return ok
+}
+
+// Alpha returns the alphabetic ISO 4217 code, or an empty string for unknown currencies
+func (c Currency) Alpha() string {
+	return currencyAlpha[c]
+}
+
+// PriceType tells how an ad is priced
+type PriceType int
+
+const (
+	PriceFixed     PriceType = 1 // Priced at Value
+	PriceFree      PriceType = 2 // Given away for free
+	PriceOnRequest PriceType = 3 // Price given on request, without a value
 )
 
+// ErrInvalidPriceType is returned for unknown price types
+var ErrInvalidPriceType = errors.New("invalid price type")
+
 // Price represents a monetary value with its currency
 type Price struct {
-	Value    float64 `json:"value"`
-	Currency string  `json:"currency"`
+	Value    float64   `json:"value" validate:"gte=0"`
+	Currency string    `json:"currency"`
+	Type     PriceType `json:"type"`
 }
 
-// UnmarshalJSON implements custom JSON unmarshaling to handle currency as both string and number
+// UnmarshalJSON implements custom JSON unmarshaling to handle currency as both string and number.
+// Prices without a type, including those stored before types existed, are fixed.
 func (p *Price) UnmarshalJSON(data []byte) error {
 	// Try to unmarshal into a temporary struct
 	var temp struct {
-		Value    float64     `json:"value"`
-		Currency json.Number `json:"currency"`
+		Value    float64         `json:"value"`
+		Currency json.RawMessage `json:"currency"`
+		Type     PriceType       `json:"type"`
 	}
 	if err := json.Unmarshal(data, &temp); err != nil {
 		return err
@@ -34,12 +130,25 @@ func (p *Price) UnmarshalJSON(data []byte) error {
 
 	p.Value = temp.Value
 
-	// Convert currency to string
-	if temp.Currency != "" {
-		if _, err := strconv.Atoi(string(temp.Currency)); err != nil {
-			return fmt.Errorf("invalid currency code: %v", temp.Currency)
+	// Accept the currency as a JSON number or as a numeric/alphabetic string
+	code := strings.Trim(string(temp.Currency), `"`)
+	if code != "" && code != "null" {
+		currency, err := ParseCurrency(code)
+		if err != nil {
+			return err
 		}
-		p.Currency = string(temp.Currency)
+		p.Currency = string(currency)
+	}
+
+	switch temp.Type {
+	case 0, PriceFixed:
+		p.Type = PriceFixed
+	case PriceFree, PriceOnRequest:
+		// Neither has an amount, so a stray value cannot affect filtering or sorting
+		p.Type = temp.Type
+		p.Value = 0
+	default:
+		return fmt.Errorf("%w: %d", ErrInvalidPriceType, temp.Type)
 	}
 
 	return nil
@@ -0,0 +1,13 @@
+package domain
+
+import "errors"
+
+// ErrNotDuplicate is returned when an ad not marked as a duplicate is cleared of it
+var ErrNotDuplicate = errors.New("ad is not marked as a duplicate")
+
+// AdDuplicates is the duplicate review of an ad: the ad it was found to
+// duplicate, if any, and the ads found to duplicate it
+type AdDuplicates struct {
+	DuplicateOf *Ad  `json:"duplicate_of,omitempty"`
+	Duplicates  []Ad `json:"duplicates"`
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "errors"
+
+var (
+	// ErrNotFound is returned when the requested entity does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned when an update is based on an outdated version of the entity
+	ErrConflict = errors.New("conflict: the entity was modified by another request")
+	// ErrDuplicate is returned when the entity would duplicate a unique value of another one
+	ErrDuplicate = errors.New("duplicate")
+	// ErrConstraint is returned when the entity violates a database constraint, such as a foreign key or check
+	ErrConstraint = errors.New("constraint violation")
+	// ErrForbidden is returned when the caller may not access the entity
+	ErrForbidden = errors.New("forbidden")
+	// ErrInvalidPageToken is returned for malformed pagination tokens
+	ErrInvalidPageToken = errors.New("invalid page token")
+	// ErrRequestInProgress is returned when a request with the same idempotency key has not finished yet
+	ErrRequestInProgress = errors.New("a request with this idempotency key is still in progress")
+	// ErrIdempotencyUnavailable is returned when idempotency keys cannot be
+	// checked, so a request carrying one cannot be run safely
+	ErrIdempotencyUnavailable = errors.New("idempotency keys are unavailable, retry later")
+)
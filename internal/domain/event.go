@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Types of the ad events published through the outbox
+const (
+	AdEventCreated       = "ad.created"
+	AdEventUpdated       = "ad.updated"
+	AdEventStatusChanged = "ad.status_changed"
+)
+
+// OutboxEvent is an ad event recorded with the change it describes and relayed
+// to the message broker at least once
+type OutboxEvent struct {
+	// Sequence increases with every event and orders them
+	Sequence int64  `json:"sequence" gorm:"column:id;primaryKey"`
+	Type     string `json:"type" gorm:"column:event_type"`
+	AdID     uint   `json:"ad_id"`
+	// Payload is the JSON of the ad as it was right after the change
+	Payload   json.RawMessage `json:"ad"`
+	CreatedAt time.Time       `json:"created_at"`
+}
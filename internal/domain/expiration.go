@@ -0,0 +1,9 @@
+package domain
+
+import "errors"
+
+// CompletedByExpiry is the status reason of ads completed when they expired
+const CompletedByExpiry = "expired"
+
+// ErrNotRenewable is returned when renewing an ad that is neither active nor expired
+var ErrNotRenewable = errors.New("only active and expired ads can be renewed")
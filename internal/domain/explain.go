@@ -0,0 +1,13 @@
+package domain
+
+import "encoding/json"
+
+// QueryPlan is the EXPLAIN ANALYZE output of an ads listing query
+type QueryPlan struct {
+	SQL             string          `json:"sql"`
+	EstimatedRows   float64         `json:"estimated_rows"`
+	ActualRows      float64         `json:"actual_rows"`
+	PlanningTimeMs  float64         `json:"planning_time_ms"`
+	ExecutionTimeMs float64         `json:"execution_time_ms"`
+	Plan            json.RawMessage `json:"plan"`
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+// Favorite represents an ad saved by a user
+type Favorite struct {
+	UserID    string    `json:"user_id" gorm:"primaryKey"`
+	AdID      uint      `json:"ad_id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+}
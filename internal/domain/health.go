@@ -0,0 +1,28 @@
+package domain
+
+// Health statuses of a single dependency
+const (
+	HealthUp   = "up"
+	HealthDown = "down"
+)
+
+// Overall health of the service
+const (
+	HealthHealthy   = "healthy"
+	HealthDegraded  = "degraded"
+	HealthUnhealthy = "unhealthy"
+)
+
+// DependencyHealth is the result of checking one dependency
+type DependencyHealth struct {
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// HealthReport is the health of the service and its dependencies
+type HealthReport struct {
+	DB      DependencyHealth `json:"db"`
+	Redis   DependencyHealth `json:"redis"`
+	Overall string           `json:"overall"`
+}
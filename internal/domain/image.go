@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// MaxAdImages caps the number of images of an ad
+const MaxAdImages = 20
+
+var (
+	// ErrUnsupportedImageType is returned for uploads that are not JPEG, PNG or GIF images
+	ErrUnsupportedImageType = errors.New("unsupported image type: must be JPEG, PNG or GIF")
+	// ErrImageTooLarge is returned for uploads above the configured image size
+	ErrImageTooLarge = errors.New("image too large")
+	// ErrTooManyImages is returned when adding an image to an ad that has MaxAdImages
+	ErrTooManyImages = errors.New("too many images")
+	// ErrStorageUnavailable is returned for image changes when no blob storage is configured
+	ErrStorageUnavailable = errors.New("image storage unavailable")
+)
+
+// Image is an uploaded image of an ad. Key names it among the ad's images and
+// in its storage object; the primary image is the first one.
+type Image struct {
+	Key       string `json:"key"`
+	URL       string `json:"url"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	IsPrimary bool   `json:"is_primary"`
+}
+
+// Images is the ordered list of an ad's images
+type Images []Image
+
+// Value implements the driver.Valuer interface for JSONB storage
+func (images Images) Value() (driver.Value, error) {
+	if images == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(images)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements the sql.Scanner interface for JSONB storage
+func (images *Images) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*images = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, images)
+	case string:
+		return json.Unmarshal([]byte(v), images)
+	}
+	return nil
+}
+
+// Primary returns the primary image alone, or nil when there are no images
+func (images Images) Primary() Images {
+	if len(images) == 0 {
+		return nil
+	}
+	return images[:1:1]
+}
+
+// Index returns the position of the image with the given key, or -1
+func (images Images) Index(key string) int {
+	for i, image := range images {
+		if image.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// MarkPrimary flags the first image as the primary one
+func (images Images) MarkPrimary() {
+	for i := range images {
+		images[i].IsPrimary = i == 0
+	}
+}
@@ -0,0 +1,20 @@
+package domain
+
+// ImportLineResult is the outcome of one line of an ads import: the ID of the
+// created ad, or why the line was not imported
+type ImportLineResult struct {
+	Line    int          `json:"line"`
+	ID      uint         `json:"id,omitempty"`
+	Error   string       `json:"error,omitempty"`
+	Details []FieldError `json:"details,omitempty"`
+}
+
+// ImportSummary reports the outcome of every non-empty line of an ads import
+type ImportSummary struct {
+	Created int                `json:"created"`
+	Failed  int                `json:"failed"`
+	Results []ImportLineResult `json:"results"`
+	// Error is set when the body could not be read to the end; the lines
+	// after the last result were not imported
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// JobSearchVectorRebuild is the type of the jobs recomputing the search vector of every ad
+const JobSearchVectorRebuild = "search_vector_rebuild"
+
+// Job statuses
+const (
+	JobRunning   = "running"
+	JobCompleted = "completed"
+	JobFailed    = "failed"
+)
+
+// Job is a background task started through the admin API. Processed counts
+// the items done so far out of Total, the number there were when it started.
+type Job struct {
+	ID         string     `json:"id"`
+	Type       string     `json:"type"`
+	Status     string     `json:"status"`
+	Processed  int64      `json:"processed"`
+	Total      int64      `json:"total"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
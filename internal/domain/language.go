@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Language represents the supported languages
+type Language int
+
+const (
+	LangRussian Language = 1
+	LangEnglish Language = 2
+	LangTurkish Language = 3
+)
+
+// ErrUnsupportedLanguage is returned for language codes the API does not serve
+var ErrUnsupportedLanguage = errors.New("unsupported language")
+
+// languageCodes maps API language codes to languages, in display order
+var languageCodes = []struct {
+	Code string
+	Lang Language
+}{
+	{"ru", LangRussian},
+	{"en", LangEnglish},
+	{"tr", LangTurkish},
+}
+
+// ParseLanguage accepts an API language code ("ru") or its numeric value ("1")
+func ParseLanguage(code string) (Language, error) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	for _, entry := range languageCodes {
+		if entry.Code == code {
+			return entry.Lang, nil
+		}
+	}
+	if n, err := strconv.Atoi(code); err == nil {
+		if lang := Language(n); lang.Code() != "" {
+			return lang, nil
+		}
+	}
+	return 0, fmt.Errorf("%w %q (supported: %s)", ErrUnsupportedLanguage, code, strings.Join(SupportedLanguageCodes(), ", "))
+}
+
+// SupportedLanguageCodes returns the language codes accepted by ParseLanguage
+func SupportedLanguageCodes() []string {
+	codes := make([]string, 0, len(languageCodes))
+	for _, entry := range languageCodes {
+		codes = append(codes, entry.Code)
+	}
+	return codes
+}
+
+// Code returns the API language code, or an empty string for unknown languages
+func (l Language) Code() string {
+	for _, entry := range languageCodes {
+		if entry.Lang == l {
+			return entry.Code
+		}
+	}
+	return ""
+}
+
+// MultiLangText represents text in a specific language
+type MultiLangText struct {
+	Lang Language `json:"lang" validate:"language"`
+	Text string   `json:"text" validate:"notblank"`
+}
+
+// MultiLangArray represents an array of multilingual texts
+type MultiLangArray []MultiLangText
+
+// Value implements the driver.Valuer interface for JSONB storage
+func (m MultiLangArray) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
+
+// Scan implements the sql.Scanner interface for JSONB storage
+func (m *MultiLangArray) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, &m)
+}
+
+// LangFallbackChain lists the languages tried, in order, when a text is not
+// available in the requested language
+type LangFallbackChain []Language
+
+// DefaultFallbackChain is the fallback chain used by GetText and Resolve
+var DefaultFallbackChain = LangFallbackChain{LangEnglish}
+
+// GetText returns the text for the specified language, falling back along DefaultFallbackChain
+func (m MultiLangArray) GetText(lang Language) string {
+	return m.GetTextWithChain(lang, DefaultFallbackChain)
+}
+
+// GetTextWithChain returns the text for the specified language, falling back along chain
+func (m MultiLangArray) GetTextWithChain(lang Language, chain LangFallbackChain) string {
+	return m.ResolveWithChain(lang, chain).Text
+}
+
+// Resolve returns the entry for the specified language, falling back along
+// DefaultFallbackChain. The returned Lang tells which one was used.
+func (m MultiLangArray) Resolve(lang Language) MultiLangText {
+	return m.ResolveWithChain(lang, DefaultFallbackChain)
+}
+
+// ResolveWithChain returns the entry for the specified language, falling back to
+// the languages of chain in order and then to the first available entry
+func (m MultiLangArray) ResolveWithChain(lang Language, chain LangFallbackChain) MultiLangText {
+	// First try to find exact match
+	for _, t := range m {
+		if t.Lang == lang {
+			return t
+		}
+	}
+
+	// Fallback along the chain
+	for _, fallback := range chain {
+		for _, t := range m {
+			if t.Lang == fallback {
+				return t
+			}
+		}
+	}
+
+	// If no fallback language, return the first available text
+	if len(m) > 0 {
+		return m[0]
+	}
+
+	return MultiLangText{}
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// Media types of ad attachments
+const (
+	MediaImage = "image"
+	MediaVideo = "video"
+)
+
+// MediaItem references an image or video of an ad hosted elsewhere
+type MediaItem struct {
+	URL    string `json:"url" validate:"required,http_url,max=2048"`
+	Type   string `json:"type" validate:"required,oneof=image video"`
+	Width  int    `json:"width,omitempty" validate:"gte=0"`
+	Height int    `json:"height,omitempty" validate:"gte=0"`
+}
+
+// Media is the ordered list of an ad's attachments, the first being its cover
+type Media []MediaItem
+
+// Value implements the driver.Valuer interface for JSONB storage
+func (m Media) Value() (driver.Value, error) {
+	if m == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements the sql.Scanner interface for JSONB storage
+func (m *Media) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, m)
+	case string:
+		return json.Unmarshal([]byte(v), m)
+	}
+	return nil
+}
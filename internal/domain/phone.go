@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidPhone is returned for phone numbers that are not 7 to 15 digits
+	ErrInvalidPhone = errors.New("invalid phone number")
+	// ErrNoPhone is returned when revealing the phone of an ad without one
+	ErrNoPhone = errors.New("ad has no phone")
+)
+
+// phoneVisibleLead and phoneVisibleTail are how many leading and trailing
+// digits a masked phone keeps, e.g. the country and area code and the last two
+const (
+	phoneVisibleLead = 4
+	phoneVisibleTail = 2
+)
+
+// ValidatePhone checks the phone has 7 to 15 digits, the E.164 limit, and
+// otherwise only a leading + and the separators " ()-"
+func ValidatePhone(phone string) error {
+	digits := 0
+	for i, r := range phone {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == '+' && i == 0:
+		case strings.ContainsRune(" ()-", r):
+		default:
+			return ErrInvalidPhone
+		}
+	}
+	if digits < 7 || digits > 15 {
+		return ErrInvalidPhone
+	}
+	return nil
+}
+
+// MaskPhone hides the middle digits of a valid phone and keeps its formatting,
+// e.g. "+7 (999) 123-45-12" becomes "+7 (999) ***-**-12"
+func MaskPhone(phone string) string {
+	digits := 0
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	// Short numbers keep fewer leading digits so that most of them stays hidden
+	lead := min(phoneVisibleLead, digits-phoneVisibleTail-4)
+
+	var masked strings.Builder
+	seen := 0
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			if seen >= lead && seen < digits-phoneVisibleTail {
+				r = '*'
+			}
+			seen++
+		}
+		masked.WriteRune(r)
+	}
+	return masked.String()
+}
+
+// PhoneReveal records a user viewing the phone of an ad
+type PhoneReveal struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    string    `json:"user_id"`
+	AdID      uint      `json:"ad_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AdStats are the engagement counters shown to the owner of an ad
+type AdStats struct {
+	AdID             uint  `json:"ad_id"`
+	PhoneRevealCount int64 `json:"phone_reveal_count"`
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"context"
+	"slices"
+)
+
+// Roles granted by the identity service
+const (
+	RoleSeller    = "seller"
+	RoleParser    = "parser"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// Principal identifies the caller of a request
+type Principal struct {
+	UserID string
+	// SellerID is the seller the user posts ads as, if any. Like the roles,
+	// it is only set from the seller_id claim of a verified token.
+	SellerID *uint
+	// Roles are only set for callers authenticated with a token
+	Roles []string
+}
+
+// HasAnyRole reports whether the principal has at least one of the roles
+func (p *Principal) HasAnyRole(roles ...string) bool {
+	for _, role := range roles {
+		if slices.Contains(p.Roles, role) {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the principal
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal stored in ctx, or nil for anonymous requests
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
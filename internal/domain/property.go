@@ -24,7 +24,7 @@ type PropertyValue struct {
 // AdProperty represents a property value for an ad
 type AdProperty struct {
 	ID      uint   `json:"ID"`
-	Value   string `json:"value,omitempty"`
+	Value   string `json:"value,omitempty" validate:"max=500"`
 	ValueID *uint  `json:"value_id,omitempty"`
 }
 
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrAlreadyReported is returned when a user reports the same ad twice
+var ErrAlreadyReported = errors.New("ad already reported by this user")
+
+// AutoRejectedByReports is the status reason of ads rejected for having too many reports
+const AutoRejectedByReports = "auto_rejected_by_reports"
+
+// ReportReason is why a user flagged an ad
+type ReportReason int
+
+const (
+	ReportReasonSpam              ReportReason = 1
+	ReportReasonFraud             ReportReason = 2
+	ReportReasonProhibitedContent ReportReason = 3
+	ReportReasonWrongCategory     ReportReason = 4
+	ReportReasonDuplicate         ReportReason = 5
+	ReportReasonOther             ReportReason = 99
+)
+
+// reportReasons lists every valid reason in code order
+var reportReasons = []ReportReason{
+	ReportReasonSpam,
+	ReportReasonFraud,
+	ReportReasonProhibitedContent,
+	ReportReasonWrongCategory,
+	ReportReasonDuplicate,
+	ReportReasonOther,
+}
+
+// String returns the string representation of the reason
+func (r ReportReason) String() string {
+	switch r {
+	case ReportReasonSpam:
+		return "spam"
+	case ReportReasonFraud:
+		return "fraud"
+	case ReportReasonProhibitedContent:
+		return "prohibited_content"
+	case ReportReasonWrongCategory:
+		return "wrong_category"
+	case ReportReasonDuplicate:
+		return "duplicate"
+	case ReportReasonOther:
+		return "other"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseReportReason accepts a reason name ("spam") or its numeric code ("1")
+func ParseReportReason(value string) (ReportReason, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for _, reason := range reportReasons {
+		if reason.String() == value || strconv.Itoa(int(reason)) == value {
+			return reason, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid report reason: %q", value)
+}
+
+// MarshalJSON implements json.Marshaler, rendering the numeric code
+func (r ReportReason) MarshalJSON() ([]byte, error) {
+	return json.Marshal(int(r))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting the numeric code or the name
+func (r *ReportReason) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		value = string(data)
+	}
+	parsed, err := ParseReportReason(value)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// ReportStatus is where a report is in the moderation queue
+type ReportStatus int
+
+const (
+	ReportStatusOpen      ReportStatus = 0 // Awaiting review
+	ReportStatusResolved  ReportStatus = 1 // Acted upon
+	ReportStatusDismissed ReportStatus = 2 // Reviewed, no action needed
+)
+
+// String returns the string representation of the status
+func (s ReportStatus) String() string {
+	switch s {
+	case ReportStatusOpen:
+		return "open"
+	case ReportStatusResolved:
+		return "resolved"
+	case ReportStatusDismissed:
+		return "dismissed"
+	default:
+		return "unknown"
+	}
+}
+
+// AdReport is a user flagging an ad for moderation
+type AdReport struct {
+	ID          uint         `json:"id" gorm:"primaryKey"`
+	AdID        uint         `json:"ad_id"`
+	ReporterID  string       `json:"reporter_id"`
+	Reason      ReportReason `json:"reason" validate:"required"`
+	Description string       `json:"description,omitempty" validate:"max=1000"`
+	Status      ReportStatus `json:"status"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+// AdReportPage is a page of the moderation queue
+type AdReportPage struct {
+	Items    []AdReport `json:"items"`
+	NextPage string     `json:"next_page,omitempty"`
+}
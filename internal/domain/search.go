@@ -0,0 +1,17 @@
+package domain
+
+// FacetCount is the number of matching ads having a value of a faceted field
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// SearchResult is a page of ad IDs found by a search index, in ranking order
+type SearchResult struct {
+	IDs []uint
+	// Next is the page token of the next page, empty on the last page
+	Next  string
+	Total int64
+	// Facets counts the matching ads per value of the faceted fields, by field name
+	Facets map[string][]FacetCount
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// Seller is a person or shop posting ads
+type Seller struct {
+	ID         uint           `json:"id" gorm:"primaryKey"`
+	Name       MultiLangArray `json:"name_multi" gorm:"type:jsonb;not null"`
+	Phone      string         `json:"phone,omitempty"`
+	Email      string         `json:"email,omitempty"`
+	IsVerified bool           `json:"is_verified"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
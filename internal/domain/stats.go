@@ -0,0 +1,26 @@
+package domain
+
+// MaxStatsDays caps how many days back the daily created-ads histogram goes
+const MaxStatsDays = 90
+
+// StatusCount is the number of ads with a status
+type StatusCount struct {
+	Status AdStatus `json:"status"`
+	Name   string   `json:"name"`
+	Count  int64    `json:"count"`
+}
+
+// DailyCount is the number of ads created on a UTC day
+type DailyCount struct {
+	// Date is the day as YYYY-MM-DD
+	Date  string `json:"date"`
+	Count int64  `json:"count"`
+}
+
+// AdsStats summarizes the ads for the ops dashboard
+type AdsStats struct {
+	ByStatus []StatusCount `json:"by_status"`
+	// CreatedDaily covers each of the last Days days, oldest first, including days without ads
+	CreatedDaily []DailyCount `json:"created_daily"`
+	Days         int          `json:"days"`
+}
@@ -3,6 +3,8 @@ package domain
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 )
 
 // AdStatus represents the status of an advertisement
@@ -46,18 +48,102 @@ func (s AdStatus) String() string {
 	}
 }
 
-// MarshalJSON implements json.Marshaler
+// ParseAdStatus accepts a status name ("active") or its numeric code ("3")
+func ParseAdStatus(value string) (AdStatus, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for status := StatusDraft; status <= StatusDuplicate; status++ {
+		if status.String() == value {
+			return status, nil
+		}
+	}
+	if n, err := strconv.Atoi(value); err == nil && n >= int(StatusDraft) && n <= int(StatusDuplicate) {
+		return AdStatus(n), nil
+	}
+	return 0, fmt.Errorf("invalid status: %q", value)
+}
+
+// MarshalJSON implements json.Marshaler. Statuses are stored and cached as
+// their numeric code; responses may use another StatusFormat.
 func (s AdStatus) MarshalJSON() ([]byte, error) {
 	return json.Marshal(int(s))
 }
 
-// UnmarshalJSON implements json.Unmarshaler
+// UnmarshalJSON implements json.Unmarshaler. Besides the numeric code it
+// accepts every StatusFormat: a name ("active") and an object ({"code":3,"name":"active"}).
 func (s *AdStatus) UnmarshalJSON(data []byte) error {
 	var status int
-	if err := json.Unmarshal(data, &status); err != nil {
-		return fmt.Errorf("invalid status: %v", err)
+	if err := json.Unmarshal(data, &status); err == nil {
+		*s = AdStatus(status)
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		parsed, err := ParseAdStatus(name)
+		if err != nil {
+			return err
+		}
+		*s = parsed
+		return nil
 	}
 
-	*s = AdStatus(status)
+	var object struct {
+		Code *int   `json:"code"`
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return fmt.Errorf("invalid status: %s", data)
+	}
+	if object.Code != nil {
+		*s = AdStatus(*object.Code)
+		return nil
+	}
+	parsed, err := ParseAdStatus(object.Name)
+	if err != nil {
+		return err
+	}
+	*s = parsed
 	return nil
 }
+
+// StatusFormat is how statuses are rendered in API responses
+type StatusFormat string
+
+const (
+	// StatusFormatCode renders the numeric code, e.g. 3
+	StatusFormatCode StatusFormat = "code"
+	// StatusFormatName renders the name, e.g. "active"
+	StatusFormatName StatusFormat = "name"
+	// StatusFormatObject renders both, e.g. {"code":3,"name":"active"}
+	StatusFormatObject StatusFormat = "object"
+)
+
+// ParseStatusFormat parses a status format, defaulting to StatusFormatCode
+func ParseStatusFormat(value string) (StatusFormat, error) {
+	switch format := StatusFormat(strings.ToLower(strings.TrimSpace(value))); format {
+	case "":
+		return StatusFormatCode, nil
+	case StatusFormatCode, StatusFormatName, StatusFormatObject:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid status format %q, expected code, name or object", value)
+	}
+}
+
+// StatusObject is the StatusFormatObject rendering of a status
+type StatusObject struct {
+	Code AdStatus `json:"code"`
+	Name string   `json:"name"`
+}
+
+// Format returns the status in the given format, ready to be marshaled
+func (s AdStatus) Format(format StatusFormat) interface{} {
+	switch format {
+	case StatusFormatName:
+		return s.String()
+	case StatusFormatObject:
+		return StatusObject{Code: s, Name: s.String()}
+	default:
+		return s
+	}
+}
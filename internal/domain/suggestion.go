@@ -0,0 +1,12 @@
+package domain
+
+// MinSuggestionPrefix is the shortest prefix, in characters, that search suggestions are given for
+const MinSuggestionPrefix = 2
+
+// Suggestion is an ad title offered while the user types a search
+type Suggestion struct {
+	// Text is the lowercased title in the requested language
+	Text string `json:"text"`
+	// Count is the number of active ads with this title
+	Count int64 `json:"count"`
+}
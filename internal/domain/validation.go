@@ -0,0 +1,173 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/go-playground/validator/v10/non-standard/validators"
+)
+
+const (
+	// maxTitleLength caps each translation of an ad title, in characters
+	maxTitleLength = 200
+	// maxDescriptionLength caps each translation of an ad description, in characters
+	maxDescriptionLength = 10000
+)
+
+// FieldError describes why a single field is invalid
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every invalid field of a validated value
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Field + ": " + field.Message
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// validate checks the validate struct tags of ads and filters
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+
+	// Report fields by the names clients use: the JSON name, or the query parameter
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form"} {
+			if name, _, _ := strings.Cut(field.Tag.Get(tag), ","); name != "" && name != "-" {
+				return name
+			}
+		}
+		return field.Name
+	})
+
+	// language accepts supported Language values and API language codes
+	v.RegisterValidation("language", func(fl validator.FieldLevel) bool {
+		switch value := fl.Field().Interface().(type) {
+		case Language:
+			return value.Code() != ""
+		case string:
+			_, err := ParseLanguage(value)
+			return err == nil
+		}
+		return false
+	})
+
+	// notblank rejects texts made only of whitespace, which required accepts
+	v.RegisterValidation("notblank", validators.NotBlank)
+
+	v.RegisterStructValidation(validateAdTexts, Ad{})
+	return v
+}
+
+// validateAdTexts checks the length of every translation of the ad's texts,
+// which tags cannot express for the elements of a slice of structs
+func validateAdTexts(sl validator.StructLevel) {
+	ad := sl.Current().Interface().(Ad)
+	for _, text := range ad.Title {
+		if utf8.RuneCountInString(text.Text) > maxTitleLength {
+			sl.ReportError(ad.Title, "title_multi", "Title", "max_text", fmt.Sprint(maxTitleLength))
+			break
+		}
+	}
+	for _, text := range ad.Description {
+		if utf8.RuneCountInString(text.Text) > maxDescriptionLength {
+			sl.ReportError(ad.Description, "body_multi", "Description", "max_text", fmt.Sprint(maxDescriptionLength))
+			break
+		}
+	}
+}
+
+// ValidateAd checks the ad's fields, returning a *ValidationError listing the invalid ones
+func ValidateAd(ad *Ad) error {
+	return validationError(validate.Struct(ad))
+}
+
+// ValidateCategory checks the category's fields, returning a *ValidationError listing the invalid ones
+func ValidateCategory(category *Category) error {
+	return validationError(validate.Struct(category))
+}
+
+// ValidateReport checks the report's fields, returning a *ValidationError listing the invalid ones
+func ValidateReport(report *AdReport) error {
+	return validationError(validate.Struct(report))
+}
+
+// ValidateFilter checks the filter's fields, returning a *ValidationError listing the invalid ones
+func ValidateFilter(f *FilterRequest) error {
+	return validationError(validate.Struct(f))
+}
+
+// validationError converts validator errors to a *ValidationError
+func validationError(err error) error {
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return err
+	}
+
+	result := &ValidationError{}
+	for _, fieldError := range fieldErrors {
+		result.Fields = append(result.Fields, FieldError{
+			Field:   validationFieldPath(fieldError),
+			Message: validationMessage(fieldError),
+		})
+	}
+	return result
+}
+
+// validationFieldPath returns the field path without the type name, e.g. title_multi[0].lang
+func validationFieldPath(fieldError validator.FieldError) string {
+	_, path, _ := strings.Cut(fieldError.Namespace(), ".")
+	return path
+}
+
+// validationMessage describes a failed validation tag
+func validationMessage(fieldError validator.FieldError) string {
+	param := fieldError.Param()
+	switch fieldError.Tag() {
+	case "required", "notblank":
+		return "is required"
+	case "min":
+		if fieldError.Kind() == reflect.Slice {
+			if param == "1" {
+				return "must have at least one entry"
+			}
+			return "must have at least " + param + " entries"
+		}
+		return "must be at least " + param
+	case "max":
+		switch fieldError.Kind() {
+		case reflect.Slice:
+			return "must have at most " + param + " entries"
+		case reflect.String:
+			return "must be at most " + param + " characters"
+		}
+		return "must be at most " + param
+	case "unique":
+		return "must not have two entries with the same " + strings.ToLower(param)
+	case "http_url":
+		return "must be an http or https URL"
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(param), ", ")
+	case "gte":
+		return "must be greater than or equal to " + param
+	case "max_text":
+		return "each translation must be at most " + param + " characters"
+	case "language":
+		return "must be one of " + strings.Join(SupportedLanguageCodes(), ", ")
+	default:
+		return "failed the " + fieldError.Tag() + " check"
+	}
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidateAdTexts(t *testing.T) {
+	valid := func() *Ad {
+		return &Ad{
+			Title:       MultiLangArray{{Lang: LangEnglish, Text: "Red bicycle"}, {Lang: LangRussian, Text: "Красный велосипед"}},
+			Description: MultiLangArray{{Lang: LangEnglish, Text: "Barely used"}},
+			CategoryIDs: []int{1},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(ad *Ad)
+		want   []FieldError
+	}{
+		{"valid", func(ad *Ad) {}, nil},
+		{"duplicate title lang", func(ad *Ad) {
+			ad.Title[1].Lang = LangEnglish
+		}, []FieldError{{"title_multi", "must not have two entries with the same lang"}}},
+		{"duplicate description lang", func(ad *Ad) {
+			ad.Description = append(ad.Description, MultiLangText{Lang: LangEnglish, Text: "Like new"})
+		}, []FieldError{{"body_multi", "must not have two entries with the same lang"}}},
+		{"missing title", func(ad *Ad) {
+			ad.Title = nil
+		}, []FieldError{{"title_multi", "is required"}}},
+		{"empty title", func(ad *Ad) {
+			ad.Title = MultiLangArray{}
+		}, []FieldError{{"title_multi", "must have at least one entry"}}},
+		{"blank title", func(ad *Ad) {
+			ad.Title[0].Text = " \t\n"
+		}, []FieldError{{"title_multi[0].text", "is required"}}},
+		{"only blank titles", func(ad *Ad) {
+			ad.Title = MultiLangArray{{Lang: LangEnglish, Text: "  "}}
+		}, []FieldError{{"title_multi[0].text", "is required"}}},
+		{"blank description", func(ad *Ad) {
+			ad.Description[0].Text = "   "
+		}, []FieldError{{"body_multi[0].text", "is required"}}},
+		{"title over length", func(ad *Ad) {
+			ad.Title[1].Text = strings.Repeat("я", maxTitleLength+1)
+		}, []FieldError{{"title_multi", "each translation must be at most 200 characters"}}},
+		{"title at length", func(ad *Ad) {
+			ad.Title[1].Text = strings.Repeat("я", maxTitleLength)
+		}, nil},
+		{"description over length", func(ad *Ad) {
+			ad.Description[0].Text = strings.Repeat("a", maxDescriptionLength+1)
+		}, []FieldError{{"body_multi", "each translation must be at most 10000 characters"}}},
+		{"media", func(ad *Ad) {
+			ad.Media = Media{{URL: "https://cdn.example.com/1.jpg", Type: MediaImage, Width: 800, Height: 600}}
+		}, nil},
+		{"invalid media", func(ad *Ad) {
+			ad.Media = Media{
+				{URL: "ftp://cdn.example.com/1.jpg", Type: MediaImage},
+				{URL: "https://cdn.example.com/2.gif", Type: "animation", Width: -1},
+			}
+		}, []FieldError{
+			{"media[0].url", "must be an http or https URL"},
+			{"media[1].type", "must be one of image, video"},
+			{"media[1].width", "must be greater than or equal to 0"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ad := valid()
+			tt.modify(ad)
+			err := ValidateAd(ad)
+
+			var got []FieldError
+			var validationErr *ValidationError
+			if errors.As(err, &validationErr) {
+				got = validationErr.Fields
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/nats-io/nats.go"
+)
+
+func testEvent() domain.OutboxEvent {
+	return domain.OutboxEvent{
+		Sequence:  1234,
+		Type:      domain.AdEventStatusChanged,
+		AdID:      42,
+		Payload:   json.RawMessage(`{"id":42,"status":3}`),
+		CreatedAt: time.Date(2026, 10, 15, 8, 30, 0, 0, time.UTC),
+	}
+}
+
+// decodeEvent decodes a published event, failing unless it matches want
+func decodeEvent(t *testing.T, data []byte, want domain.OutboxEvent) {
+	t.Helper()
+	var got domain.OutboxEvent
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Sequence != want.Sequence || got.Type != want.Type || got.AdID != want.AdID ||
+		string(got.Payload) != string(want.Payload) || !got.CreatedAt.Equal(want.CreatedAt) {
+		t.Errorf("event = %+v, want %+v", got, want)
+	}
+}
+
+func TestNATSMessage(t *testing.T) {
+	event := testEvent()
+	msg, err := natsMessage("ads.events", event)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg.Subject != "ads.events" {
+		t.Errorf("subject = %q", msg.Subject)
+	}
+	// The message ID lets JetStream drop events the relay publishes again
+	if got := msg.Header.Get(nats.MsgIdHdr); got != "1234" {
+		t.Errorf("message ID = %q, want the sequence", got)
+	}
+	if got := msg.Header.Get(typeHeader); got != domain.AdEventStatusChanged {
+		t.Errorf("type header = %q", got)
+	}
+	if got := msg.Header.Get(sequenceHeader); got != "1234" {
+		t.Errorf("sequence header = %q", got)
+	}
+	decodeEvent(t, msg.Data, event)
+}
+
+func TestKafkaMessage(t *testing.T) {
+	event := testEvent()
+	msg, err := kafkaMessage(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Keyed by ad, so that the events of an ad stay in order in one partition
+	if string(msg.Key) != "42" {
+		t.Errorf("key = %q, want the ad ID", msg.Key)
+	}
+	headers := make(map[string]string)
+	for _, header := range msg.Headers {
+		headers[header.Key] = string(header.Value)
+	}
+	if headers[typeHeader] != domain.AdEventStatusChanged || headers[sequenceHeader] != "1234" {
+		t.Errorf("headers = %v", headers)
+	}
+	decodeEvent(t, msg.Value, event)
+}
@@ -0,0 +1,68 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/segmentio/kafka-go"
+)
+
+// Kafka publishes ad events to a Kafka topic. Events are keyed by ad, so the
+// events of an ad land in one partition in sequence order.
+type Kafka struct {
+	writer *kafka.Writer
+}
+
+// NewKafka returns a publisher to the topic on the given brokers. Connections
+// are opened on the first publish.
+func NewKafka(brokers []string, topic string) *Kafka {
+	return &Kafka{writer: &kafka.Writer{
+		Addr:     kafka.TCP(brokers...),
+		Topic:    topic,
+		Balancer: &kafka.Hash{},
+		// Wait for every in-sync replica, so an acknowledged event survives
+		// the loss of the leader
+		RequiredAcks: kafka.RequireAll,
+		// The relay publishes one event at a time; don't wait for a batch to fill
+		BatchSize:    1,
+		WriteTimeout: publishTimeout,
+	}}
+}
+
+// Publish writes the event and waits for the brokers to acknowledge it
+func (p *Kafka) Publish(ctx context.Context, event domain.OutboxEvent) error {
+	msg, err := kafkaMessage(event)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+	if err := p.writer.WriteMessages(ctx, msg); err != nil {
+		return fmt.Errorf("error publishing to Kafka: %v", err)
+	}
+	return nil
+}
+
+// Close closes the connections to the brokers
+func (p *Kafka) Close() error {
+	return p.writer.Close()
+}
+
+func kafkaMessage(event domain.OutboxEvent) (kafka.Message, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return kafka.Message{}, fmt.Errorf("error encoding event: %v", err)
+	}
+	return kafka.Message{
+		Key:   []byte(strconv.FormatUint(uint64(event.AdID), 10)),
+		Value: data,
+		Headers: []kafka.Header{
+			{Key: typeHeader, Value: []byte(event.Type)},
+			{Key: sequenceHeader, Value: []byte(strconv.FormatInt(event.Sequence, 10))},
+		},
+	}, nil
+}
@@ -0,0 +1,87 @@
+// Package events publishes the ad events relayed from the outbox to message
+// brokers. Publishing returns once the broker has stored the event, so the
+// relay only marks acknowledged events sent.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/nats-io/nats.go"
+)
+
+// publishTimeout bounds the wait for the broker to acknowledge an event
+const publishTimeout = 10 * time.Second
+
+// Headers carrying the event's type and sequence, so that consumers can
+// filter without decoding the event
+const (
+	typeHeader     = "Event-Type"
+	sequenceHeader = "Event-Sequence"
+)
+
+// NATS publishes ad events to a JetStream subject, which a stream must
+// capture. Events carry their sequence as message ID, so JetStream drops the
+// copies the relay publishes again within the stream's duplicate window.
+type NATS struct {
+	conn    *nats.Conn
+	js      nats.JetStreamContext
+	subject string
+}
+
+// NewNATS connects to the NATS server at url. The connection is retried in
+// the background, so events published while the server is down fail and are
+// relayed again later.
+func NewNATS(url, subject string) (*NATS, error) {
+	conn, err := nats.Connect(url,
+		nats.Name("ads-outbox-relay"),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to NATS: %v", err)
+	}
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error opening JetStream: %v", err)
+	}
+	return &NATS{conn: conn, js: js, subject: subject}, nil
+}
+
+// Publish publishes the event and waits for JetStream to acknowledge it
+func (p *NATS) Publish(ctx context.Context, event domain.OutboxEvent) error {
+	msg, err := natsMessage(p.subject, event)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+	if _, err := p.js.PublishMsg(msg, nats.Context(ctx)); err != nil {
+		return fmt.Errorf("error publishing to NATS: %v", err)
+	}
+	return nil
+}
+
+// Close closes the connection once pending messages are flushed
+func (p *NATS) Close() error {
+	return p.conn.Drain()
+}
+
+func natsMessage(subject string, event domain.OutboxEvent) (*nats.Msg, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding event: %v", err)
+	}
+	msg := nats.NewMsg(subject)
+	msg.Data = data
+	sequence := strconv.FormatInt(event.Sequence, 10)
+	msg.Header.Set(nats.MsgIdHdr, sequence)
+	msg.Header.Set(typeHeader, event.Type)
+	msg.Header.Set(sequenceHeader, sequence)
+	return msg, nil
+}
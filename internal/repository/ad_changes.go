@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/1way-market/v3/internal/domain"
+)
+
+// ListChanges returns up to limit ads changed after the since cursor, from
+// the ad_changes journal written by the ads_record_change trigger.
+//
+// The journal is read in (txid, id) order and only up to the oldest
+// transaction still running. Every change a later read could see belongs to
+// a transaction at least that new, and so sorts after the returned cursor:
+// resuming from it never misses or repeats a journal entry, however many
+// changes share a timestamp or commit out of order. A long transaction holds
+// the feed back until it ends.
+//
+// Items carry the ad as it is when read, so an ad changed several times in a
+// page is listed once, at its last change, and an ad deleted since its
+// change is already a tombstone.
+func (r *AdRepository) ListChanges(ctx context.Context, since string, limit int) (*domain.AdChangesPage, error) {
+	cursor, err := decodeChangeCursor(since)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []struct {
+		ID      int64
+		TxID    int64 `gorm:"column:txid"`
+		AdID    uint
+		Deleted bool
+	}
+	err = r.db.WithContext(ctx).Table("ad_changes").
+		Select("id, txid, ad_id, deleted").
+		Where("(txid, id) > (?, ?)", cursor.TxID, cursor.ID).
+		Where("txid < txid_snapshot_xmin(txid_current_snapshot())").
+		Order("txid, id").
+		Limit(limit + 1).
+		Scan(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing ad changes: %v", err)
+	}
+
+	page := &domain.AdChangesPage{Items: []domain.AdChange{}}
+	if len(entries) > limit {
+		page.HasMore = true
+		entries = entries[:limit]
+	}
+	if len(entries) > 0 {
+		last := entries[len(entries)-1]
+		cursor = changeCursor{TxID: last.TxID, ID: last.ID}
+	}
+	if cursor.ID > 0 {
+		page.Next = cursor.encode()
+	}
+
+	// Keep the last change of each ad
+	lastIndex := make(map[uint]int, len(entries))
+	for i, entry := range entries {
+		lastIndex[entry.AdID] = i
+	}
+	var ids []uint
+	for i, entry := range entries {
+		if lastIndex[entry.AdID] == i && !entry.Deleted {
+			ids = append(ids, entry.AdID)
+		}
+	}
+
+	ads := make(map[uint]*domain.Ad, len(ids))
+	if len(ids) > 0 {
+		var found []domain.Ad
+		if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&found).Error; err != nil {
+			return nil, fmt.Errorf("error loading changed ads: %v", err)
+		}
+		for i := range found {
+			ads[found[i].ID] = &found[i]
+		}
+	}
+
+	for i, entry := range entries {
+		if lastIndex[entry.AdID] != i {
+			continue
+		}
+		change := domain.AdChange{ID: entry.AdID, Ad: ads[entry.AdID]}
+		change.Deleted = change.Ad == nil
+		page.Items = append(page.Items, change)
+	}
+	return page, nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/1way-market/v3/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AdReportRepository struct {
+	db *gorm.DB
+}
+
+func NewAdReportRepository(db *gorm.DB) *AdReportRepository {
+	return &AdReportRepository{db: db}
+}
+
+// Create saves the report, returning domain.ErrAlreadyReported when the
+// reporter already reported the ad
+func (r *AdReportRepository) Create(ctx context.Context, report *domain.AdReport) error {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(report)
+	if result.Error != nil {
+		return fmt.Errorf("error creating ad report: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrAlreadyReported
+	}
+	return nil
+}
+
+// CountByAd returns the number of reports of the ad
+func (r *AdReportRepository) CountByAd(ctx context.Context, adID uint) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.AdReport{}).Where("ad_id = ?", adID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("error counting ad reports: %v", err)
+	}
+	return count, nil
+}
+
+// ListOpen returns a page of the reports awaiting review, oldest first. The
+// page token is the ID of the last report of the previous page.
+func (r *AdReportRepository) ListOpen(ctx context.Context, pageSize int, pageToken string) (*domain.AdReportPage, error) {
+	query := r.db.WithContext(ctx).Where("status = ?", domain.ReportStatusOpen)
+	if pageToken != "" {
+		afterID, err := strconv.ParseUint(pageToken, 10, 32)
+		if err != nil {
+			return nil, domain.ErrInvalidPageToken
+		}
+		query = query.Where("id > ?", afterID)
+	}
+
+	reports := []domain.AdReport{}
+	if err := query.Order("id").Limit(pageSize + 1).Find(&reports).Error; err != nil {
+		return nil, fmt.Errorf("error listing ad reports: %v", err)
+	}
+
+	page := &domain.AdReportPage{Items: reports}
+	if len(reports) > pageSize {
+		page.Items = reports[:pageSize]
+		page.NextPage = strconv.FormatUint(uint64(reports[pageSize-1].ID), 10)
+	}
+	return page, nil
+}
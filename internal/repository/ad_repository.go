@@ -2,11 +2,20 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/1way-market/v3/internal/domain"
+	"github.com/1way-market/v3/internal/slug"
+	"github.com/lib/pq"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AdRepository struct {
@@ -17,38 +26,266 @@ func NewAdRepository(db *gorm.DB) *AdRepository {
 	return &AdRepository{db: db}
 }
 
+// priceValueExpr extracts the numeric price used for filtering and sorting.
+// Price-on-request ads have no price, so they fail price ranges and sort last.
+var priceValueExpr = fmt.Sprintf("(CASE WHEN price->>'type' = '%d' THEN NULL ELSE (price->>'value')::float END)",
+	domain.PriceOnRequest)
+
+// rankExpr ranks an ad against the text search of the filter, binding the
+// language and the search text. It is a float8 so that the rank round-trips
+// exactly through page tokens.
+const rankExpr = "ts_rank(search_vector, plainto_tsquery(ads_lang_regconfig(?), ?))::float8"
+
+// rankVars are the values bound by rankExpr
+func rankVars(filter domain.FilterRequest) []interface{} {
+	return []interface{}{int(filter.Language), filter.TextSearch}
+}
+
 func (r *AdRepository) FindWithFilter(ctx context.Context, filter domain.FilterRequest) (*domain.PaginatedResponse, error) {
 	var ads []domain.Ad
 	var totalCount int64
 
-	query := r.db.WithContext(ctx).Model(&domain.Ad{})
+	query, cursor, err := r.filterQuery(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Count total results
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, err
+	}
+
+	// Execute query
+	pageSize := filterPageSize(filter)
+	query, err = pageQuery(query, filter, cursor)
+	if err != nil {
+		return nil, err
+	}
+	if err := query.Find(&ads).Error; err != nil {
+		return nil, err
+	}
+
+	// Prepare response
+	response := &domain.PaginatedResponse{
+		TotalCount: totalCount,
+		PageSize:   pageSize,
+	}
+
+	if len(ads) > pageSize {
+		response.Items = ads[:pageSize]
+		response.NextPage = cursor.after(&ads[pageSize-1], filter.SortBy).encode()
+	} else {
+		response.Items = ads
+	}
+
+	return response, nil
+}
+
+// ExplainFindWithFilter runs EXPLAIN ANALYZE on the page query FindWithFilter
+// would execute for the filter. The statement runs in a read-only transaction
+// that is rolled back, so the database is never modified.
+func (r *AdRepository) ExplainFindWithFilter(ctx context.Context, filter domain.FilterRequest) (*domain.QueryPlan, error) {
+	query, cursor, err := r.filterQuery(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	query, err = pageQuery(query, filter, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the statement without executing it
+	var ads []domain.Ad
+	stmt := query.Session(&gorm.Session{DryRun: true}).Find(&ads).Statement
+	if stmt.Error != nil {
+		return nil, fmt.Errorf("error building ads query: %v", stmt.Error)
+	}
+
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("error getting database handle: %v", err)
+	}
+	tx, err := sqlDB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("error starting explain transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var output []byte
+	err = tx.QueryRowContext(ctx, "EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) "+stmt.SQL.String(), stmt.Vars...).Scan(&output)
+	if err != nil {
+		return nil, fmt.Errorf("error explaining ads query: %v", err)
+	}
+
+	var explained []struct {
+		Plan struct {
+			PlanRows   float64 `json:"Plan Rows"`
+			ActualRows float64 `json:"Actual Rows"`
+		} `json:"Plan"`
+		PlanningTime  float64 `json:"Planning Time"`
+		ExecutionTime float64 `json:"Execution Time"`
+	}
+	if err := json.Unmarshal(output, &explained); err != nil || len(explained) == 0 {
+		return nil, fmt.Errorf("error parsing query plan: %v", err)
+	}
+
+	return &domain.QueryPlan{
+		SQL:             r.db.Dialector.Explain(stmt.SQL.String(), stmt.Vars...),
+		EstimatedRows:   explained[0].Plan.PlanRows,
+		ActualRows:      explained[0].Plan.ActualRows,
+		PlanningTimeMs:  explained[0].PlanningTime,
+		ExecutionTimeMs: explained[0].ExecutionTime,
+		Plan:            output,
+	}, nil
+}
+
+// filterQuery builds the filtered ads query pinned to the snapshot of the page token.
+// The returned query has no pagination applied, so it can be counted.
+func (r *AdRepository) filterQuery(ctx context.Context, filter domain.FilterRequest) (*gorm.DB, adCursor, error) {
+	// Pin the result set to ads that existed when the first page was requested,
+	// so ads created while paging do not shift later pages
+	cursor := adCursor{SnapshotAt: time.Now().UnixMicro()}
+	if filter.PageToken != "" {
+		var err error
+		if cursor, err = decodeAdCursor(filter.PageToken); err != nil {
+			return nil, cursor, err
+		}
+	}
+
+	query := applyFilter(r.db.WithContext(ctx).Model(&domain.Ad{}), filter).
+		Where("created_at <= ?", time.UnixMicro(cursor.SnapshotAt))
+	return query, cursor, nil
+}
+
+// filterPageSize returns the requested page size or the default of 20
+func filterPageSize(filter domain.FilterRequest) int {
+	if filter.PageSize == 0 {
+		return domain.DefaultPageSize
+	}
+	return filter.PageSize
+}
+
+// pageQuery restricts a filtered query to the page after the cursor. One extra
+// row is fetched to tell whether a next page exists.
+func pageQuery(query *gorm.DB, filter domain.FilterRequest, cursor adCursor) (*gorm.DB, error) {
+	if cursor.ID != 0 {
+		// A token from a differently sorted listing cannot be resumed
+		if cursor.Sort != filter.SortBy {
+			return nil, domain.ErrInvalidPageToken
+		}
+		query = applyCursor(query, filter, cursor)
+	}
+
+	// Only fetch the requested columns; selected after counting so Count stays COUNT(*).
+	// The sort columns are always fetched since the next page token encodes them.
+	selected := "*"
+	if len(filter.SelectedFields) > 0 {
+		columns := domain.AdFieldColumns(filter.SelectedFields)
+		for _, column := range []string{"created_at", "price"} {
+			if !slices.Contains(columns, column) {
+				columns = append(columns, column)
+			}
+		}
+		selected = strings.Join(columns, ", ")
+	}
+	if filter.SortBy == "relevance" {
+		query = query.Select(selected+", "+rankExpr+" AS search_rank", rankVars(filter)...)
+	} else if selected != "*" {
+		query = query.Select(selected)
+	}
+
+	return applySort(query, filter).Limit(filterPageSize(filter) + 1), nil
+}
+
+// applySort orders the query by the requested sort mode, breaking ties by id
+// so that keyset pagination is deterministic
+func applySort(query *gorm.DB, filter domain.FilterRequest) *gorm.DB {
+	switch filter.SortBy {
+	case "price_asc":
+		return query.Order(priceValueExpr + " ASC NULLS LAST").Order("id ASC")
+	case "price_desc":
+		return query.Order(priceValueExpr + " DESC NULLS LAST").Order("id DESC")
+	case "date_asc":
+		return query.Order("created_at ASC").Order("id ASC")
+	case "relevance":
+		// Order only takes raw strings, so the bound rank goes in as a whole ORDER BY clause
+		return query.Clauses(clause.OrderBy{Expression: clause.Expr{SQL: rankExpr + " DESC, id DESC", Vars: rankVars(filter)}})
+	default:
+		return query.Order("created_at DESC").Order("id DESC")
+	}
+}
+
+// applyCursor restricts the query to rows sorted after the cursor position.
+// Each branch compares the primary sort value and then the id, mirroring applySort.
+func applyCursor(query *gorm.DB, filter domain.FilterRequest, cursor adCursor) *gorm.DB {
+	switch sortBy := filter.SortBy; sortBy {
+	case "date_asc":
+		return query.Where("(created_at, id) > (?, ?)", time.UnixMicro(cursor.CreatedAt), cursor.ID)
+	case "relevance":
+		var rank float64
+		if cursor.Rank != nil {
+			rank = *cursor.Rank
+		}
+		lang, text := int(filter.Language), filter.TextSearch
+		return query.Where("("+rankExpr+" < ? OR ("+rankExpr+" = ? AND id < ?))", lang, text, rank, lang, text, rank, cursor.ID)
+	case "price_asc", "price_desc":
+		op := ">"
+		if sortBy == "price_desc" {
+			op = "<"
+		}
+		// Ads without a price sort last in both directions
+		if cursor.Price == nil {
+			return query.Where(priceValueExpr+" IS NULL AND id "+op+" ?", cursor.ID)
+		}
+		return query.Where(
+			"("+priceValueExpr+" "+op+" ? OR ("+priceValueExpr+" = ? AND id "+op+" ?) OR "+priceValueExpr+" IS NULL)",
+			*cursor.Price, *cursor.Price, cursor.ID)
+	default:
+		return query.Where("(created_at, id) < (?, ?)", time.UnixMicro(cursor.CreatedAt), cursor.ID)
+	}
+}
 
+// applyFilter adds the WHERE conditions shared by all filtered ad queries
+func applyFilter(query *gorm.DB, filter domain.FilterRequest) *gorm.DB {
 	// Apply category filter
 	if len(filter.CategoryIDs) > 0 {
-		query = query.Where("category_ids && ?", filter.CategoryIDs)
+		// The IDs are bound as one array; GORM would expand a bare slice into a list
+		query = query.Where("category_ids && ?::integer[]", pq.Array(filter.CategoryIDs))
 	}
 
 	// Apply text search if provided
 	if filter.TextSearch != "" {
-		query = query.Where("search_vector @@ plainto_tsquery(?)", filter.TextSearch)
+		// Parse the query with the text search configuration of the requested language
+		query = query.Where("search_vector @@ plainto_tsquery(ads_lang_regconfig(?), ?)", int(filter.Language), filter.TextSearch)
+	}
+
+	if filter.SellerID != nil {
+		query = query.Where("seller_id = ?", *filter.SellerID)
+	}
+	if filter.OwnerID != "" {
+		query = query.Where("owner_id = ?", filter.OwnerID)
 	}
 
 	if filter.Status != nil {
 		query = query.Where("status = ?", *filter.Status)
 	}
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+
+	// Expired ads may still be active until the expiration worker completes them
+	if !filter.IncludeExpired {
+		query = query.Where("(expires_at IS NULL OR expires_at > NOW())")
+	}
 
 	// Apply property filters
 	for _, prop := range filter.PropertyFilters {
-		// Filter by primitive values
-		if len(prop.Values) > 0 {
-			query = query.Where("EXISTS (SELECT 1 FROM jsonb_array_elements(properties) props WHERE props->>'ID' = ? AND props->>'value' = ANY(?))",
-				prop.PropertyID, prop.Values)
-		}
-		// Filter by reference values
-		if len(prop.ValueIDs) > 0 {
-			query = query.Where("EXISTS (SELECT 1 FROM jsonb_array_elements(properties) props WHERE props->>'ID' = ? AND (props->>'value_id')::int = ANY(?))",
-				prop.PropertyID, prop.ValueIDs)
-		}
+		query = applyPropertyFilter(query, prop)
+	}
+
+	// Price ranges never match price-on-request ads
+	if filter.ExcludePriceOnRequest || filter.MinPrice != nil || filter.MaxPrice != nil {
+		query = query.Where("price->>'type' IS DISTINCT FROM ?", strconv.Itoa(int(domain.PriceOnRequest)))
 	}
 
 	// Apply price filters
@@ -57,223 +294,746 @@ func (r *AdRepository) FindWithFilter(ctx context.Context, filter domain.FilterR
 			query = query.Where("price->>'currency' = ?", filter.Currency)
 		}
 		if filter.MinPrice != nil {
-			query = query.Where("(price->>'value')::float >= ?", *filter.MinPrice)
+			query = query.Where(priceValueExpr+" >= ?", *filter.MinPrice)
 		}
 		if filter.MaxPrice != nil {
-			query = query.Where("(price->>'value')::float <= ?", *filter.MaxPrice)
+			query = query.Where(priceValueExpr+" <= ?", *filter.MaxPrice)
 		}
 	}
 
-	// Apply sorting
-	switch filter.SortBy {
-	case "price_asc":
-		query = query.Order("(price->>'value')::float ASC NULLS LAST")
-	case "price_desc":
-		query = query.Order("(price->>'value')::float DESC NULLS LAST")
-	case "date_desc":
-		query = query.Order("created_at DESC")
-	default:
-		query = query.Order("created_at DESC")
+	return query
+}
+
+// Property filter JSON paths, matching an element of the properties array with the
+// filtered ID and any of the filtered values. The paths are bound as parameters since
+// their "?" filter operator would otherwise be taken for a placeholder.
+const (
+	propertyValuesPath   = "$[*] ? (@.ID == $id && @.value == $values[*])"
+	propertyValueIDsPath = "$[*] ? (@.ID == $id && @.value_id == $value_ids[*])"
+)
+
+// applyPropertyFilter restricts the query to ads having the property with one of the
+// filtered values. User input only reaches the query as jsonpath variables.
+func applyPropertyFilter(query *gorm.DB, prop domain.PropertyFilter) *gorm.DB {
+	if len(prop.Values) == 0 && len(prop.ValueIDs) == 0 {
+		return query
 	}
 
-	// Count total results
-	if err := query.Count(&totalCount).Error; err != nil {
-		return nil, err
+	// Containment on the property ID lets the GIN index on properties narrow the rows
+	contains, _ := json.Marshal([]map[string]uint{{"ID": prop.PropertyID}})
+	query = query.Where("properties @> ?::jsonb", string(contains))
+
+	// Filter by primitive values
+	if len(prop.Values) > 0 {
+		vars, _ := json.Marshal(map[string]interface{}{"id": prop.PropertyID, "values": prop.Values})
+		query = query.Where("jsonb_path_exists(properties, ?::jsonpath, ?::jsonb)", propertyValuesPath, string(vars))
+	}
+	// Filter by reference values
+	if len(prop.ValueIDs) > 0 {
+		vars, _ := json.Marshal(map[string]interface{}{"id": prop.PropertyID, "value_ids": prop.ValueIDs})
+		query = query.Where("jsonb_path_exists(properties, ?::jsonpath, ?::jsonb)", propertyValueIDsPath, string(vars))
 	}
+	return query
+}
+
+// exportBatchSize is the number of ads read per query of an export
+const exportBatchSize = 1000
 
-	// Apply pagination
-	pageSize := filter.PageSize
-	if pageSize == 0 {
-		pageSize = 20
+// Export streams the ads matching the filter, in its sort order, to fn without
+// loading the whole result into memory. At most limit ads are read.
+//
+// The ads are read in batches, each resuming after the last ad of the previous
+// one like the pages of FindWithFilter, so no query stays open for the whole
+// export. Ads created after the export started are left out.
+func (r *AdRepository) Export(ctx context.Context, filter domain.FilterRequest, limit int, fn func(*domain.Ad) error) error {
+	filter.PageToken = ""
+	filter.SelectedFields = nil
+	cursor := adCursor{Sort: filter.SortBy, SnapshotAt: time.Now().UnixMicro()}
+
+	for exported := 0; exported < limit; {
+		filter.PageSize = min(exportBatchSize, limit-exported)
+		query := applyFilter(r.db.WithContext(ctx).Model(&domain.Ad{}), filter).
+			Where("created_at <= ?", time.UnixMicro(cursor.SnapshotAt))
+		query, err := pageQuery(query, filter, cursor)
+		if err != nil {
+			return err
+		}
+
+		var ads []domain.Ad
+		if err := query.Limit(filter.PageSize).Find(&ads).Error; err != nil {
+			return fmt.Errorf("error exporting ads: %v", err)
+		}
+		for i := range ads {
+			if err := fn(&ads[i]); err != nil {
+				return err
+			}
+		}
+
+		exported += len(ads)
+		if len(ads) < filter.PageSize {
+			return nil
+		}
+		cursor = cursor.after(&ads[len(ads)-1], filter.SortBy)
 	}
+	return nil
+}
 
-	if filter.PageToken != "" {
-		var lastAd domain.Ad
-		if err := r.db.First(&lastAd, "id = ?", filter.PageToken).Error; err != nil {
-			return nil, err
+// similarPriceRange is the relative price difference allowed for similar ads
+const similarPriceRange = 0.2
+
+// FindSimilar returns active ads sharing a category with the given ad, in a
+// similar price range when it has a price, ranked by text similarity
+func (r *AdRepository) FindSimilar(ctx context.Context, ad *domain.Ad, limit int) ([]domain.Ad, error) {
+	query := r.db.WithContext(ctx).Model(&domain.Ad{}).
+		Where("id <> ? AND status = ? AND category_ids && ?::integer[]", ad.ID, domain.StatusActive, pq.Array(ad.CategoryIDs))
+
+	if ad.Price != nil && ad.Price.Type != domain.PriceOnRequest {
+		query = query.Where(priceValueExpr+" BETWEEN ? AND ?",
+			ad.Price.Value*(1-similarPriceRange), ad.Price.Value*(1+similarPriceRange))
+		if ad.Price.Currency != "" {
+			query = query.Where("price->>'currency' = ?", ad.Price.Currency)
 		}
-		query = query.Where("id > ?", lastAd.ID)
 	}
 
-	// Execute query
-	if err := query.Limit(pageSize + 1).Find(&ads).Error; err != nil {
-		return nil, err
+	// Rank by how many of the target ad's lexemes each candidate matches, newest
+	// first among equals. Order only takes raw strings, so the bound rank goes in
+	// as a whole ORDER BY clause.
+	rank := clause.Expr{
+		SQL: `ts_rank(search_vector, (
+			SELECT to_tsquery('simple', COALESCE(string_agg(quote_literal(lexeme), ' | '), ''))
+			FROM ads target, unnest(tsvector_to_array(target.search_vector)) lexeme
+			WHERE target.id = ?
+		)) DESC, created_at DESC, id DESC`,
+		Vars: []interface{}{ad.ID},
 	}
 
-	// Prepare response
-	response := &domain.PaginatedResponse{
-		TotalCount: totalCount,
+	var ads []domain.Ad
+	err := query.Clauses(clause.OrderBy{Expression: rank}).Limit(limit).Find(&ads).Error
+	if err != nil {
+		return nil, fmt.Errorf("error finding similar ads: %v", err)
 	}
+	return ads, nil
+}
 
-	if len(ads) > pageSize {
-		response.Items = ads[:pageSize]
-		response.NextPage = fmt.Sprintf("%d", ads[pageSize-1].ID)
-	} else {
-		response.Items = ads
+// Exists reports whether any ad matches the filter
+func (r *AdRepository) Exists(ctx context.Context, filter domain.FilterRequest) (bool, error) {
+	subQuery := applyFilter(r.db.WithContext(ctx).Model(&domain.Ad{}).Select("1"), filter)
+
+	var exists bool
+	if err := r.db.WithContext(ctx).Raw("SELECT EXISTS (?)", subQuery).Scan(&exists).Error; err != nil {
+		return false, fmt.Errorf("error checking ads existence: %v", err)
 	}
+	return exists, nil
+}
 
-	return response, nil
+// Create saves a new ad and records its ad.created event
+func (r *AdRepository) Create(ctx context.Context, ad *domain.Ad) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return createAds(tx, []*domain.Ad{ad})
+	})
+}
+
+// insertBatchSize is the number of ads per multi-row INSERT, well within the
+// 65535 bind parameters PostgreSQL allows per statement
+const insertBatchSize = 500
+
+// CreateBatch saves new ads in one transaction, recording their ad.created
+// events, and sets their IDs. The ads are inserted with multi-row INSERTs; when
+// the database rejects one of them, the batch is saved again one ad at a time
+// so that the ads that fail are left out without undoing the others. The error
+// of each ad is returned at its index.
+func (r *AdRepository) CreateBatch(ctx context.Context, ads []*domain.Ad) ([]error, error) {
+	errs := make([]error, len(ads))
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if tx.Transaction(func(tx *gorm.DB) error { return createAds(tx, ads) }) == nil {
+			return nil
+		}
+		for i, ad := range ads {
+			errs[i] = tx.Transaction(func(tx *gorm.DB) error {
+				return createAds(tx, []*domain.Ad{ad})
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating ads: %v", err)
+	}
+	return errs, nil
 }
 
-func (r *AdRepository) buildSearchVector(ad *domain.Ad) string {
-	// Build search vector from all language versions
-	var searchTexts []string
+// createAds saves new ads and records their ad.created events within tx. The
+// ads get their IDs only once every one of them is saved.
+func createAds(tx *gorm.DB, ads []*domain.Ad) error {
+	// Create ads with all fields; search_vector is computed by the ads_search_vector_update trigger
+	records := make([]*domain.Ad, len(ads))
+	for i, ad := range ads {
+		records[i] = &domain.Ad{
+			Title:       ad.Title,
+			Description: ad.Description,
+			Properties:  ad.Properties,
+			CategoryIDs: ad.CategoryIDs,
+			Media:       ad.Media,
+			Status:      ad.Status,
+			Price:       ad.Price,
+			SellerID:    ad.SellerID,
+			OwnerID:     ad.OwnerID,
+			DuplicateOf: ad.DuplicateOf,
+			RawSource:   ad.RawSource,
+			ExpiresAt:   ad.ExpiresAt,
 
-	// Add title texts
-	for _, t := range ad.Title {
-		searchTexts = append(searchTexts, t.Text)
+			StatusReason:   ad.StatusReason,
+			PhoneEncrypted: ad.PhoneEncrypted,
+			PhoneMasked:    ad.PhoneMasked,
+		}
+	}
+	// slug is derived from the ID, so it is set once the ads have one
+	if err := tx.Omit("search_vector", "slug").CreateInBatches(records, insertBatchSize).Error; err != nil {
+		return dbError("creating ad", err)
+	}
+	if err := setSlugs(tx, records); err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err := recordAdEvent(tx, domain.AdEventCreated, record.ID); err != nil {
+			return err
+		}
 	}
 
-	// Add description texts if present
-	for _, d := range ad.Description {
-		searchTexts = append(searchTexts, d.Text)
+	for i, ad := range ads {
+		ad.ID = records[i].ID
+		ad.Slug = records[i].Slug
+		ad.Version = records[i].Version
+		ad.CreatedAt = records[i].CreatedAt
+		ad.UpdatedAt = records[i].UpdatedAt
 	}
+	return nil
+}
 
-	// Join all texts with spaces and convert to tsvector
-	return fmt.Sprintf("to_tsvector('simple', %s)",
-		r.db.Dialector.Explain("?", strings.Join(searchTexts, " ")))
+// setSlugs gives saved ads their slugs, with one UPDATE per insert batch
+func setSlugs(tx *gorm.DB, ads []*domain.Ad) error {
+	for start := 0; start < len(ads); start += insertBatchSize {
+		batch := ads[start:min(start+insertBatchSize, len(ads))]
+		values := make([]string, len(batch))
+		args := make([]interface{}, 0, 2*len(batch))
+		for i, ad := range batch {
+			ad.Slug = slug.ForAd(ad)
+			values[i] = "(?::bigint, ?::text)"
+			args = append(args, ad.ID, ad.Slug)
+		}
+		query := "UPDATE ads SET slug = v.slug FROM (VALUES " + strings.Join(values, ", ") + ") AS v(id, slug) WHERE ads.id = v.id"
+		if err := tx.Exec(query, args...).Error; err != nil {
+			return dbError("setting ad slugs", err)
+		}
+	}
+	return nil
 }
 
-func (r *AdRepository) Create(ctx context.Context, ad *domain.Ad) error {
-	// Set search vector
-	searchVector := r.buildSearchVector(ad)
-
-	// Create ad with all fields
-	result := r.db.WithContext(ctx).Model(&domain.Ad{}).Create(&domain.Ad{
-		Title:        ad.Title,
-		Description:  ad.Description,
-		Properties:   ad.Properties,
-		CategoryIDs:  ad.CategoryIDs,
-		Status:       ad.Status,
-		Price:        ad.Price,
-		SearchVector: searchVector,
+// Update saves the ad if it is still at ad.Version, returning domain.ErrConflict
+// when another update came first, and advances ad.Version. It records an
+// ad.updated event, preceded by ad.status_changed when the status changed.
+func (r *AdRepository) Update(ctx context.Context, ad *domain.Ad) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the ad so that the previous status is the one this update replaces
+		var previous domain.Ad
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("status").
+			Where("id = ? AND version = ?", ad.ID, ad.Version).Take(&previous).Error
+		if err == gorm.ErrRecordNotFound {
+			return domain.ErrConflict
+		}
+		if err != nil {
+			return fmt.Errorf("error locking ad: %v", err)
+		}
+
+		// search_vector is recomputed by the ads_search_vector_update trigger;
+		// raw_source keeps the payload the ad was originally ingested from,
+		// and seller_id and owner_id the seller and user who posted it
+		result := tx.Model(&domain.Ad{}).
+			Where("id = ? AND version = ?", ad.ID, ad.Version).
+			Omit("created_at").
+			Updates(map[string]interface{}{
+				"title":           ad.Title,
+				"description":     ad.Description,
+				"properties":      ad.Properties,
+				"category_ids":    ad.CategoryIDs,
+				"media":           ad.Media,
+				"status":          ad.Status,
+				"status_reason":   ad.StatusReason,
+				"price":           ad.Price,
+				"phone_encrypted": ad.PhoneEncrypted,
+				"phone_masked":    ad.PhoneMasked,
+				"expires_at":      ad.ExpiresAt,
+				"version":         gorm.Expr("version + 1"),
+			})
+
+		if result.Error != nil {
+			return dbError("updating ad", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrConflict
+		}
+
+		if previous.Status != ad.Status {
+			if err := recordAdEvent(tx, domain.AdEventStatusChanged, ad.ID); err != nil {
+				return err
+			}
+		}
+		return recordAdEvent(tx, domain.AdEventUpdated, ad.ID)
 	})
+	if err != nil {
+		return err
+	}
 
-	if result.Error != nil {
-		return fmt.Errorf("error creating ad: %v", result.Error)
+	ad.Version++
+	return nil
+}
+
+// AddViewCounts adds the view count deltas, by ad ID, in a single statement
+func (r *AdRepository) AddViewCounts(ctx context.Context, deltas map[uint]int64) error {
+	if len(deltas) == 0 {
+		return nil
 	}
 
+	ids := make([]int64, 0, len(deltas))
+	counts := make([]int64, 0, len(deltas))
+	for id, delta := range deltas {
+		ids = append(ids, int64(id))
+		counts = append(counts, delta)
+	}
+
+	// The arrays are bound as single values; GORM would expand bare slices into lists
+	err := r.db.WithContext(ctx).Exec(`
+		UPDATE ads SET view_count = ads.view_count + views.delta
+		FROM unnest(?::integer[], ?::bigint[]) AS views(id, delta)
+		WHERE ads.id = views.id`,
+		pq.Array(ids), pq.Array(counts)).Error
+	if err != nil {
+		return fmt.Errorf("error adding view counts: %v", err)
+	}
 	return nil
 }
 
-func (r *AdRepository) Update(ctx context.Context, ad *domain.Ad) error {
-	// Set search vector
-	searchVector := r.buildSearchVector(ad)
-
-	result := r.db.WithContext(ctx).Model(&domain.Ad{}).
-		Where("id = ?", ad.ID).
-		Omit("created_at").
-		Updates(map[string]interface{}{
-			"title":         ad.Title,
-			"description":   ad.Description,
-			"properties":    ad.Properties,
-			"category_ids":  ad.CategoryIDs,
-			"status":        ad.Status,
-			"price":         ad.Price,
-			"search_vector": searchVector,
+// SetStatus changes the status of the ad, e.g. when it is rejected automatically,
+// advancing its version so that edits based on the previous status conflict.
+// It records an ad.status_changed event.
+func (r *AdRepository) SetStatus(ctx context.Context, id uint, status domain.AdStatus, reason string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Ad{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"status":        status,
+			"status_reason": reason,
+			"version":       gorm.Expr("version + 1"),
 		})
+		if result.Error != nil {
+			return fmt.Errorf("error setting ad status: %v", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		return recordAdEvent(tx, domain.AdEventStatusChanged, id)
+	})
+}
 
-	if result.Error != nil {
-		return fmt.Errorf("error updating ad: %v", result.Error)
+// renewedExpiresAt is the expiry date of an ad starting a new lifetime of the
+// bound number of seconds, unless it has a future expiry date already
+const renewedExpiresAt = "CASE WHEN expires_at > NOW() THEN expires_at ELSE NOW() + ? * INTERVAL '1 second' END"
+
+// ActivateApproved makes the ads approved for longer than delay active and
+// returns them. Their lifetime starts on activation, so ads without a later
+// expiry date expire after lifetime. Like SetStatus, it advances their
+// versions and records an ad.status_changed event per ad.
+func (r *AdRepository) ActivateApproved(ctx context.Context, delay, lifetime time.Duration) ([]domain.Ad, error) {
+	var activated []domain.Ad
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The delay is measured on the database clock, which updated_at comes from
+		err := tx.Model(&activated).Clauses(clause.Returning{}).
+			Where("status = ? AND updated_at < NOW() - ? * INTERVAL '1 second'", domain.StatusApproved, delay.Seconds()).
+			Updates(map[string]interface{}{
+				"status":        domain.StatusActive,
+				"status_reason": "",
+				"expires_at":    gorm.Expr(renewedExpiresAt, lifetime.Seconds()),
+				"version":       gorm.Expr("version + 1"),
+			}).Error
+		if err != nil {
+			return fmt.Errorf("error activating approved ads: %v", err)
+		}
+
+		for _, ad := range activated {
+			if err := recordAdEvent(tx, domain.AdEventStatusChanged, ad.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return activated, nil
+}
 
-	return nil
+// ExpireAds completes up to limit active ads past their expiry date and
+// returns them. Ads locked by a concurrent expiration are skipped, so
+// instances running it at the same time complete different ads. Like
+// SetStatus, it advances their versions and records an ad.status_changed
+// event per ad.
+func (r *AdRepository) ExpireAds(ctx context.Context, limit int) ([]domain.Ad, error) {
+	var expired []domain.Ad
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		due := tx.Model(&domain.Ad{}).Select("id").
+			Where("status = ? AND expires_at IS NOT NULL AND expires_at < NOW()", domain.StatusActive).
+			Order("expires_at").
+			Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		err := tx.Model(&expired).Clauses(clause.Returning{}).
+			Where("id IN (?)", due).
+			Updates(map[string]interface{}{
+				"status":        domain.StatusCompleted,
+				"status_reason": domain.CompletedByExpiry,
+				"version":       gorm.Expr("version + 1"),
+			}).Error
+		if err != nil {
+			return fmt.Errorf("error expiring ads: %v", err)
+		}
+
+		for _, ad := range expired {
+			if err := recordAdEvent(tx, domain.AdEventStatusChanged, ad.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return expired, nil
+}
+
+// RenewAd starts a new lifetime for an active ad, or for an ad completed when
+// it expired, which becomes active again. It returns domain.ErrNotFound for a
+// missing ad and domain.ErrNotRenewable for an ad in another status. It
+// advances the ad's version and records an ad.updated event, preceded by
+// ad.status_changed when the ad is reactivated.
+func (r *AdRepository) RenewAd(ctx context.Context, id uint, lifetime time.Duration) (*domain.Ad, error) {
+	var renewed domain.Ad
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var previous domain.Ad
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "status", "status_reason").Take(&previous, id).Error
+		if err == gorm.ErrRecordNotFound {
+			return domain.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error locking ad: %v", err)
+		}
+		expired := previous.Status == domain.StatusCompleted && previous.StatusReason == domain.CompletedByExpiry
+		if previous.Status != domain.StatusActive && !expired {
+			return domain.ErrNotRenewable
+		}
+
+		err = tx.Model(&renewed).Clauses(clause.Returning{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"status":        domain.StatusActive,
+			"status_reason": "",
+			"expires_at":    gorm.Expr("NOW() + ? * INTERVAL '1 second'", lifetime.Seconds()),
+			"version":       gorm.Expr("version + 1"),
+		}).Error
+		if err != nil {
+			return fmt.Errorf("error renewing ad: %v", err)
+		}
+
+		if expired {
+			if err := recordAdEvent(tx, domain.AdEventStatusChanged, id); err != nil {
+				return err
+			}
+		}
+		return recordAdEvent(tx, domain.AdEventUpdated, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &renewed, nil
+}
+
+// duplicatePriceRange is how far apart, as a fraction, two prices may be for
+// the ads to be duplicates
+const duplicatePriceRange = 0.01
+
+// FindDuplicate returns the ID of the ad created within window before now
+// that the given ad most likely duplicates: an ad sharing a category, at
+// nearly the same price, whose search vector matches the ad's title in one of
+// its languages and whose title in one of them is at least threshold similar
+// to the ad's by trigram similarity. The search vector match narrows the
+// candidates through its GIN index before similarity is computed. It returns
+// nil when no ad is similar enough.
+func (r *AdRepository) FindDuplicate(ctx context.Context, ad *domain.Ad, window time.Duration, threshold float64) (*uint, error) {
+	if len(ad.CategoryIDs) == 0 || len(ad.Title) == 0 {
+		return nil, nil
+	}
+
+	query := r.db.WithContext(ctx).Model(&domain.Ad{}).Select("id").
+		Where("category_ids && ?::integer[] AND created_at > NOW() - ? * INTERVAL '1 second'", pq.Array(ad.CategoryIDs), window.Seconds()).
+		Where("status NOT IN ?", []domain.AdStatus{domain.StatusDuplicate, domain.StatusRejected})
+
+	if ad.Price != nil && ad.Price.Type != domain.PriceOnRequest {
+		query = query.Where(priceValueExpr+" BETWEEN ? AND ?",
+			ad.Price.Value*(1-duplicatePriceRange), ad.Price.Value*(1+duplicatePriceRange))
+		if ad.Price.Currency != "" {
+			query = query.Where("price->>'currency' = ?", ad.Price.Currency)
+		}
+	} else {
+		query = query.Where(priceValueExpr + " IS NULL")
+	}
+
+	// The best similarity over the ad's languages, against the lowercased
+	// titles ads_title_text returns
+	similarities := make([]string, len(ad.Title))
+	matches := make([]string, len(ad.Title))
+	vars := make([]interface{}, len(ad.Title))
+	matchVars := make([]interface{}, 0, 2*len(ad.Title))
+	for i, title := range ad.Title {
+		similarities[i] = fmt.Sprintf("COALESCE(similarity(ads_title_text(title, %d), lower(?)), 0)", int(title.Lang))
+		vars[i] = title.Text
+		matches[i] = "search_vector @@ plainto_tsquery(ads_lang_regconfig(?), ?)"
+		matchVars = append(matchVars, int(title.Lang), title.Text)
+	}
+	similarity := "GREATEST(" + strings.Join(similarities, ", ") + ")"
+	query = query.Where("("+strings.Join(matches, " OR ")+")", matchVars...)
+
+	var ids []uint
+	err := query.Where(similarity+" >= ?", append(vars, threshold)...).
+		Clauses(clause.OrderBy{Expression: clause.Expr{SQL: similarity + " DESC, created_at ASC", Vars: vars}}).
+		Limit(1).Pluck("id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("error finding duplicate ad: %v", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return &ids[0], nil
+}
+
+// FindDuplicates returns the ads found to duplicate the ad with the given ID,
+// newest first
+func (r *AdRepository) FindDuplicates(ctx context.Context, id uint) ([]domain.Ad, error) {
+	var ads []domain.Ad
+	if err := r.db.WithContext(ctx).Where("duplicate_of = ?", id).Order("created_at DESC").Find(&ads).Error; err != nil {
+		return nil, fmt.Errorf("error getting duplicate ads: %v", err)
+	}
+	return ads, nil
+}
+
+// ClearDuplicate returns an ad marked as a duplicate to pending moderation and
+// forgets the ad it duplicated. It returns domain.ErrNotFound for a missing ad
+// and domain.ErrNotDuplicate for an ad in another status. It advances the ad's
+// version and records ad.status_changed and ad.updated events.
+func (r *AdRepository) ClearDuplicate(ctx context.Context, id uint) (*domain.Ad, error) {
+	var cleared domain.Ad
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var previous domain.Ad
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "status").Take(&previous, id).Error
+		if err == gorm.ErrRecordNotFound {
+			return domain.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error locking ad: %v", err)
+		}
+		if previous.Status != domain.StatusDuplicate {
+			return domain.ErrNotDuplicate
+		}
+
+		err = tx.Model(&cleared).Clauses(clause.Returning{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"status":       domain.StatusPending,
+			"duplicate_of": nil,
+			"version":      gorm.Expr("version + 1"),
+		}).Error
+		if err != nil {
+			return fmt.Errorf("error clearing duplicate ad: %v", err)
+		}
+
+		if err := recordAdEvent(tx, domain.AdEventStatusChanged, id); err != nil {
+			return err
+		}
+		return recordAdEvent(tx, domain.AdEventUpdated, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &cleared, nil
+}
+
+// RebuildSearchVectors recomputes, with ads_build_search_vector, the search
+// vectors of up to limit ads with IDs above afterID, in ID order, and returns
+// the IDs of those ads. The ads are updated by a single statement.
+func (r *AdRepository) RebuildSearchVectors(ctx context.Context, afterID uint, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&domain.Ad{}).
+		Where("id > ?", afterID).Order("id").Limit(limit).Pluck("id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("error getting ads to rebuild search vectors of: %v", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	err = r.db.WithContext(ctx).Exec(`UPDATE ads SET search_vector = ads_build_search_vector(ads.title, ads.description)
+		FROM unnest(?::integer[]) AS batch(id) WHERE ads.id = batch.id`, pq.Array(ids)).Error
+	if err != nil {
+		return nil, fmt.Errorf("error rebuilding search vectors: %v", err)
+	}
+	return ids, nil
+}
+
+// UpdateImages replaces the images of the ad with the ones change returns for
+// its current images, returning domain.ErrNotFound for a missing ad. The ad
+// is locked meanwhile, so concurrent changes apply one after the other. It
+// advances the ad's version and records an ad.updated event.
+func (r *AdRepository) UpdateImages(ctx context.Context, id uint, change func(domain.Images) (domain.Images, error)) (domain.Images, error) {
+	var images domain.Images
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ad domain.Ad
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "images").Take(&ad, id).Error
+		if err == gorm.ErrRecordNotFound {
+			return domain.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error locking ad: %v", err)
+		}
+
+		if images, err = change(ad.Images); err != nil {
+			return err
+		}
+		err = tx.Model(&domain.Ad{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"images":  images,
+			"version": gorm.Expr("version + 1"),
+		}).Error
+		if err != nil {
+			return fmt.Errorf("error updating ad images: %v", err)
+		}
+		return recordAdEvent(tx, domain.AdEventUpdated, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return images, nil
 }
 
 func (r *AdRepository) Delete(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).Delete(&domain.Ad{}, id).Error
 }
 
+// GetByID returns the ad with the given ID, or domain.ErrNotFound
 func (r *AdRepository) GetByID(ctx context.Context, id uint) (*domain.Ad, error) {
 	var ad domain.Ad
 	if err := r.db.WithContext(ctx).First(&ad, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, nil
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
 		}
 		return nil, fmt.Errorf("error getting ad: %v", err)
 	}
 	return &ad, nil
 }
 
-func (r *AdRepository) List(ctx context.Context, filter *domain.FilterRequest) (*domain.PaginatedResponse, error) {
-	query := r.db.WithContext(ctx).Model(&domain.Ad{})
-
-	// Apply filters
-	if len(filter.CategoryIDs) > 0 {
-		query = query.Where("category_ids && ?", filter.CategoryIDs)
+// FindByIDs returns the ads with the given IDs, in no particular order; IDs
+// without an ad are skipped
+func (r *AdRepository) FindByIDs(ctx context.Context, ids []uint) ([]domain.Ad, error) {
+	if len(ids) == 0 {
+		return nil, nil
 	}
+	var ads []domain.Ad
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&ads).Error; err != nil {
+		return nil, fmt.Errorf("error getting ads: %v", err)
+	}
+	return ads, nil
+}
 
-	if filter.TextSearch != "" {
-		query = query.Where("search_vector @@ plainto_tsquery(?)", filter.TextSearch)
+// CountActiveByCategory returns the number of active ads per category
+func (r *AdRepository) CountActiveByCategory(ctx context.Context) (map[int]int64, error) {
+	var rows []struct {
+		CategoryID int
+		Count      int64
 	}
 
-	if filter.Status != nil {
-		query = query.Where("status = ?", *filter.Status)
+	err := r.db.WithContext(ctx).Model(&domain.Ad{}).
+		Select("unnest(category_ids) AS category_id, COUNT(*) AS count").
+		Where("status = ?", domain.StatusActive).
+		Group("category_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("error counting ads by category: %v", err)
 	}
 
-	// Apply property filters
-	for _, prop := range filter.PropertyFilters {
-		// Filter by primitive values
-		if len(prop.Values) > 0 {
-			query = query.Where("EXISTS (SELECT 1 FROM jsonb_array_elements(properties) props WHERE props->>'ID' = ? AND props->>'value' = ANY(?))",
-				prop.PropertyID, prop.Values)
-		}
-		// Filter by reference values
-		if len(prop.ValueIDs) > 0 {
-			query = query.Where("EXISTS (SELECT 1 FROM jsonb_array_elements(properties) props WHERE props->>'ID' = ? AND (props->>'value_id')::int = ANY(?))",
-				prop.PropertyID, prop.ValueIDs)
-		}
+	counts := make(map[int]int64, len(rows))
+	for _, row := range rows {
+		counts[row.CategoryID] = row.Count
 	}
+	return counts, nil
+}
 
-	// Apply price filters
-	if filter.MinPrice != nil || filter.MaxPrice != nil || filter.Currency != "" {
-		if filter.Currency != "" {
-			query = query.Where("price->>'currency' = ?", filter.Currency)
-		}
-		if filter.MinPrice != nil {
-			query = query.Where("(price->>'value')::float >= ?", *filter.MinPrice)
-		}
-		if filter.MaxPrice != nil {
-			query = query.Where("(price->>'value')::float <= ?", *filter.MaxPrice)
-		}
+// likeEscaper escapes the LIKE wildcards of user input
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// Suggest returns up to limit distinct active ad titles in the given language that
+// start with the prefix or have a word starting with it, most frequent first.
+// The prefix must be lowercase to match the lowercased titles.
+func (r *AdRepository) Suggest(ctx context.Context, prefix string, lang domain.Language, limit int) ([]domain.Suggestion, error) {
+	// The language is inlined rather than bound so the query matches the per-language trigram indexes
+	title := fmt.Sprintf("ads_title_text(title, %d)", int(lang))
+	escaped := likeEscaper.Replace(prefix)
+
+	var suggestions []domain.Suggestion
+	err := r.db.WithContext(ctx).Model(&domain.Ad{}).
+		Select(title+" AS text, COUNT(*) AS count").
+		Where("status = ?", domain.StatusActive).
+		Where("("+title+" LIKE ? OR "+title+" LIKE ?)", escaped+"%", "% "+escaped+"%").
+		Group("text").
+		Order("count DESC").
+		Order("text ASC").
+		Limit(limit).
+		Scan(&suggestions).Error
+	if err != nil {
+		return nil, fmt.Errorf("error finding suggestions: %v", err)
 	}
+	return suggestions, nil
+}
 
-	// Apply sorting
-	switch filter.SortBy {
-	case "price_asc":
-		query = query.Order("(price->>'value')::float ASC NULLS LAST")
-	case "price_desc":
-		query = query.Order("(price->>'value')::float DESC NULLS LAST")
-	case "date_desc":
-		query = query.Order("created_at DESC")
-	default:
-		query = query.Order("created_at DESC")
+// CountByStatus returns the number of ads per status
+func (r *AdRepository) CountByStatus(ctx context.Context) (map[domain.AdStatus]int64, error) {
+	var rows []struct {
+		Status domain.AdStatus
+		Count  int64
 	}
 
-	// Get total count
-	var totalCount int64
-	if err := query.Count(&totalCount).Error; err != nil {
-		return nil, fmt.Errorf("error counting ads: %v", err)
+	err := r.db.WithContext(ctx).Model(&domain.Ad{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("error counting ads by status: %v", err)
 	}
 
-	// Apply pagination
-	if filter.PageSize > 0 {
-		query = query.Limit(filter.PageSize)
+	counts := make(map[domain.AdStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
 	}
-	if filter.PageToken != "" {
-		// Implement cursor-based pagination using PageToken
-		// This is a placeholder - implement according to your needs
+	return counts, nil
+}
+
+// CountCreatedByDay returns the number of ads created per UTC day since the given time,
+// keyed by the day as YYYY-MM-DD; days without ads are missing
+func (r *AdRepository) CountCreatedByDay(ctx context.Context, since time.Time) (map[string]int64, error) {
+	var rows []struct {
+		Day   string
+		Count int64
 	}
 
-	// Get results
-	var ads []domain.Ad
-	if err := query.Find(&ads).Error; err != nil {
-		return nil, fmt.Errorf("error listing ads: %v", err)
+	err := r.db.WithContext(ctx).Model(&domain.Ad{}).
+		Select("to_char(created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS day, COUNT(*) AS count").
+		Where("created_at >= ?", since).
+		Group("day").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("error counting ads by day: %v", err)
 	}
 
-	return &domain.PaginatedResponse{
-		Items:      ads,
-		TotalCount: totalCount,
-		// Set NextPage based on your pagination implementation
-	}, nil
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Day] = row.Count
+	}
+	return counts, nil
 }
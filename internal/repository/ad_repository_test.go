@@ -0,0 +1,626 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newMockDB returns a database whose statements are checked against the
+// expectations of the returned mock
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	return newMockDBMatching(t, sqlmock.QueryMatcherRegexp)
+}
+
+// newMockDBMatching is newMockDB matching statements to expectations with
+// matcher
+func newMockDBMatching(t *testing.T, matcher sqlmock.QueryMatcher) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(matcher))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		sqlDB.Close()
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, mock
+}
+
+// expectSlugUpdate expects the UPDATE giving a batch of inserted ads their
+// slugs
+func expectSlugUpdate(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(`UPDATE ads SET slug = v.slug FROM \(VALUES`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func newTestAd() *domain.Ad {
+	return &domain.Ad{
+		Title: domain.MultiLangArray{
+			{Lang: domain.LangRussian, Text: "Красный велосипед"},
+			{Lang: domain.LangEnglish, Text: "Red Bicycle"},
+		},
+		CategoryIDs: []int{1},
+	}
+}
+
+func TestCreateSetsIDAndTimestamps(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "ads"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(42, 1))
+	expectSlugUpdate(mock)
+	expectAdEvent(mock, domain.AdEventCreated, 42, nil)
+	mock.ExpectCommit()
+
+	ad := newTestAd()
+	if err := NewAdRepository(db).Create(context.Background(), ad); err != nil {
+		t.Fatal(err)
+	}
+	if ad.ID != 42 {
+		t.Errorf("ID = %d, want 42", ad.ID)
+	}
+	if ad.CreatedAt.IsZero() || ad.UpdatedAt.IsZero() {
+		t.Errorf("timestamps not set: created_at %v, updated_at %v", ad.CreatedAt, ad.UpdatedAt)
+	}
+}
+
+func TestFilterLeavesOutExpiredAds(t *testing.T) {
+	db, _ := newMockDB(t)
+	tests := []struct {
+		name   string
+		filter domain.FilterRequest
+		want   bool
+	}{
+		{"default", domain.FilterRequest{}, true},
+		{"include expired", domain.FilterRequest{IncludeExpired: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ads []domain.Ad
+			query := applyFilter(db.Session(&gorm.Session{DryRun: true}).Model(&domain.Ad{}), tt.filter)
+			sql := query.Find(&ads).Statement.SQL.String()
+			if got := strings.Contains(sql, "(expires_at IS NULL OR expires_at > NOW())"); got != tt.want {
+				t.Errorf("expired ads left out = %v, want %v: %s", got, tt.want, sql)
+			}
+		})
+	}
+}
+
+func TestMediaRoundTrip(t *testing.T) {
+	media := domain.Media{
+		{URL: "https://cdn.example.com/ads/1/cover.jpg", Type: domain.MediaImage, Width: 1200, Height: 800},
+		{URL: "https://cdn.example.com/ads/1/tour.mp4", Type: domain.MediaVideo},
+	}
+	stored, err := media.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "ads" \(.*"media".*\) VALUES`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(42, 1))
+	expectSlugUpdate(mock)
+	expectAdEvent(mock, domain.AdEventCreated, 42, nil)
+	mock.ExpectCommit()
+	mock.ExpectQuery(`SELECT \* FROM "ads"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "media"}).AddRow(42, stored))
+
+	repo := NewAdRepository(db)
+	ad := newTestAd()
+	ad.Media = media
+	if err := repo.Create(context.Background(), ad); err != nil {
+		t.Fatal(err)
+	}
+	fetched, err := repo.GetByID(context.Background(), ad.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(fetched.Media, media) {
+		t.Errorf("fetched media %+v, want %+v", fetched.Media, media)
+	}
+}
+
+// expectAdEvent expects an ad to be read and recorded as an outbox event of
+// the given type, failing with err unless it is nil
+func expectAdEvent(mock sqlmock.Sqlmock, eventType string, id uint, err error) {
+	mock.ExpectQuery(`SELECT \* FROM "ads"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
+	insert := mock.ExpectExec(`INSERT INTO outbox_events`).WithArgs(eventType, id, sqlmock.AnyArg())
+	if err != nil {
+		insert.WillReturnError(err)
+	} else {
+		insert.WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+}
+
+func TestCreateRollsBackWhenEventFails(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "ads"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(42, 1))
+	expectSlugUpdate(mock)
+	expectAdEvent(mock, domain.AdEventCreated, 42, errors.New("outbox unavailable"))
+	mock.ExpectRollback()
+
+	ad := newTestAd()
+	if err := NewAdRepository(db).Create(context.Background(), ad); err == nil {
+		t.Fatal("Create succeeded without its event")
+	}
+	if ad.ID != 0 {
+		t.Errorf("rolled back ad got ID %d", ad.ID)
+	}
+}
+
+func TestUpdateRecordsStatusChange(t *testing.T) {
+	tests := []struct {
+		name string
+		// eventErr fails the status_changed event
+		eventErr error
+	}{
+		{"committed", nil},
+		{"rolled back when the status event fails", errors.New("outbox unavailable")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			mock.ExpectBegin()
+			mock.ExpectQuery(`SELECT "status" FROM "ads" WHERE id = \$1 AND version = \$2 .*FOR UPDATE`).
+				WithArgs(42, 3).
+				WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(domain.StatusPending))
+			mock.ExpectExec(`UPDATE "ads" SET .*"version"=version \+ 1.* WHERE id = \$\d+ AND version = \$\d+`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			expectAdEvent(mock, domain.AdEventStatusChanged, 42, tt.eventErr)
+			if tt.eventErr == nil {
+				expectAdEvent(mock, domain.AdEventUpdated, 42, nil)
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			ad := newTestAd()
+			ad.ID, ad.Version, ad.Status = 42, 3, domain.StatusDraft
+			err := NewAdRepository(db).Update(context.Background(), ad)
+			if (err == nil) != (tt.eventErr == nil) {
+				t.Fatalf("error = %v, want failure %v", err, tt.eventErr != nil)
+			}
+			wantVersion := 4
+			if tt.eventErr != nil {
+				wantVersion = 3
+			}
+			if ad.Version != wantVersion {
+				t.Errorf("version = %d, want %d", ad.Version, wantVersion)
+			}
+		})
+	}
+}
+
+func TestUpdateConflict(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT "status" FROM "ads"`).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}))
+	mock.ExpectRollback()
+
+	ad := newTestAd()
+	ad.ID, ad.Version = 42, 3
+	if err := NewAdRepository(db).Update(context.Background(), ad); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("error = %v, want %v", err, domain.ErrConflict)
+	}
+}
+
+// adRows returns rows of the ads with IDs from, from-1, ... down to to,
+// newest first
+func adRows(from, to int, createdAt time.Time) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"id", "created_at"})
+	for id := from; id >= to; id-- {
+		rows.AddRow(id, createdAt.Add(time.Duration(id)*time.Second))
+	}
+	return rows
+}
+
+func TestExportReadsInBatches(t *testing.T) {
+	createdAt := time.Now().Add(-time.Hour)
+	db, mock := newMockDB(t)
+	mock.ExpectQuery(`SELECT \* FROM "ads" WHERE .*created_at <= \$\d+ ORDER BY created_at DESC,id DESC LIMIT 1000$`).
+		WillReturnRows(adRows(2300, 1301, createdAt))
+	mock.ExpectQuery(`SELECT \* FROM "ads" WHERE .*\(created_at, id\) < \(\$\d+, \$\d+\) ORDER BY .* LIMIT 1000$`).
+		WithArgs(sqlmock.AnyArg(), createdAt.Add(1301*time.Second).Truncate(time.Microsecond), 1301).
+		WillReturnRows(adRows(1300, 301, createdAt))
+	mock.ExpectQuery(`SELECT \* FROM "ads" WHERE .*\(created_at, id\) < \(\$\d+, \$\d+\) ORDER BY .* LIMIT 1000$`).
+		WithArgs(sqlmock.AnyArg(), createdAt.Add(301*time.Second).Truncate(time.Microsecond), 301).
+		WillReturnRows(adRows(300, 1, createdAt))
+
+	var exported []uint
+	err := NewAdRepository(db).Export(context.Background(), domain.FilterRequest{IncludeExpired: true, PageSize: 5}, 5000, func(ad *domain.Ad) error {
+		exported = append(exported, ad.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exported) != 2300 {
+		t.Fatalf("exported %d ads, want 2300", len(exported))
+	}
+	for i, id := range exported {
+		if want := uint(2300 - i); id != want {
+			t.Fatalf("ad %d exported as %d, want %d", i, id, want)
+		}
+	}
+}
+
+func TestExportStopsAtLimit(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectQuery(`SELECT \* FROM "ads" .* LIMIT 1000$`).
+		WillReturnRows(adRows(2000, 1001, time.Now().Add(-time.Hour)))
+	mock.ExpectQuery(`SELECT \* FROM "ads" .* LIMIT 200$`).
+		WillReturnRows(adRows(1000, 801, time.Now().Add(-time.Hour)))
+
+	exported := 0
+	err := NewAdRepository(db).Export(context.Background(), domain.FilterRequest{IncludeExpired: true}, 1200, func(ad *domain.Ad) error {
+		exported++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exported != 1200 {
+		t.Errorf("exported %d ads, want the limit of 1200", exported)
+	}
+}
+
+func TestExpireAdsSkipsLockedAds(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE "ads" SET .* WHERE id IN \(SELECT "id" FROM "ads" WHERE status = \$\d+ AND expires_at IS NOT NULL AND expires_at < NOW\(\) ORDER BY expires_at LIMIT 500 FOR UPDATE SKIP LOCKED\) RETURNING \*`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).
+			AddRow(7, domain.StatusCompleted).
+			AddRow(9, domain.StatusCompleted))
+	expectAdEvent(mock, domain.AdEventStatusChanged, 7, nil)
+	expectAdEvent(mock, domain.AdEventStatusChanged, 9, nil)
+	mock.ExpectCommit()
+
+	expired, err := NewAdRepository(db).ExpireAds(context.Background(), 500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(expired) != 2 || expired[0].ID != 7 || expired[1].ID != 9 {
+		t.Errorf("expired = %+v, want ads 7 and 9", expired)
+	}
+}
+
+func TestRenewAd(t *testing.T) {
+	tests := []struct {
+		name   string
+		status domain.AdStatus
+		reason string
+		// events are the events the renewal records, none when it fails
+		events  []string
+		wantErr error
+	}{
+		{"active", domain.StatusActive, "", []string{domain.AdEventUpdated}, nil},
+		{"expired", domain.StatusCompleted, domain.CompletedByExpiry, []string{domain.AdEventStatusChanged, domain.AdEventUpdated}, nil},
+		{"completed by the owner", domain.StatusCompleted, "", nil, domain.ErrNotRenewable},
+		{"draft", domain.StatusDraft, "", nil, domain.ErrNotRenewable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			mock.ExpectBegin()
+			mock.ExpectQuery(`SELECT "id","status","status_reason" FROM "ads" WHERE .* FOR UPDATE`).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "status", "status_reason"}).AddRow(42, tt.status, tt.reason))
+			if tt.wantErr == nil {
+				mock.ExpectQuery(`UPDATE "ads" SET "expires_at"=NOW\(\) \+ \$1 \* INTERVAL '1 second',"status"=\$2`).
+					WithArgs(float64(3600), domain.StatusActive, "", sqlmock.AnyArg(), 42).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(42, domain.StatusActive))
+				for _, event := range tt.events {
+					expectAdEvent(mock, event, 42, nil)
+				}
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			ad, err := NewAdRepository(db).RenewAd(context.Background(), 42, time.Hour)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && ad.Status != domain.StatusActive {
+				t.Errorf("renewed ad status = %v, want active", ad.Status)
+			}
+		})
+	}
+}
+
+func TestRenewMissingAd(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT "id","status","status_reason" FROM "ads"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "status_reason"}))
+	mock.ExpectRollback()
+
+	if _, err := NewAdRepository(db).RenewAd(context.Background(), 42, time.Hour); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("error = %v, want %v", err, domain.ErrNotFound)
+	}
+}
+
+func TestGetMissingAd(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectQuery(`SELECT \* FROM "ads" WHERE "ads"."id" = \$1`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	ad, err := NewAdRepository(db).GetByID(context.Background(), 42)
+	if !errors.Is(err, domain.ErrNotFound) || ad != nil {
+		t.Errorf("GetByID = %v, %v, want nil, %v", ad, err, domain.ErrNotFound)
+	}
+}
+
+func TestCreateDuplicate(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "ads"`).
+		WillReturnError(&pgconn.PgError{Code: "23505", Message: "duplicate key value violates unique constraint"})
+	mock.ExpectRollback()
+
+	if err := NewAdRepository(db).Create(context.Background(), newTestAd()); !errors.Is(err, domain.ErrDuplicate) {
+		t.Errorf("error = %v, want %v", err, domain.ErrDuplicate)
+	}
+}
+
+// insertedRows returns the rows a multi-row INSERT of the ads with IDs from
+// first up to last returns
+func insertedRows(first, last int) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"id", "version"})
+	for id := first; id <= last; id++ {
+		rows.AddRow(id, 1)
+	}
+	return rows
+}
+
+// countInsertedRows matches statements as regular expressions, recording the
+// number of rows of every INSERT INTO "ads"
+func countInsertedRows(rows *[]int) sqlmock.QueryMatcher {
+	return sqlmock.QueryMatcherFunc(func(expectedSQL, actualSQL string) error {
+		if err := sqlmock.QueryMatcherRegexp.Match(expectedSQL, actualSQL); err != nil {
+			return err
+		}
+		if strings.HasPrefix(actualSQL, `INSERT INTO "ads"`) {
+			// Rows are separated by "),(" followed by their first bind parameter
+			*rows = append(*rows, strings.Count(actualSQL, "),($")+1)
+		}
+		return nil
+	})
+}
+
+func TestCreateBatchInsertsMultipleRows(t *testing.T) {
+	var statementRows []int
+	db, mock := newMockDBMatching(t, countInsertedRows(&statementRows))
+	mock.ExpectBegin()
+	// CreateBatch and CreateInBatches each make a savepoint
+	mock.ExpectExec(`SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`INSERT INTO "ads"`).WillReturnRows(insertedRows(1, 500))
+	mock.ExpectQuery(`INSERT INTO "ads"`).WillReturnRows(insertedRows(501, 1000))
+	expectSlugUpdate(mock)
+	expectSlugUpdate(mock)
+	for id := uint(1); id <= 1000; id++ {
+		expectAdEvent(mock, domain.AdEventCreated, id, nil)
+	}
+	mock.ExpectCommit()
+
+	ads := make([]*domain.Ad, 1000)
+	for i := range ads {
+		ads[i] = newTestAd()
+	}
+	errs, err := NewAdRepository(db).CreateBatch(context.Background(), ads)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{500, 500}; !slices.Equal(statementRows, want) {
+		t.Errorf("inserted %v rows per statement, want %v", statementRows, want)
+	}
+	for i, ad := range ads {
+		if errs[i] != nil || ad.ID != uint(i+1) {
+			t.Fatalf("ad %d created with ID %d and error %v", i, ad.ID, errs[i])
+		}
+	}
+}
+
+func TestCreateBatchSavesOneByOneAfterRejection(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`INSERT INTO "ads"`).
+		WillReturnError(&pgconn.PgError{Code: "23514", Message: "violates check constraint"})
+	mock.ExpectExec(`ROLLBACK TO SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectExec(`SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`INSERT INTO "ads"`).WillReturnRows(insertedRows(1, 1))
+	expectSlugUpdate(mock)
+	expectAdEvent(mock, domain.AdEventCreated, 1, nil)
+	mock.ExpectExec(`SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`INSERT INTO "ads"`).
+		WillReturnError(&pgconn.PgError{Code: "23514", Message: "violates check constraint"})
+	mock.ExpectExec(`ROLLBACK TO SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	ads := []*domain.Ad{newTestAd(), newTestAd()}
+	errs, err := NewAdRepository(db).CreateBatch(context.Background(), ads)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs[0] != nil || ads[0].ID != 1 {
+		t.Errorf("first ad created with ID %d and error %v", ads[0].ID, errs[0])
+	}
+	if !errors.Is(errs[1], domain.ErrConstraint) || ads[1].ID != 0 {
+		t.Errorf("second ad created with ID %d and error %v, want %v", ads[1].ID, errs[1], domain.ErrConstraint)
+	}
+}
+
+func TestFindDuplicate(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectQuery(`SELECT "id" FROM "ads" WHERE \(category_ids && \$1::integer\[\] AND created_at > NOW\(\) - \$2 \* INTERVAL '1 second'\) `+
+		`AND status NOT IN \(\$3,\$4\) AND .* BETWEEN \$5 AND \$6\) AND price->>'currency' = \$7 `+
+		`AND \(\(search_vector @@ plainto_tsquery\(ads_lang_regconfig\(\$8\), \$9\) OR search_vector @@ plainto_tsquery\(ads_lang_regconfig\(\$10\), \$11\)\)\) `+
+		`AND GREATEST\(COALESCE\(similarity\(ads_title_text\(title, 1\), lower\(\$12\)\), 0\), COALESCE\(similarity\(ads_title_text\(title, 2\), lower\(\$13\)\), 0\)\) >= \$14 `+
+		`ORDER BY GREATEST\(.*\) DESC, created_at ASC LIMIT 1`).
+		WithArgs(sqlmock.AnyArg(), float64(3600), domain.StatusDuplicate, domain.StatusRejected, 99.0, 101.0, "978",
+			int(domain.LangRussian), "Красный велосипед", int(domain.LangEnglish), "Red Bicycle",
+			"Красный велосипед", "Red Bicycle", 0.8, "Красный велосипед", "Red Bicycle").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+	ad := newTestAd()
+	ad.Price = &domain.Price{Value: 100, Currency: "978", Type: domain.PriceFixed}
+	id, err := NewAdRepository(db).FindDuplicate(context.Background(), ad, time.Hour, 0.8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id == nil || *id != 7 {
+		t.Errorf("duplicate of %v, want 7", id)
+	}
+}
+
+func TestFindDuplicatePrefiltersBySearchVector(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectQuery(`search_vector @@ plainto_tsquery\(ads_lang_regconfig\(\$\d+\), \$\d+\) OR search_vector @@ plainto_tsquery\(ads_lang_regconfig\(\$\d+\), \$\d+\)\).*similarity`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+	id, err := NewAdRepository(db).FindDuplicate(context.Background(), newTestAd(), 24*time.Hour, 0.6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id == nil || *id != 7 {
+		t.Errorf("found duplicate %v, want 7", id)
+	}
+}
+
+func TestFindNoDuplicate(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectQuery(`SELECT "id" FROM "ads" WHERE .* IS NULL AND \(\(search_vector @@ .*\)\) AND GREATEST`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	id, err := NewAdRepository(db).FindDuplicate(context.Background(), newTestAd(), time.Hour, 0.8)
+	if err != nil || id != nil {
+		t.Errorf("FindDuplicate = %v, %v, want no duplicate", id, err)
+	}
+}
+
+func TestClearDuplicateOfAnotherStatus(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT "id","status" FROM "ads" .* FOR UPDATE`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(42, domain.StatusPending))
+	mock.ExpectRollback()
+
+	if _, err := NewAdRepository(db).ClearDuplicate(context.Background(), 42); !errors.Is(err, domain.ErrNotDuplicate) {
+		t.Errorf("error = %v, want %v", err, domain.ErrNotDuplicate)
+	}
+}
+
+// expectAdInsert expects the INSERT of one ad, which gets the given ID
+func expectAdInsert(mock sqlmock.Sqlmock, id uint) {
+	mock.ExpectQuery(`INSERT INTO "ads"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(id, 1))
+}
+
+func TestCreateSetsSlugInTransaction(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	expectAdInsert(mock, 42)
+	mock.ExpectExec(`UPDATE ads SET slug = v.slug FROM \(VALUES`).
+		WithArgs(42, "red-bicycle-42").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT \* FROM "ads"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "slug"}).AddRow(42, "red-bicycle-42"))
+	mock.ExpectExec(`INSERT INTO outbox_events`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	ad := newTestAd()
+	if err := NewAdRepository(db).Create(context.Background(), ad); err != nil {
+		t.Fatal(err)
+	}
+	if ad.ID != 42 || ad.Slug != "red-bicycle-42" {
+		t.Errorf("created ad %d with slug %q, want 42 with red-bicycle-42", ad.ID, ad.Slug)
+	}
+}
+
+func TestCreateRollsBackWithoutSlug(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	expectAdInsert(mock, 42)
+	mock.ExpectExec(`UPDATE ads SET slug`).WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	ad := newTestAd()
+	if err := NewAdRepository(db).Create(context.Background(), ad); err == nil {
+		t.Fatal("Create succeeded without its slug")
+	}
+	if ad.ID != 0 || ad.Slug != "" {
+		t.Errorf("rolled back ad got ID %d and slug %q", ad.ID, ad.Slug)
+	}
+}
+
+// BenchmarkCreateBatch inserts batches of 1000 ads into the migrated
+// PostgreSQL database at TEST_DATABASE_URL and is skipped without one. The
+// ads and their events are deleted afterwards.
+func BenchmarkCreateBatch(b *testing.B) {
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		b.Skip("TEST_DATABASE_URL not set")
+	}
+	db, err := gorm.Open(postgres.Open(databaseURL), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	repo := NewAdRepository(db)
+
+	var created []uint
+	b.Cleanup(func() {
+		if len(created) > 0 {
+			db.Exec("DELETE FROM outbox_events WHERE ad_id IN ?", created)
+			db.Exec("DELETE FROM ads WHERE id IN ?", created)
+		}
+	})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		ads := make([]*domain.Ad, 1000)
+		for j := range ads {
+			ads[j] = newTestAd()
+		}
+		b.StartTimer()
+
+		if _, err := repo.CreateBatch(context.Background(), ads); err != nil {
+			b.Fatal(err)
+		}
+		for _, ad := range ads {
+			created = append(created, ad.ID)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/1way-market/v3/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CategoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) *CategoryRepository {
+	return &CategoryRepository{db: db}
+}
+
+// GetByID returns the category with the given ID or domain.ErrNotFound
+func (r *CategoryRepository) GetByID(ctx context.Context, id int) (*domain.Category, error) {
+	var category domain.Category
+	if err := r.db.WithContext(ctx).First(&category, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("error getting category: %v", err)
+	}
+	return &category, nil
+}
+
+// Create saves a new category and adds it to category_closure below its
+// parent. A missing parent fails with domain.ErrConstraint.
+func (r *CategoryRepository) Create(ctx context.Context, category *domain.Category) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(category).Error; err != nil {
+			return dbError("creating category", err)
+		}
+
+		// The category is its own ancestor at depth 0, then come its parent's ancestors
+		err := tx.Exec(`INSERT INTO category_closure (ancestor_id, descendant_id, depth)
+			SELECT ?, ?, 0
+			UNION ALL
+			SELECT ancestor_id, ?, depth + 1 FROM category_closure WHERE descendant_id = ?`,
+			category.ID, category.ID, category.ID, category.ParentID).Error
+		if err != nil {
+			return fmt.Errorf("error adding category to the closure: %v", err)
+		}
+		return nil
+	})
+}
+
+// Update saves the category's name and parent, or returns domain.ErrNotFound.
+// When the parent changed the category is moved with its subtree in
+// category_closure; moving it below itself fails with domain.ErrCategoryCycle.
+func (r *CategoryRepository) Update(ctx context.Context, category *domain.Category) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the category so that concurrent moves cannot create a cycle
+		var previous domain.Category
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("parent_id").
+			Where("id = ?", category.ID).Take(&previous).Error
+		if err == gorm.ErrRecordNotFound {
+			return domain.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error locking category: %v", err)
+		}
+
+		moved := !equalParent(previous.ParentID, category.ParentID)
+		if moved && category.ParentID != nil {
+			subtree, err := getDescendants(tx, category.ID)
+			if err != nil {
+				return err
+			}
+			if slices.Contains(subtree, *category.ParentID) {
+				return domain.ErrCategoryCycle
+			}
+		}
+
+		err = tx.Model(&domain.Category{}).
+			Where("id = ?", category.ID).
+			Updates(map[string]interface{}{
+				"name":      category.Name,
+				"parent_id": category.ParentID,
+			}).Error
+		if err != nil {
+			return dbError("updating category", err)
+		}
+		if !moved {
+			return nil
+		}
+
+		// Detach the subtree from its former ancestors, then attach it below
+		// the new parent's ancestors
+		err = tx.Exec(`DELETE FROM category_closure
+			WHERE descendant_id IN (SELECT descendant_id FROM category_closure WHERE ancestor_id = ?)
+			AND ancestor_id NOT IN (SELECT descendant_id FROM category_closure WHERE ancestor_id = ?)`,
+			category.ID, category.ID).Error
+		if err != nil {
+			return fmt.Errorf("error detaching category from the closure: %v", err)
+		}
+		if category.ParentID == nil {
+			return nil
+		}
+		err = tx.Exec(`INSERT INTO category_closure (ancestor_id, descendant_id, depth)
+			SELECT ancestors.ancestor_id, subtree.descendant_id, ancestors.depth + subtree.depth + 1
+			FROM category_closure ancestors, category_closure subtree
+			WHERE ancestors.descendant_id = ? AND subtree.ancestor_id = ?`,
+			*category.ParentID, category.ID).Error
+		if err != nil {
+			return fmt.Errorf("error attaching category to the closure: %v", err)
+		}
+		return nil
+	})
+}
+
+// Delete deletes a category without subcategories, or returns
+// domain.ErrCategoryHasChildren or domain.ErrNotFound. Its closure rows are
+// deleted along with it.
+func (r *CategoryRepository) Delete(ctx context.Context, id int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var children int64
+		if err := tx.Model(&domain.Category{}).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+			return fmt.Errorf("error counting subcategories: %v", err)
+		}
+		if children > 0 {
+			return domain.ErrCategoryHasChildren
+		}
+
+		result := tx.Delete(&domain.Category{}, id)
+		if result.Error != nil {
+			return dbError("deleting category", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrNotFound
+		}
+		return nil
+	})
+}
+
+// GetPath returns the category's ancestors from the root down to the
+// category itself, read through category_closure, or domain.ErrNotFound
+func (r *CategoryRepository) GetPath(ctx context.Context, id int) ([]domain.Category, error) {
+	var path []domain.Category
+	err := r.db.WithContext(ctx).
+		Joins("JOIN category_closure ON category_closure.ancestor_id = categories.id").
+		Where("category_closure.descendant_id = ?", id).
+		Order("category_closure.depth DESC").
+		Find(&path).Error
+	if err != nil {
+		return nil, fmt.Errorf("error getting category path: %v", err)
+	}
+	if len(path) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return path, nil
+}
+
+// GetDescendants returns the IDs of the category and of every category below
+// it, read from category_closure
+func (r *CategoryRepository) GetDescendants(ctx context.Context, id int) ([]int, error) {
+	return getDescendants(r.db.WithContext(ctx), id)
+}
+
+func getDescendants(db *gorm.DB, id int) ([]int, error) {
+	var ids []int
+	err := db.Table("category_closure").
+		Where("ancestor_id = ?", id).
+		Pluck("descendant_id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("error getting category descendants: %v", err)
+	}
+	return ids, nil
+}
+
+// equalParent reports whether two parent IDs name the same parent, or both none
+func equalParent(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestGetPath(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectQuery(`SELECT "categories"."id",.* FROM "categories" JOIN category_closure ON category_closure.ancestor_id = categories.id WHERE category_closure.descendant_id = \$1 ORDER BY category_closure.depth DESC`).
+		WithArgs(12).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "parent_id"}).AddRow(1, nil).AddRow(5, 1).AddRow(12, 5))
+
+	path, err := NewCategoryRepository(db).GetPath(context.Background(), 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int
+	for _, category := range path {
+		ids = append(ids, category.ID)
+	}
+	if want := []int{1, 5, 12}; !slices.Equal(ids, want) {
+		t.Errorf("path = %v, want %v", ids, want)
+	}
+	if path[0].ParentID != nil || path[2].ParentID == nil || *path[2].ParentID != 5 {
+		t.Errorf("parents not read: %+v", path)
+	}
+}
+
+func TestGetPathOfMissingCategory(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectQuery(`FROM "categories" JOIN category_closure`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	if _, err := NewCategoryRepository(db).GetPath(context.Background(), 12); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("error = %v, want %v", err, domain.ErrNotFound)
+	}
+}
+
+func TestCreateCategoryAddsClosureRows(t *testing.T) {
+	db, mock := newMockDB(t)
+	parent := 5
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "categories"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+	mock.ExpectExec(`INSERT INTO category_closure .* SELECT \$1, \$2, 0 UNION ALL SELECT ancestor_id, \$3, depth \+ 1 FROM category_closure WHERE descendant_id = \$4`).
+		WithArgs(12, 12, 12, parent).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	category := &domain.Category{ParentID: &parent, Name: domain.MultiLangArray{{Lang: domain.LangEnglish, Text: "Bicycles"}}}
+	if err := NewCategoryRepository(db).Create(context.Background(), category); err != nil {
+		t.Fatal(err)
+	}
+	if category.ID != 12 {
+		t.Errorf("ID = %d, want 12", category.ID)
+	}
+}
+
+func TestMoveCategory(t *testing.T) {
+	newParent := 4
+	tests := []struct {
+		name    string
+		subtree []int
+		wantErr error
+	}{
+		{"under another branch", []int{2, 3}, nil},
+		{"under its own subcategory", []int{2, 3, 4}, domain.ErrCategoryCycle},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			mock.ExpectBegin()
+			mock.ExpectQuery(`SELECT "parent_id" FROM "categories" WHERE id = \$1 .*FOR UPDATE`).
+				WithArgs(2).
+				WillReturnRows(sqlmock.NewRows([]string{"parent_id"}).AddRow(1))
+			rows := sqlmock.NewRows([]string{"descendant_id"})
+			for _, id := range tt.subtree {
+				rows.AddRow(id)
+			}
+			mock.ExpectQuery(`SELECT "descendant_id" FROM "category_closure" WHERE ancestor_id = \$1`).
+				WithArgs(2).
+				WillReturnRows(rows)
+			if tt.wantErr != nil {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectExec(`UPDATE "categories" SET .*"parent_id"=\$2`).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`DELETE FROM category_closure WHERE descendant_id IN`).
+					WithArgs(2, 2).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(`INSERT INTO category_closure .* WHERE ancestors.descendant_id = \$1 AND subtree.ancestor_id = \$2`).
+					WithArgs(newParent, 2).
+					WillReturnResult(sqlmock.NewResult(0, 4))
+				mock.ExpectCommit()
+			}
+
+			category := &domain.Category{ID: 2, ParentID: &newParent, Name: domain.MultiLangArray{{Lang: domain.LangEnglish, Text: "Bicycles"}}}
+			if err := NewCategoryRepository(db).Update(context.Background(), category); !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDeleteCategoryWithChildren(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "categories" WHERE parent_id = \$1`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectRollback()
+
+	if err := NewCategoryRepository(db).Delete(context.Background(), 2); !errors.Is(err, domain.ErrCategoryHasChildren) {
+		t.Errorf("error = %v, want %v", err, domain.ErrCategoryHasChildren)
+	}
+}
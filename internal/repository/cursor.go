@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/1way-market/v3/internal/domain"
+)
+
+// adCursor is the position in an ad listing encoded in page tokens.
+// It carries the sort values of the last ad so that the next page resumes
+// exactly after it even if that ad was changed or deleted meanwhile.
+type adCursor struct {
+	// ID is the last ad of the previous page
+	ID uint `json:"id"`
+	// Sort is the sort mode the cursor was created for
+	Sort string `json:"sort,omitempty"`
+	// CreatedAt is the creation time of the last ad in unix microseconds
+	CreatedAt int64 `json:"created_at,omitempty"`
+	// Price is the price of the last ad, nil when it had none
+	Price *float64 `json:"price,omitempty"`
+	// Rank is the text search rank of the last ad when sorting by relevance
+	Rank *float64 `json:"rank,omitempty"`
+	// SnapshotAt is the time of the first page request in unix microseconds
+	SnapshotAt int64 `json:"snapshot_at"`
+}
+
+// after returns a cursor positioned after the given ad, keeping the snapshot
+func (c adCursor) after(ad *domain.Ad, sortBy string) adCursor {
+	next := adCursor{
+		ID:         ad.ID,
+		Sort:       sortBy,
+		CreatedAt:  ad.CreatedAt.UnixMicro(),
+		SnapshotAt: c.SnapshotAt,
+	}
+	if ad.Price != nil && ad.Price.Type != domain.PriceOnRequest {
+		price := ad.Price.Value
+		next.Price = &price
+	}
+	if sortBy == "relevance" {
+		rank := ad.SearchRank
+		next.Rank = &rank
+	}
+	return next
+}
+
+// encode renders the cursor as an opaque URL-safe page token
+func (c adCursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeAdCursor parses a page token produced by adCursor.encode
+func decodeAdCursor(token string) (adCursor, error) {
+	var cursor adCursor
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor, domain.ErrInvalidPageToken
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == 0 || cursor.SnapshotAt == 0 {
+		return cursor, domain.ErrInvalidPageToken
+	}
+	return cursor, nil
+}
+
+// changeCursor is the position in the ads change feed. Changes are ordered
+// by the ID of the transaction that made them, then by journal ID.
+type changeCursor struct {
+	TxID int64 `json:"txid"`
+	ID   int64 `json:"id"`
+}
+
+// encode renders the cursor as an opaque URL-safe token
+func (c changeCursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeChangeCursor parses a token produced by changeCursor.encode; an empty
+// token starts from the beginning of the feed
+func decodeChangeCursor(token string) (changeCursor, error) {
+	var cursor changeCursor
+	if token == "" {
+		return cursor, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor, domain.ErrInvalidPageToken
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.TxID <= 0 || cursor.ID <= 0 {
+		return cursor, domain.ErrInvalidPageToken
+	}
+	return cursor, nil
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PostgreSQL error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgUniqueViolation          = "23505"
+	pgIntegrityConstraintClass = "23"
+)
+
+// dbError describes an error of the database operation named by action,
+// wrapping domain.ErrDuplicate or domain.ErrConstraint when the statement
+// violated a constraint
+func dbError(action string, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == pgUniqueViolation:
+			return fmt.Errorf("error %s: %w: %s", action, domain.ErrDuplicate, pgErr.Message)
+		case strings.HasPrefix(pgErr.Code, pgIntegrityConstraintClass):
+			return fmt.Errorf("error %s: %w: %s", action, domain.ErrConstraint, pgErr.Message)
+		}
+	}
+	return fmt.Errorf("error %s: %v", action, err)
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestDBError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"unique violation", &pgconn.PgError{Code: "23505", Message: "duplicate key"}, domain.ErrDuplicate},
+		{"foreign key violation", &pgconn.PgError{Code: "23503", Message: "violates foreign key"}, domain.ErrConstraint},
+		{"check violation wrapped", fmt.Errorf("exec: %w", &pgconn.PgError{Code: "23514"}), domain.ErrConstraint},
+		{"syntax error", &pgconn.PgError{Code: "42601"}, nil},
+		{"connection error", errors.New("connection refused"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dbError("creating ad", tt.err)
+			for _, domainErr := range []error{domain.ErrDuplicate, domain.ErrConstraint} {
+				if got := errors.Is(err, domainErr); got != (domainErr == tt.want) {
+					t.Errorf("errors.Is(%v, %v) = %v", err, domainErr, got)
+				}
+			}
+		})
+	}
+}
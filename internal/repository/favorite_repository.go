@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/1way-market/v3/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FavoriteRepository struct {
+	db *gorm.DB
+}
+
+func NewFavoriteRepository(db *gorm.DB) *FavoriteRepository {
+	return &FavoriteRepository{db: db}
+}
+
+// Add saves the favorite, reporting whether the ad was not already a favorite of the user
+func (r *FavoriteRepository) Add(ctx context.Context, userID string, adID uint) (bool, error) {
+	favorite := domain.Favorite{UserID: userID, AdID: adID}
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&favorite)
+	if result.Error != nil {
+		return false, fmt.Errorf("error adding favorite: %v", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Remove deletes the favorite, reporting whether it existed
+func (r *FavoriteRepository) Remove(ctx context.Context, userID string, adID uint) (bool, error) {
+	result := r.db.WithContext(ctx).Delete(&domain.Favorite{}, "user_id = ? AND ad_id = ?", userID, adID)
+	if result.Error != nil {
+		return false, fmt.Errorf("error removing favorite: %v", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CountByAd returns the number of users who favorited the ad
+func (r *FavoriteRepository) CountByAd(ctx context.Context, adID uint) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.Favorite{}).Where("ad_id = ?", adID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("error counting favorites: %v", err)
+	}
+	return count, nil
+}
+
+// FavoritedAmong returns which of the ads the user favorited
+func (r *FavoriteRepository) FavoritedAmong(ctx context.Context, userID string, adIDs []uint) ([]uint, error) {
+	if len(adIDs) == 0 {
+		return nil, nil
+	}
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&domain.Favorite{}).
+		Where("user_id = ? AND ad_id IN ?", userID, adIDs).
+		Pluck("ad_id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("error reading favorites: %v", err)
+	}
+	return ids, nil
+}
+
+// ListAds returns the user's favorited active ads, most recently favorited first
+func (r *FavoriteRepository) ListAds(ctx context.Context, userID string, pageSize int, pageToken string) (*domain.PaginatedResponse, error) {
+	query := r.db.WithContext(ctx).Model(&domain.Ad{}).
+		Joins("JOIN favorites f ON f.ad_id = ads.id").
+		Where("f.user_id = ? AND ads.status = ?", userID, domain.StatusActive)
+
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, fmt.Errorf("error counting favorites: %v", err)
+	}
+
+	if pageSize == 0 {
+		pageSize = 20
+	}
+
+	if pageToken != "" {
+		favoritedAt, adID, err := parseFavoriteToken(pageToken)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("(f.created_at, f.ad_id) < (?, ?)", favoritedAt, adID)
+	}
+
+	var rows []struct {
+		domain.Ad
+		FavoritedAt time.Time
+	}
+	err := query.Select("ads.*, f.created_at AS favorited_at").
+		Order("f.created_at DESC, f.ad_id DESC").
+		Limit(pageSize + 1).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing favorites: %v", err)
+	}
+
+	response := &domain.PaginatedResponse{
+		TotalCount: totalCount,
+		Items:      make([]domain.Ad, 0, len(rows)),
+	}
+
+	if len(rows) > pageSize {
+		last := rows[pageSize-1]
+		response.NextPage = fmt.Sprintf("%d:%d", last.FavoritedAt.UnixMicro(), last.ID)
+		rows = rows[:pageSize]
+	}
+
+	for _, row := range rows {
+		response.Items = append(response.Items, row.Ad)
+	}
+
+	return response, nil
+}
+
+// parseFavoriteToken decodes a "<favorited_at unix micros>:<ad id>" page token
+func parseFavoriteToken(token string) (time.Time, uint, error) {
+	micros, id, ok := strings.Cut(token, ":")
+	if !ok {
+		return time.Time{}, 0, domain.ErrInvalidPageToken
+	}
+	ts, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return time.Time{}, 0, domain.ErrInvalidPageToken
+	}
+	adID, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return time.Time{}, 0, domain.ErrInvalidPageToken
+	}
+	return time.UnixMicro(ts), uint(adID), nil
+}
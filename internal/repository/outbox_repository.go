@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/1way-market/v3/internal/domain"
+	"gorm.io/gorm"
+)
+
+// recordAdEvent writes an event carrying the current state of the ad to the
+// outbox. It runs in the transaction of the change, so the event is recorded
+// if and only if the change commits.
+func recordAdEvent(tx *gorm.DB, eventType string, id uint) error {
+	var ad domain.Ad
+	if err := tx.First(&ad, id).Error; err != nil {
+		return fmt.Errorf("error reading ad for %s event: %v", eventType, err)
+	}
+	payload, err := json.Marshal(&ad)
+	if err != nil {
+		return fmt.Errorf("error encoding %s event: %v", eventType, err)
+	}
+
+	err = tx.Exec("INSERT INTO outbox_events (event_type, ad_id, payload) VALUES (?, ?, ?::jsonb)",
+		eventType, id, string(payload)).Error
+	if err != nil {
+		return fmt.Errorf("error recording %s event: %v", eventType, err)
+	}
+	return nil
+}
+
+// outboxLockKey is the advisory lock held by the instance relaying the outbox
+const outboxLockKey = 0x6f7574626f78
+
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// RelayUnsent passes up to limit unsent events, in sequence order, to publish
+// and marks the first n it reports as published sent.
+//
+// It runs in one transaction holding an advisory lock, so only one instance
+// relays at a time and events go out in order; while another instance holds
+// the lock it returns 0 without calling publish. Like the ad change feed,
+// events are only read from transactions older than every running one, so an
+// event is never relayed before an earlier one commits. Events published when
+// the transaction fails, e.g. on a crash, are not marked and are published
+// again.
+func (r *OutboxRepository) RelayUnsent(ctx context.Context, limit int, publish func([]domain.OutboxEvent) (int, error)) (int, error) {
+	var sent int
+	var publishErr error
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", outboxLockKey).Scan(&locked).Error; err != nil {
+			return fmt.Errorf("error locking outbox: %v", err)
+		}
+		if !locked {
+			return nil
+		}
+
+		// The payload is read as text; drivers differ in how they return jsonb
+		var rows []struct {
+			ID        int64
+			EventType string
+			AdID      uint
+			Payload   string
+			CreatedAt time.Time
+		}
+		err := tx.Table("outbox_events").
+			Select("id, event_type, ad_id, payload::text AS payload, created_at").
+			Where("sent_at IS NULL").
+			Where("txid < txid_snapshot_xmin(txid_current_snapshot())").
+			Order("id").
+			Limit(limit).
+			Scan(&rows).Error
+		if err != nil {
+			return fmt.Errorf("error listing outbox events: %v", err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		events := make([]domain.OutboxEvent, 0, len(rows))
+		for _, row := range rows {
+			events = append(events, domain.OutboxEvent{
+				Sequence:  row.ID,
+				Type:      row.EventType,
+				AdID:      row.AdID,
+				Payload:   json.RawMessage(row.Payload),
+				CreatedAt: row.CreatedAt,
+			})
+		}
+
+		sent, publishErr = publish(events)
+		if sent == 0 {
+			return nil
+		}
+
+		ids := make([]int64, 0, sent)
+		for _, event := range events[:sent] {
+			ids = append(ids, event.Sequence)
+		}
+		err = tx.Table("outbox_events").Where("id IN ?", ids).Update("sent_at", gorm.Expr("CURRENT_TIMESTAMP")).Error
+		if err != nil {
+			return fmt.Errorf("error marking outbox events sent: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return sent, publishErr
+}
+
+// DeleteSentBefore removes the events relayed before the given time
+func (r *OutboxRepository) DeleteSentBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Exec("DELETE FROM outbox_events WHERE sent_at < ?", before)
+	if result.Error != nil {
+		return 0, fmt.Errorf("error deleting sent outbox events: %v", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/1way-market/v3/internal/domain"
+	"gorm.io/gorm"
+)
+
+type PhoneRevealRepository struct {
+	db *gorm.DB
+}
+
+func NewPhoneRevealRepository(db *gorm.DB) *PhoneRevealRepository {
+	return &PhoneRevealRepository{db: db}
+}
+
+func (r *PhoneRevealRepository) Create(ctx context.Context, reveal *domain.PhoneReveal) error {
+	if err := r.db.WithContext(ctx).Create(reveal).Error; err != nil {
+		return fmt.Errorf("error recording phone reveal: %v", err)
+	}
+	return nil
+}
+
+// CountByAd returns the number of times the phone of the ad was revealed
+func (r *PhoneRevealRepository) CountByAd(ctx context.Context, adID uint) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.PhoneReveal{}).Where("ad_id = ?", adID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("error counting phone reveals: %v", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/1way-market/v3/internal/domain"
+	"gorm.io/gorm"
+)
+
+type PropertyRepository struct {
+	db *gorm.DB
+}
+
+func NewPropertyRepository(db *gorm.DB) *PropertyRepository {
+	return &PropertyRepository{db: db}
+}
+
+// NamesByID returns the display names of the given properties
+func (r *PropertyRepository) NamesByID(ctx context.Context, ids []uint) (map[uint]string, error) {
+	names := make(map[uint]string, len(ids))
+	if len(ids) == 0 {
+		return names, nil
+	}
+
+	var properties []domain.Property
+	if err := r.db.WithContext(ctx).Select("id", "name").Where("id IN ?", ids).Find(&properties).Error; err != nil {
+		return nil, fmt.Errorf("error getting property names: %v", err)
+	}
+
+	for _, property := range properties {
+		names[property.ID] = property.Name
+	}
+	return names, nil
+}
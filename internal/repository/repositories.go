@@ -1,15 +1,44 @@
 package repository
 
 import (
+	"context"
+
 	"gorm.io/gorm"
 )
 
 type Repositories struct {
-	Ad *AdRepository
+	db *gorm.DB
+
+	Ad          *AdRepository
+	AdReport    *AdReportRepository
+	Category    *CategoryRepository
+	Favorite    *FavoriteRepository
+	Outbox      *OutboxRepository
+	Property    *PropertyRepository
+	PhoneReveal *PhoneRevealRepository
+	Seller      *SellerRepository
 }
 
 func NewRepositories(db *gorm.DB) *Repositories {
 	return &Repositories{
-		Ad: NewAdRepository(db),
+		db:          db,
+		Ad:          NewAdRepository(db),
+		AdReport:    NewAdReportRepository(db),
+		Category:    NewCategoryRepository(db),
+		Favorite:    NewFavoriteRepository(db),
+		Outbox:      NewOutboxRepository(db),
+		Property:    NewPropertyRepository(db),
+		PhoneReveal: NewPhoneRevealRepository(db),
+		Seller:      NewSellerRepository(db),
 	}
 }
+
+// WithTx runs fn in a transaction with repositories bound to it, committing
+// when fn returns nil and rolling back when it returns an error or panics.
+// Methods that open their own transaction run under a savepoint within it;
+// ExplainFindWithFilter, which needs a connection of its own, fails.
+func (r *Repositories) WithTx(ctx context.Context, fn func(repos *Repositories) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(NewRepositories(tx))
+	})
+}
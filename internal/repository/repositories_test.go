@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestWithTxCommits(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "sellers"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectExec(`INSERT INTO "favorites"`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := NewRepositories(db).WithTx(context.Background(), func(repos *Repositories) error {
+		seller := &domain.Seller{Name: domain.MultiLangArray{{Lang: domain.LangEnglish, Text: "Bike shop"}}}
+		if err := repos.Seller.Create(context.Background(), seller); err != nil {
+			return err
+		}
+		_, err := repos.Favorite.Add(context.Background(), "user-1", 42)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithTxRollsBackEarlierWrites(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "sellers"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	// Ad.Create opens its own transaction, a savepoint within this one
+	mock.ExpectExec(`SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`INSERT INTO "ads"`).WillReturnError(errors.New("connection reset"))
+	mock.ExpectExec(`ROLLBACK TO SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+	// The seller inserted first goes with the transaction; nothing commits
+	mock.ExpectRollback()
+
+	err := NewRepositories(db).WithTx(context.Background(), func(repos *Repositories) error {
+		seller := &domain.Seller{Name: domain.MultiLangArray{{Lang: domain.LangEnglish, Text: "Bike shop"}}}
+		if err := repos.Seller.Create(context.Background(), seller); err != nil {
+			return err
+		}
+		ad := newTestAd()
+		ad.SellerID = &seller.ID
+		return repos.Ad.Create(context.Background(), ad)
+	})
+	if err == nil {
+		t.Fatal("WithTx succeeded although its second write failed")
+	}
+}
+
+func TestWithTxRollsBackOnPanic(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "sellers"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectRollback()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("WithTx swallowed the panic")
+		}
+	}()
+	NewRepositories(db).WithTx(context.Background(), func(repos *Repositories) error {
+		seller := &domain.Seller{Name: domain.MultiLangArray{{Lang: domain.LangEnglish, Text: "Bike shop"}}}
+		if err := repos.Seller.Create(context.Background(), seller); err != nil {
+			return err
+		}
+		panic("callback failed")
+	})
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/1way-market/v3/internal/domain"
+	"gorm.io/gorm"
+)
+
+type SellerRepository struct {
+	db *gorm.DB
+}
+
+func NewSellerRepository(db *gorm.DB) *SellerRepository {
+	return &SellerRepository{db: db}
+}
+
+func (r *SellerRepository) Create(ctx context.Context, seller *domain.Seller) error {
+	if err := r.db.WithContext(ctx).Create(seller).Error; err != nil {
+		return fmt.Errorf("error creating seller: %v", err)
+	}
+	return nil
+}
+
+// Update saves the seller's details and reports whether the seller exists
+func (r *SellerRepository) Update(ctx context.Context, seller *domain.Seller) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.Seller{}).
+		Where("id = ?", seller.ID).
+		Updates(map[string]interface{}{
+			"name":        seller.Name,
+			"phone":       seller.Phone,
+			"email":       seller.Email,
+			"is_verified": seller.IsVerified,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("error updating seller: %v", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *SellerRepository) Delete(ctx context.Context, id uint) error {
+	if err := r.db.WithContext(ctx).Delete(&domain.Seller{}, id).Error; err != nil {
+		return fmt.Errorf("error deleting seller: %v", err)
+	}
+	return nil
+}
+
+func (r *SellerRepository) GetByID(ctx context.Context, id uint) (*domain.Seller, error) {
+	var seller domain.Seller
+	if err := r.db.WithContext(ctx).First(&seller, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting seller: %v", err)
+	}
+	return &seller, nil
+}
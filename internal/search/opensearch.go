@@ -0,0 +1,509 @@
+// Package search indexes ads in OpenSearch, which answers text searches with
+// typo tolerance and facets beyond what the database full-text search offers.
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/1way-market/v3/internal/domain"
+)
+
+// requestTimeout bounds each request to the cluster
+const requestTimeout = 10 * time.Second
+
+// facetSize caps the number of values counted per facet
+const facetSize = 100
+
+// languageAnalyzers are the OpenSearch analyzers of the languages titles and
+// descriptions are indexed in
+var languageAnalyzers = map[domain.Language]string{
+	domain.LangRussian: "russian",
+	domain.LangEnglish: "english",
+	domain.LangTurkish: "turkish",
+}
+
+// OpenSearch is an ad search index in an OpenSearch cluster, reached through its REST API
+type OpenSearch struct {
+	baseURL string
+	index   string
+	client  *http.Client
+}
+
+// NewOpenSearch returns the index named index of the cluster at baseURL, which may
+// carry basic auth credentials
+func NewOpenSearch(baseURL, index string) *OpenSearch {
+	return &OpenSearch{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		index:   index,
+		client:  &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// document is the indexed form of an ad. Texts are keyed by language code so
+// that each is analyzed in its own language.
+type document struct {
+	ID          uint               `json:"id"`
+	Title       map[string]string  `json:"title"`
+	Description map[string]string  `json:"description,omitempty"`
+	CategoryIDs []int              `json:"category_ids,omitempty"`
+	Status      int                `json:"status"`
+	SellerID    *uint              `json:"seller_id,omitempty"`
+	OwnerID     string             `json:"owner_id,omitempty"`
+	Price       *documentPrice     `json:"price,omitempty"`
+	Properties  []documentProperty `json:"properties,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+	ExpiresAt   *time.Time         `json:"expires_at,omitempty"`
+}
+
+type documentPrice struct {
+	Type int `json:"type"`
+	// Value is unset for price-on-request ads, which sort last like in the database
+	Value    *float64 `json:"value,omitempty"`
+	Currency string   `json:"currency,omitempty"`
+}
+
+type documentProperty struct {
+	ID      uint   `json:"id"`
+	Value   string `json:"value,omitempty"`
+	ValueID *uint  `json:"value_id,omitempty"`
+}
+
+func newDocument(ad *domain.Ad) document {
+	doc := document{
+		ID:          ad.ID,
+		Title:       multiLangMap(ad.Title),
+		Description: multiLangMap(ad.Description),
+		CategoryIDs: ad.CategoryIDs,
+		Status:      int(ad.Status),
+		SellerID:    ad.SellerID,
+		OwnerID:     ad.OwnerID,
+		CreatedAt:   ad.CreatedAt,
+		ExpiresAt:   ad.ExpiresAt,
+	}
+	if ad.Price != nil {
+		doc.Price = &documentPrice{Type: int(ad.Price.Type), Currency: ad.Price.Currency}
+		if ad.Price.Type != domain.PriceOnRequest {
+			value := ad.Price.Value
+			doc.Price.Value = &value
+		}
+	}
+	for _, prop := range ad.Properties {
+		doc.Properties = append(doc.Properties, documentProperty{ID: prop.ID, Value: prop.Value, ValueID: prop.ValueID})
+	}
+	return doc
+}
+
+func multiLangMap(texts domain.MultiLangArray) map[string]string {
+	if len(texts) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(texts))
+	for _, text := range texts {
+		m[text.Lang.Code()] = text.Text
+	}
+	return m
+}
+
+// indexMapping is the mapping of the ads index
+func indexMapping() map[string]interface{} {
+	texts := make(map[string]interface{}, len(languageAnalyzers))
+	for lang, analyzer := range languageAnalyzers {
+		texts[lang.Code()] = map[string]interface{}{"type": "text", "analyzer": analyzer}
+	}
+	return map[string]interface{}{
+		"mappings": map[string]interface{}{
+			"dynamic": "strict",
+			"properties": map[string]interface{}{
+				"id":           map[string]interface{}{"type": "long"},
+				"title":        map[string]interface{}{"properties": texts},
+				"description":  map[string]interface{}{"properties": texts},
+				"category_ids": map[string]interface{}{"type": "integer"},
+				"status":       map[string]interface{}{"type": "integer"},
+				"seller_id":    map[string]interface{}{"type": "long"},
+				"owner_id":     map[string]interface{}{"type": "keyword"},
+				"price": map[string]interface{}{"properties": map[string]interface{}{
+					"type":     map[string]interface{}{"type": "integer"},
+					"value":    map[string]interface{}{"type": "double"},
+					"currency": map[string]interface{}{"type": "keyword"},
+				}},
+				"properties": map[string]interface{}{"type": "nested", "properties": map[string]interface{}{
+					"id":       map[string]interface{}{"type": "long"},
+					"value":    map[string]interface{}{"type": "keyword"},
+					"value_id": map[string]interface{}{"type": "long"},
+				}},
+				"created_at": map[string]interface{}{"type": "date"},
+				"expires_at": map[string]interface{}{"type": "date"},
+			},
+		},
+	}
+}
+
+// EnsureIndex creates the index with its mapping unless it exists
+func (s *OpenSearch) EnsureIndex(ctx context.Context) error {
+	status, body, err := s.send(ctx, http.MethodHead, "/"+s.index, "", nil)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusOK {
+		return nil
+	}
+	if status != http.StatusNotFound {
+		return statusError("checking index", status, body)
+	}
+
+	mapping, _ := json.Marshal(indexMapping())
+	status, body, err = s.send(ctx, http.MethodPut, "/"+s.index, "application/json", mapping)
+	if err != nil {
+		return err
+	}
+	if status >= http.StatusMultipleChoices {
+		return statusError("creating index", status, body)
+	}
+	return nil
+}
+
+// IndexAd adds or replaces the document of the ad
+func (s *OpenSearch) IndexAd(ctx context.Context, ad *domain.Ad) error {
+	data, err := json.Marshal(newDocument(ad))
+	if err != nil {
+		return fmt.Errorf("error encoding ad %d: %v", ad.ID, err)
+	}
+	status, body, err := s.send(ctx, http.MethodPut, fmt.Sprintf("/%s/_doc/%d", s.index, ad.ID), "application/json", data)
+	if err != nil {
+		return err
+	}
+	if status >= http.StatusMultipleChoices {
+		return statusError("indexing ad", status, body)
+	}
+	return nil
+}
+
+// IndexAds adds or replaces the documents of the ads in one bulk request
+func (s *OpenSearch) IndexAds(ctx context.Context, ads []domain.Ad) error {
+	if len(ads) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for i := range ads {
+		action := map[string]interface{}{"index": map[string]interface{}{"_index": s.index, "_id": strconv.FormatUint(uint64(ads[i].ID), 10)}}
+		if err := encoder.Encode(action); err != nil {
+			return err
+		}
+		if err := encoder.Encode(newDocument(&ads[i])); err != nil {
+			return fmt.Errorf("error encoding ad %d: %v", ads[i].ID, err)
+		}
+	}
+
+	status, body, err := s.send(ctx, http.MethodPost, "/_bulk", "application/x-ndjson", buf.Bytes())
+	if err != nil {
+		return err
+	}
+	if status >= http.StatusMultipleChoices {
+		return statusError("indexing ads", status, body)
+	}
+
+	// A bulk request succeeds as a whole even when some of its items fail
+	var response struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			ID    string          `json:"_id"`
+			Error json.RawMessage `json:"error"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("error decoding bulk response: %v", err)
+	}
+	if response.Errors {
+		for _, item := range response.Items {
+			for _, result := range item {
+				if len(result.Error) > 0 {
+					return fmt.Errorf("error indexing ad %s: %s", result.ID, result.Error)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// RemoveAd deletes the document of the ad; a missing document is not an error
+func (s *OpenSearch) RemoveAd(ctx context.Context, id uint) error {
+	status, body, err := s.send(ctx, http.MethodDelete, fmt.Sprintf("/%s/_doc/%d", s.index, id), "", nil)
+	if err != nil {
+		return err
+	}
+	if status >= http.StatusMultipleChoices && status != http.StatusNotFound {
+		return statusError("removing ad", status, body)
+	}
+	return nil
+}
+
+// Search returns the IDs of a page of ads matching the filter, with the
+// category and status facets of all matching ads
+func (s *OpenSearch) Search(ctx context.Context, filter domain.FilterRequest) (*domain.SearchResult, error) {
+	cursor, err := decodeSearchCursor(filter.PageToken, filter.SortBy)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := filter.PageSize
+	if pageSize == 0 {
+		pageSize = domain.DefaultPageSize
+	}
+	request := map[string]interface{}{
+		"size":             pageSize + 1,
+		"_source":          false,
+		"track_total_hits": true,
+		"query":            buildQuery(filter),
+		"sort":             buildSort(filter.SortBy),
+		"aggs": map[string]interface{}{
+			"category_ids": map[string]interface{}{"terms": map[string]interface{}{"field": "category_ids", "size": facetSize}},
+			"status":       map[string]interface{}{"terms": map[string]interface{}{"field": "status", "size": facetSize}},
+		},
+	}
+	if len(cursor.After) > 0 {
+		request["search_after"] = cursor.After
+	}
+
+	data, _ := json.Marshal(request)
+	status, body, err := s.send(ctx, http.MethodPost, "/"+s.index+"/_search", "application/json", data)
+	if err != nil {
+		return nil, err
+	}
+	if status >= http.StatusMultipleChoices {
+		return nil, statusError("searching ads", status, body)
+	}
+
+	var response struct {
+		Hits struct {
+			Total struct {
+				Value int64 `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				ID   string            `json:"_id"`
+				Sort []json.RawMessage `json:"sort"`
+			} `json:"hits"`
+		} `json:"hits"`
+		Aggregations map[string]struct {
+			Buckets []struct {
+				Key      json.RawMessage `json:"key"`
+				DocCount int64           `json:"doc_count"`
+			} `json:"buckets"`
+		} `json:"aggregations"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("error decoding search response: %v", err)
+	}
+
+	hits := response.Hits.Hits
+	result := &domain.SearchResult{Total: response.Hits.Total.Value, Facets: make(map[string][]domain.FacetCount)}
+	if len(hits) > pageSize {
+		hits = hits[:pageSize]
+		result.Next = searchCursor{Sort: filter.SortBy, After: hits[pageSize-1].Sort}.encode()
+	}
+	for _, hit := range hits {
+		id, err := strconv.ParseUint(hit.ID, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ad ID %q in search index", hit.ID)
+		}
+		result.IDs = append(result.IDs, uint(id))
+	}
+	for name, aggregation := range response.Aggregations {
+		counts := make([]domain.FacetCount, 0, len(aggregation.Buckets))
+		for _, bucket := range aggregation.Buckets {
+			counts = append(counts, domain.FacetCount{Value: strings.Trim(string(bucket.Key), `"`), Count: bucket.DocCount})
+		}
+		result.Facets[name] = counts
+	}
+	return result, nil
+}
+
+// buildQuery translates the filter into a bool query. The text search
+// tolerates typos and weighs titles over descriptions.
+func buildQuery(filter domain.FilterRequest) map[string]interface{} {
+	var must, filters, mustNot []interface{}
+
+	if filter.TextSearch != "" {
+		lang := filter.Language.Code()
+		must = append(must, map[string]interface{}{"multi_match": map[string]interface{}{
+			"query":     filter.TextSearch,
+			"fields":    []string{"title." + lang + "^2", "description." + lang},
+			"fuzziness": "AUTO",
+		}})
+	}
+
+	if len(filter.CategoryIDs) > 0 {
+		filters = append(filters, terms("category_ids", filter.CategoryIDs))
+	}
+	if filter.SellerID != nil {
+		filters = append(filters, term("seller_id", *filter.SellerID))
+	}
+	if filter.OwnerID != "" {
+		filters = append(filters, term("owner_id", filter.OwnerID))
+	}
+	if filter.Status != nil {
+		filters = append(filters, term("status", int(*filter.Status)))
+	}
+	if len(filter.Statuses) > 0 {
+		statuses := make([]int, 0, len(filter.Statuses))
+		for _, status := range filter.Statuses {
+			statuses = append(statuses, int(status))
+		}
+		filters = append(filters, terms("status", statuses))
+	}
+
+	for _, prop := range filter.PropertyFilters {
+		if len(prop.Values) == 0 && len(prop.ValueIDs) == 0 {
+			continue
+		}
+		conditions := []interface{}{term("properties.id", prop.PropertyID)}
+		if len(prop.Values) > 0 {
+			conditions = append(conditions, terms("properties.value", prop.Values))
+		}
+		if len(prop.ValueIDs) > 0 {
+			conditions = append(conditions, terms("properties.value_id", prop.ValueIDs))
+		}
+		filters = append(filters, map[string]interface{}{"nested": map[string]interface{}{
+			"path":  "properties",
+			"query": map[string]interface{}{"bool": map[string]interface{}{"filter": conditions}},
+		}})
+	}
+
+	if !filter.IncludeExpired {
+		mustNot = append(mustNot, map[string]interface{}{"range": map[string]interface{}{"expires_at": map[string]interface{}{"lte": "now"}}})
+	}
+
+	// Price ranges never match price-on-request ads
+	if filter.ExcludePriceOnRequest || filter.MinPrice != nil || filter.MaxPrice != nil {
+		mustNot = append(mustNot, term("price.type", int(domain.PriceOnRequest)))
+	}
+	if filter.Currency != "" {
+		filters = append(filters, term("price.currency", filter.Currency))
+	}
+	if filter.MinPrice != nil || filter.MaxPrice != nil {
+		bounds := map[string]interface{}{}
+		if filter.MinPrice != nil {
+			bounds["gte"] = *filter.MinPrice
+		}
+		if filter.MaxPrice != nil {
+			bounds["lte"] = *filter.MaxPrice
+		}
+		filters = append(filters, map[string]interface{}{"range": map[string]interface{}{"price.value": bounds}})
+	}
+
+	clauses := map[string]interface{}{}
+	for name, list := range map[string][]interface{}{"must": must, "filter": filters, "must_not": mustNot} {
+		if len(list) > 0 {
+			clauses[name] = list
+		}
+	}
+	return map[string]interface{}{"bool": clauses}
+}
+
+// buildSort orders hits like the database listing, breaking ties by id
+func buildSort(sortBy string) []interface{} {
+	order := func(field, direction string) map[string]interface{} {
+		return map[string]interface{}{field: map[string]interface{}{"order": direction, "missing": "_last"}}
+	}
+	switch sortBy {
+	case "price_asc":
+		return []interface{}{order("price.value", "asc"), order("id", "asc")}
+	case "price_desc":
+		return []interface{}{order("price.value", "desc"), order("id", "desc")}
+	case "date_asc":
+		return []interface{}{order("created_at", "asc"), order("id", "asc")}
+	case "relevance":
+		return []interface{}{map[string]interface{}{"_score": "desc"}, order("id", "desc")}
+	default:
+		return []interface{}{order("created_at", "desc"), order("id", "desc")}
+	}
+}
+
+func term(field string, value interface{}) map[string]interface{} {
+	return map[string]interface{}{"term": map[string]interface{}{field: value}}
+}
+
+func terms(field string, values interface{}) map[string]interface{} {
+	return map[string]interface{}{"terms": map[string]interface{}{field: values}}
+}
+
+// searchCursor is the position in search results encoded in page tokens: the
+// sort values of the last hit, which the next page searches after
+type searchCursor struct {
+	Sort  string            `json:"sort,omitempty"`
+	After []json.RawMessage `json:"after"`
+}
+
+// encode renders the cursor as an opaque URL-safe page token
+func (c searchCursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeSearchCursor parses a page token produced by searchCursor.encode for
+// the given sort mode; an empty token starts at the first page
+func decodeSearchCursor(token, sortBy string) (searchCursor, error) {
+	var cursor searchCursor
+	if token == "" {
+		return cursor, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor, domain.ErrInvalidPageToken
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil || len(cursor.After) == 0 || cursor.Sort != sortBy {
+		return cursor, domain.ErrInvalidPageToken
+	}
+	return cursor, nil
+}
+
+// send performs a request against the cluster and returns the response status and body
+func (s *OpenSearch) send(ctx context.Context, method, path, contentType string, body []byte) (int, []byte, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.baseURL+path, reader)
+	if err != nil {
+		return 0, nil, fmt.Errorf("error building OpenSearch request: %v", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if req.URL.User != nil {
+		password, _ := req.URL.User.Password()
+		req.SetBasicAuth(req.URL.User.Username(), password)
+		req.URL.User = nil
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("error calling OpenSearch: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("error reading OpenSearch response: %v", err)
+	}
+	return resp.StatusCode, data, nil
+}
+
+// statusError describes a failed request by its status and the start of the response
+func statusError(action string, status int, body []byte) error {
+	const maxBody = 500
+	if len(body) > maxBody {
+		body = body[:maxBody]
+	}
+	return fmt.Errorf("error %s: OpenSearch returned %d: %s", action, status, body)
+}
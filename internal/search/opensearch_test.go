@@ -0,0 +1,48 @@
+package search
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/1way-market/v3/internal/domain"
+)
+
+func TestBuildQueryFilters(t *testing.T) {
+	draft := domain.StatusDraft
+	sellerID := uint(9)
+	minPrice := 100.0
+
+	tests := []struct {
+		name   string
+		filter domain.FilterRequest
+		want   string
+	}{
+		{"no filter", domain.FilterRequest{}, `{"bool":{"must_not":[{"range":{"expires_at":{"lte":"now"}}}]}}`},
+		{"include expired", domain.FilterRequest{IncludeExpired: true}, `{"bool":{}}`},
+		{"status within visible statuses", domain.FilterRequest{
+			Status:         &draft,
+			IncludeExpired: true,
+			Statuses:       []domain.AdStatus{domain.StatusActive, domain.StatusApproved},
+		}, `{"bool":{"filter":[{"term":{"status":0}},{"terms":{"status":[3,6]}}]}}`},
+		{"categories and seller", domain.FilterRequest{
+			CategoryIDs:    []int{1, 2},
+			SellerID:       &sellerID,
+			IncludeExpired: true,
+		}, `{"bool":{"filter":[{"terms":{"category_ids":[1,2]}},{"term":{"seller_id":9}}]}}`},
+		{"price range leaves out price on request", domain.FilterRequest{
+			MinPrice:       &minPrice,
+			IncludeExpired: true,
+		}, `{"bool":{"filter":[{"range":{"price.value":{"gte":100}}}],"must_not":[{"term":{"price.type":3}}]}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(buildQuery(tt.filter))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("query = %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
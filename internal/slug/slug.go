@@ -0,0 +1,77 @@
+// Package slug builds human-readable URL segments from titles.
+package slug
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/1way-market/v3/internal/domain"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxTextLength caps the number of characters taken from the text
+const maxTextLength = 80
+
+// stripMarks decomposes characters and drops their diacritics, e.g. "ş" to "s"
+var stripMarks = transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+// Generate returns a slug for the text in the given language followed by the
+// ID, e.g. "red-bicycle-42". The ID keeps slugs unique and lets ID parse them back.
+func Generate(text string, lang domain.Language, id uint) string {
+	if lang == domain.LangTurkish {
+		text = strings.ToLowerSpecial(unicode.TurkishCase, text)
+	} else {
+		text = strings.ToLower(text)
+	}
+	// Dotless i has no diacritic to strip
+	text = strings.ReplaceAll(text, "ı", "i")
+	if stripped, _, err := transform.String(stripMarks, text); err == nil {
+		text = stripped
+	}
+
+	var b strings.Builder
+	length := 0
+	dash := false
+	for _, r := range text {
+		if length >= maxTextLength {
+			break
+		}
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+				length++
+			}
+			b.WriteRune(r)
+			length++
+			dash = false
+		case unicode.IsSpace(r) || r == '-' || r == '_':
+			dash = true
+		}
+	}
+
+	if b.Len() > 0 {
+		b.WriteByte('-')
+	}
+	b.WriteString(strconv.FormatUint(uint64(id), 10))
+	return b.String()
+}
+
+// ID returns the ID a slug made by Generate ends with
+func ID(slug string) (uint, bool) {
+	id, err := strconv.ParseUint(slug[strings.LastIndexByte(slug, '-')+1:], 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// ForAd returns the slug of a saved ad, from its English title when it has
+// one since those give the most readable URLs
+func ForAd(ad *domain.Ad) string {
+	title := ad.Title.Resolve(domain.LangEnglish)
+	return Generate(title.Text, title.Lang, ad.ID)
+}
@@ -0,0 +1,178 @@
+// Package storage keeps ad images in an S3-compatible bucket, such as AWS S3
+// or MinIO, reached through its REST API.
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// requestTimeout bounds each request to the bucket
+const requestTimeout = 30 * time.Second
+
+// S3 is a bucket of an S3-compatible object storage. Objects are addressed
+// path-style, as MinIO expects by default, and requests are signed with AWS
+// Signature Version 4.
+type S3 struct {
+	endpoint  *url.URL
+	bucket    string
+	region    string
+	accessKey string
+	secretKey string
+	publicURL string
+	client    *http.Client
+}
+
+// NewS3 returns the bucket at endpoint. Object URLs handed to clients start
+// with publicURL, or with the bucket's own URL when it is empty.
+func NewS3(endpoint, bucket, region, accessKey, secretKey, publicURL string) (*S3, error) {
+	u, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid storage endpoint %q", endpoint)
+	}
+	if publicURL == "" {
+		publicURL = u.String() + "/" + bucket
+	}
+	return &S3{
+		endpoint:  u,
+		bucket:    bucket,
+		region:    region,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+		client:    &http.Client{Timeout: requestTimeout},
+	}, nil
+}
+
+// Put stores data under key
+func (s *S3) Put(ctx context.Context, key, contentType string, data []byte) error {
+	status, body, err := s.send(ctx, http.MethodPut, key, contentType, data)
+	if err != nil {
+		return err
+	}
+	if status >= http.StatusMultipleChoices {
+		return statusError("storing "+key, status, body)
+	}
+	return nil
+}
+
+// Delete removes the object under key; removing a missing object succeeds
+func (s *S3) Delete(ctx context.Context, key string) error {
+	status, body, err := s.send(ctx, http.MethodDelete, key, "", nil)
+	if err != nil {
+		return err
+	}
+	if status >= http.StatusMultipleChoices && status != http.StatusNotFound {
+		return statusError("deleting "+key, status, body)
+	}
+	return nil
+}
+
+// URL returns the public URL of the object under key
+func (s *S3) URL(key string) string {
+	return s.publicURL + "/" + escapeKey(key)
+}
+
+// send makes a signed request for the object under key and returns the
+// response status and body
+func (s *S3) send(ctx context.Context, method, key, contentType string, data []byte) (int, []byte, error) {
+	target := *s.endpoint
+	target.Path = s.endpoint.Path + "/" + s.bucket + "/" + key
+	target.RawPath = s.endpoint.EscapedPath() + "/" + escapeKey(s.bucket) + "/" + escapeKey(key)
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(data))
+	if err != nil {
+		return 0, nil, fmt.Errorf("error creating storage request: %v", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.sign(req, data, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("error calling storage: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("error reading storage response: %v", err)
+	}
+	return resp.StatusCode, body, nil
+}
+
+// sign adds the AWS Signature Version 4 authorization of the request, which
+// covers the host, the payload hash and the date
+func (s *S3) sign(req *http.Request, payload []byte, now time.Time) {
+	payloadHash := sha256Hex(payload)
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + s.region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), day)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+// escapeKey escapes each segment of an object key as S3 expects in paths
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// statusError describes a failed storage request
+func statusError(action string, status int, body []byte) error {
+	return fmt.Errorf("error %s: storage returned %d: %s", action, status, bytes.TrimSpace(body))
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/go-redis/redis/v8"
+)
+
+// activationLockKey is held by the instance activating approved ads, so that
+// one instance runs each activation
+const activationLockKey = "lock:ads:activation"
+
+// ActivateApproved makes the ads approved for longer than delay active and
+// returns how many were activated
+func (uc *AdUseCase) ActivateApproved(ctx context.Context, delay time.Duration) (int, error) {
+	ads, err := uc.repo.ActivateApproved(ctx, delay, uc.cfg.AdLifetime)
+	if err != nil {
+		return 0, err
+	}
+	uc.statusesChanged(ctx, ads, domain.StatusApproved)
+	return len(ads), nil
+}
+
+// statusesChanged propagates a bulk change of the ads from the previous status
+// to their current one to the category counts, caches and search index
+func (uc *AdUseCase) statusesChanged(ctx context.Context, ads []domain.Ad, previous domain.AdStatus) {
+	if len(ads) == 0 {
+		return
+	}
+
+	deltas := make(map[int]int64)
+	for i := range ads {
+		before := ads[i]
+		before.Status = previous
+		addCategoryDeltas(deltas, &before, -1)
+		addCategoryDeltas(deltas, &ads[i], 1)
+		uc.indexAd(ctx, &ads[i])
+		uc.invalidateAd(ctx, ads[i].ID)
+		uc.publishInvalidation(ctx, ads[i].ID, &ads[i])
+	}
+	uc.adjustCategoryCounts(ctx, deltas)
+	uc.invalidateAdsCache(ctx)
+}
+
+// StatusActivationWorker periodically makes ads active once they have been
+// approved for the configured delay
+type StatusActivationWorker struct {
+	ads      *AdUseCase
+	cache    *redis.Client
+	delay    time.Duration
+	interval time.Duration
+}
+
+func NewStatusActivationWorker(ads *AdUseCase, cache *redis.Client, delay, interval time.Duration) *StatusActivationWorker {
+	return &StatusActivationWorker{
+		ads:      ads,
+		cache:    cache,
+		delay:    delay,
+		interval: interval,
+	}
+}
+
+// Run activates approved ads every interval until ctx is done
+func (w *StatusActivationWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.runOnce(ctx)
+		}
+	}
+}
+
+// runOnce activates the approved ads if no other instance did in this interval.
+// The lock is not released but expires with the interval, so instances take
+// turns; an activation overlapping another one only finds fewer ads to activate.
+func (w *StatusActivationWorker) runOnce(ctx context.Context) {
+	if !takeTurn(ctx, w.cache, activationLockKey, w.interval) {
+		return
+	}
+
+	activated, err := w.ads.ActivateApproved(ctx, w.delay)
+	if err != nil {
+		log.Printf("Warning: ad activation failed: %v", err)
+		return
+	}
+	log.Printf("Activated %d approved ads", activated)
+}
+
+// takeTurn reports whether this instance runs a periodic job, i.e. no other
+// instance took the lock at key within ttl
+func takeTurn(ctx context.Context, cache *redis.Client, key string, ttl time.Duration) bool {
+	acquired, err := cache.SetNX(ctx, key, 1, ttl).Result()
+	if err != nil {
+		log.Printf("Warning: taking lock %s failed: %v", key, err)
+		return false
+	}
+	return acquired
+}
@@ -1,100 +1,916 @@
 package usecase
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"sync"
 	"time"
 
-	"encoding/json"
+	"github.com/1way-market/v3/internal/config"
 	"github.com/1way-market/v3/internal/domain"
+	"github.com/1way-market/v3/internal/secret"
+	"github.com/1way-market/v3/internal/slug"
 	"github.com/go-redis/redis/v8"
 )
 
 type AdRepository interface {
 	FindWithFilter(ctx context.Context, filter domain.FilterRequest) (*domain.PaginatedResponse, error)
+	ExplainFindWithFilter(ctx context.Context, filter domain.FilterRequest) (*domain.QueryPlan, error)
+	Exists(ctx context.Context, filter domain.FilterRequest) (bool, error)
+	FindSimilar(ctx context.Context, ad *domain.Ad, limit int) ([]domain.Ad, error)
+	Export(ctx context.Context, filter domain.FilterRequest, limit int, fn func(*domain.Ad) error) error
+	ListChanges(ctx context.Context, since string, limit int) (*domain.AdChangesPage, error)
 	Create(ctx context.Context, ad *domain.Ad) error
+	CreateBatch(ctx context.Context, ads []*domain.Ad) ([]error, error)
 	Update(ctx context.Context, ad *domain.Ad) error
+	SetStatus(ctx context.Context, id uint, status domain.AdStatus, reason string) error
+	ActivateApproved(ctx context.Context, delay, lifetime time.Duration) ([]domain.Ad, error)
+	ExpireAds(ctx context.Context, limit int) ([]domain.Ad, error)
+	RenewAd(ctx context.Context, id uint, lifetime time.Duration) (*domain.Ad, error)
+	FindDuplicate(ctx context.Context, ad *domain.Ad, window time.Duration, threshold float64) (*uint, error)
+	FindDuplicates(ctx context.Context, id uint) ([]domain.Ad, error)
+	ClearDuplicate(ctx context.Context, id uint) (*domain.Ad, error)
+	RebuildSearchVectors(ctx context.Context, afterID uint, limit int) ([]uint, error)
+	UpdateImages(ctx context.Context, id uint, change func(domain.Images) (domain.Images, error)) (domain.Images, error)
 	Delete(ctx context.Context, id uint) error
+	GetByID(ctx context.Context, id uint) (*domain.Ad, error)
+	FindByIDs(ctx context.Context, ids []uint) ([]domain.Ad, error)
+	CountByStatus(ctx context.Context) (map[domain.AdStatus]int64, error)
+	CountCreatedByDay(ctx context.Context, since time.Time) (map[string]int64, error)
+	Suggest(ctx context.Context, prefix string, lang domain.Language, limit int) ([]domain.Suggestion, error)
 }
 
+// PhoneRevealRepository records who viewed the phone of an ad
+type PhoneRevealRepository interface {
+	Create(ctx context.Context, reveal *domain.PhoneReveal) error
+	CountByAd(ctx context.Context, adID uint) (int64, error)
+}
+
+// AdReportRepository stores the reports of ads flagged by users
+type AdReportRepository interface {
+	Create(ctx context.Context, report *domain.AdReport) error
+	CountByAd(ctx context.Context, adID uint) (int64, error)
+	ListOpen(ctx context.Context, pageSize int, pageToken string) (*domain.AdReportPage, error)
+}
+
+// AdFavoritesRepository reads the favorites of ads: how many users favorited
+// an ad and which ads a user favorited
+type AdFavoritesRepository interface {
+	CountByAd(ctx context.Context, adID uint) (int64, error)
+	FavoritedAmong(ctx context.Context, userID string, adIDs []uint) ([]uint, error)
+}
+
+// PropertyRepository resolves property definitions referenced by ads
+type PropertyRepository interface {
+	NamesByID(ctx context.Context, ids []uint) (map[uint]string, error)
+}
+
+const (
+	// categoryAdCountsKey is the sorted set holding the number of active ads per category
+	categoryAdCountsKey = "category:ad_counts"
+	// idempotencyTTL is how long an idempotency key maps to the ad it created
+	idempotencyTTL = 24 * time.Hour
+	// idempotencyPending marks a key whose create request is still running
+	idempotencyPending = "pending"
+	// similarAdsTTL is how long similar ads are cached per ad
+	similarAdsTTL = 10 * time.Minute
+	// adNotFoundMarker is cached in place of an ad that does not exist
+	adNotFoundMarker = "not_found"
+)
+
 type AdUseCase struct {
-	repo  AdRepository
-	cache *redis.Client
+	repo       AdRepository
+	properties PropertyRepository
+	reveals    PhoneRevealRepository
+	favorites  AdFavoritesRepository
+	reports    AdReportRepository
+	// search answers text searches; nil when they are answered by the database
+	search SearchIndex
+	// blobs stores ad images; nil when no storage is configured
+	blobs      BlobStorage
+	cache      *redis.Client
+	cfg        *config.Config
+	serializer cacheSerializer
+	// phones encrypts ad phones; nil when no key is configured
+	phones *secret.Cipher
+	// flights lets a single request per cache key query the database on a cache miss
+	flights flightGroup
+	// refreshing holds the cache keys being refreshed in the background
+	refreshing sync.Map
 }
 
-func NewAdUseCase(repo AdRepository, cache *redis.Client) *AdUseCase {
+func NewAdUseCase(repo AdRepository, properties PropertyRepository, reveals PhoneRevealRepository, favorites AdFavoritesRepository, reports AdReportRepository, search SearchIndex, blobs BlobStorage, cache *redis.Client, cfg *config.Config) *AdUseCase {
+	codec, err := NewCacheCodec(cfg.CacheCodec)
+	if err != nil {
+		log.Printf("Warning: %v, using json", err)
+		codec = jsonCodec{}
+	}
+
+	var phones *secret.Cipher
+	if len(cfg.PhoneEncryptionKey) > 0 {
+		if phones, err = secret.NewCipher(cfg.PhoneEncryptionKey); err != nil {
+			log.Printf("Warning: invalid phone encryption key, ad phones disabled: %v", err)
+		}
+	}
+
 	return &AdUseCase{
-		repo:  repo,
-		cache: cache,
+		repo:       repo,
+		properties: properties,
+		reveals:    reveals,
+		favorites:  favorites,
+		reports:    reports,
+		search:     search,
+		blobs:      blobs,
+		cache:      cache,
+		cfg:        cfg,
+		serializer: cacheSerializer{codec: codec, gzipMinSize: cfg.CacheGzipMinSize},
+		phones:     phones,
+	}
+}
+
+// GetMyAds lists the caller's ads in every status, including expired ones
+func (uc *AdUseCase) GetMyAds(ctx context.Context, filter domain.FilterRequest) (*domain.PaginatedResponse, error) {
+	principal := domain.PrincipalFromContext(ctx)
+	if principal == nil {
+		return nil, domain.ErrForbidden
 	}
+	filter.OwnerID = principal.UserID
+	filter.IncludeExpired = true
+	return uc.GetAds(ctx, filter)
 }
 
 func (uc *AdUseCase) GetAds(ctx context.Context, filter domain.FilterRequest) (*domain.PaginatedResponse, error) {
-	// Try to get from cache first
-	cacheKey := uc.buildCacheKey(filter)
-	if cachedData, err := uc.cache.Get(ctx, cacheKey).Result(); err == nil {
-		var response domain.PaginatedResponse
-		if err := json.Unmarshal([]byte(cachedData), &response); err == nil {
-			return &response, nil
+	if err := domain.ValidateFilter(&filter); err != nil {
+		return nil, err
+	}
+	if err := normalizeFilter(&filter); err != nil {
+		return nil, err
+	}
+	if err := limitPageSize(&filter, uc.cfg.MaxPageSize); err != nil {
+		return nil, err
+	}
+	applyDefaultVisibility(ctx, &filter, uc.cfg.DefaultVisibleStatuses)
+
+	// Concurrent loads are shared by listing, whatever the cache generation
+	listingKey := uc.buildCacheKey(filter)
+	cacheKey := uc.adsCacheKey(ctx, listingKey)
+	switch cacheMode(ctx) {
+	case domain.CacheNoStore:
+		setCacheStatus(ctx, domain.CacheBypass)
+		return uc.findAds(ctx, filter)
+	case domain.CacheNoCache:
+		setCacheStatus(ctx, domain.CacheBypass)
+		return uc.loadAds(ctx, cacheKey, filter)
+	}
+
+	// Try to get from cache first; stale entries are served while a refresh runs
+	if cachedData, ok := uc.cacheGet(ctx, cacheKey); ok {
+		var entry cachedAds
+		if err := uc.serializer.decode(cachedData, &entry); err == nil && entry.Response != nil {
+			setCacheStatus(ctx, domain.CacheHit)
+			entry.Response.PageSize = filter.PageSize
+			if time.Now().Before(entry.FreshUntil) {
+				return entry.Response, nil
+			}
+			uc.refreshAdsInBackground(listingKey, cacheKey, filter)
+			entry.Response.Stale = true
+			return entry.Response, nil
 		}
 	}
+	setCacheStatus(ctx, domain.CacheMiss)
+
+	// Get from database, sharing one query among concurrent misses of the same key
+	result, err := uc.flights.Do(ctx, listingKey, func(ctx context.Context) (interface{}, error) {
+		return uc.loadAds(ctx, cacheKey, filter)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(*domain.PaginatedResponse), nil
+}
+
+// cachedAds is the cache entry of an ads listing. Redis evicts it after the
+// hard TTL; after FreshUntil (the soft TTL) it is served stale and refreshed.
+type cachedAds struct {
+	FreshUntil time.Time                 `json:"fresh_until"`
+	Response   *domain.PaginatedResponse `json:"response"`
+}
 
-	// Get from database
-	response, err := uc.repo.FindWithFilter(ctx, filter)
+// loadAds queries the ads listing and caches it
+func (uc *AdUseCase) loadAds(ctx context.Context, cacheKey string, filter domain.FilterRequest) (*domain.PaginatedResponse, error) {
+	response, err := uc.findAds(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	// Cache the result
-	if jsonData, err := json.Marshal(response); err == nil {
-		uc.cache.Set(ctx, cacheKey, jsonData, 5*time.Minute)
+	// The listing must not outlive the first of its ads to expire
+	softTTL, hardTTL := uc.cfg.AdsCacheSoftTTL, uc.cfg.AdsCacheHardTTL
+	if until, ok := earliestExpiry(response.Items); ok {
+		remaining := time.Until(until)
+		if remaining <= 0 {
+			return response, nil
+		}
+		softTTL, hardTTL = min(softTTL, remaining), min(hardTTL, remaining)
 	}
 
+	entry := cachedAds{
+		FreshUntil: time.Now().Add(softTTL),
+		Response:   response,
+	}
+	if data, err := uc.serializer.encode(entry); err == nil {
+		uc.cacheSet(ctx, cacheKey, data, hardTTL)
+	}
 	return response, nil
 }
 
+// earliestExpiry returns the first expiry date among the ads, if any expires
+func earliestExpiry(ads []domain.Ad) (time.Time, bool) {
+	var earliest time.Time
+	for _, ad := range ads {
+		if ad.ExpiresAt != nil && (earliest.IsZero() || ad.ExpiresAt.Before(earliest)) {
+			earliest = *ad.ExpiresAt
+		}
+	}
+	return earliest, !earliest.IsZero()
+}
+
+// refreshAdsInBackground reloads a stale listing into cacheKey unless a
+// refresh of the same listing is already running. It runs apart from the
+// request that noticed the stale entry, which returns without waiting.
+func (uc *AdUseCase) refreshAdsInBackground(listingKey, cacheKey string, filter domain.FilterRequest) {
+	if _, running := uc.refreshing.LoadOrStore(listingKey, struct{}{}); running {
+		return
+	}
+
+	go func() {
+		defer uc.refreshing.Delete(listingKey)
+
+		_, err := uc.flights.Do(context.Background(), listingKey, func(ctx context.Context) (interface{}, error) {
+			return uc.loadAds(ctx, cacheKey, filter)
+		})
+		if err != nil {
+			log.Printf("Warning: background refresh of %s failed: %v", cacheKey, err)
+		}
+	}()
+}
+
+// LocalizeAds resolves a page of ads to a single language, including property names
+func (uc *AdUseCase) LocalizeAds(ctx context.Context, response *domain.PaginatedResponse, lang domain.Language) (*domain.LocalizedPaginatedResponse, error) {
+	var ids []uint
+	for _, ad := range response.Items {
+		for _, prop := range ad.Properties {
+			if !slices.Contains(ids, prop.ID) {
+				ids = append(ids, prop.ID)
+			}
+		}
+	}
+
+	names, err := uc.properties.NamesByID(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return response.Localize(lang, names), nil
+}
+
+// ExplainAds returns the query plan of the ads listing GetAds would query for the
+// filter. The cache is not consulted.
+func (uc *AdUseCase) ExplainAds(ctx context.Context, filter domain.FilterRequest) (*domain.QueryPlan, error) {
+	if err := normalizeFilter(&filter); err != nil {
+		return nil, err
+	}
+	if err := limitPageSize(&filter, uc.cfg.MaxPageSize); err != nil {
+		return nil, err
+	}
+	applyDefaultVisibility(ctx, &filter, uc.cfg.DefaultVisibleStatuses)
+	return uc.repo.ExplainFindWithFilter(ctx, filter)
+}
+
+// AdsExist reports whether any ad matches the filter
+func (uc *AdUseCase) AdsExist(ctx context.Context, filter domain.FilterRequest) (bool, error) {
+	if err := normalizeFilter(&filter); err != nil {
+		return false, err
+	}
+	applyDefaultVisibility(ctx, &filter, uc.cfg.DefaultVisibleStatuses)
+	return uc.repo.Exists(ctx, filter)
+}
+
+// GetSimilarAds returns up to limit active ads related to the ad with the given ID
+func (uc *AdUseCase) GetSimilarAds(ctx context.Context, id uint, limit int) ([]domain.Ad, error) {
+	cacheKey := uc.adsCacheKey(ctx, fmt.Sprintf("similar:%d:%d", id, limit))
+	if cachedData, ok := uc.cacheGet(ctx, cacheKey); ok {
+		var ads []domain.Ad
+		if err := uc.serializer.decode(cachedData, &ads); err == nil {
+			return ads, nil
+		}
+	}
+
+	ad, err := uc.GetAd(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ads, err := uc.repo.FindSimilar(ctx, ad, limit)
+	if err != nil {
+		return nil, err
+	}
+	if ads == nil {
+		ads = []domain.Ad{}
+	}
+
+	if data, err := uc.serializer.encode(ads); err == nil {
+		uc.cacheSet(ctx, cacheKey, data, similarAdsTTL)
+	}
+
+	return ads, nil
+}
+
+// GetAdChanges returns up to limit ads changed after the since cursor, for
+// systems mirroring the ads. It reads the database, never the cache.
+func (uc *AdUseCase) GetAdChanges(ctx context.Context, since string, limit int) (*domain.AdChangesPage, error) {
+	return uc.repo.ListChanges(ctx, since, limit)
+}
+
+// ExportAds passes every ad matching the filter to fn, up to the configured export limit
+func (uc *AdUseCase) ExportAds(ctx context.Context, filter domain.FilterRequest, fn func(*domain.Ad) error) error {
+	if err := normalizeFilter(&filter); err != nil {
+		return err
+	}
+	return uc.repo.Export(ctx, filter, uc.cfg.ExportMaxRows, fn)
+}
+
+// normalizeFilter canonicalizes filter values so equivalent requests share queries and cache entries
+func normalizeFilter(filter *domain.FilterRequest) error {
+	if err := filter.ValidateSort(); err != nil {
+		return err
+	}
+	// "USD" and "840" refer to the same currency
+	if filter.Currency != "" {
+		currency, err := domain.ParseCurrency(filter.Currency)
+		if err != nil {
+			return err
+		}
+		filter.Currency = string(currency)
+	}
+	return nil
+}
+
+// limitPageSize resolves the page size of a listing: unset means the default, and
+// sizes above max are lowered to it. Sizes far above max are rejected outright.
+func limitPageSize(filter *domain.FilterRequest, max int) error {
+	if filter.PageSize < 0 || filter.PageSize > max*10 {
+		return &domain.PageSizeError{Max: max}
+	}
+	if filter.PageSize == 0 {
+		filter.PageSize = domain.DefaultPageSize
+	}
+	filter.PageSize = min(filter.PageSize, max)
+	return nil
+}
+
+// applyDefaultVisibility limits listings to the statuses configured as publicly
+// visible, whatever status they filter on. Moderators and admins see every
+// status, and so do owners listing their own ads.
+func applyDefaultVisibility(ctx context.Context, filter *domain.FilterRequest, visible []domain.AdStatus) {
+	if filter.AnyStatus {
+		return
+	}
+	if principal := domain.PrincipalFromContext(ctx); principal != nil {
+		if principal.HasAnyRole(domain.RoleModerator, domain.RoleAdmin) {
+			return
+		}
+		if filter.OwnerID != "" && filter.OwnerID == principal.UserID {
+			return
+		}
+	}
+	filter.Statuses = visible
+}
+
+// adVisible reports whether the caller may see the ad. As in listings, ads in
+// a publicly visible status are shown to everyone and the others only to
+// their owner, moderators and admins.
+func adVisible(ctx context.Context, ad *domain.Ad, visible []domain.AdStatus) bool {
+	if len(visible) == 0 || slices.Contains(visible, ad.Status) {
+		return true
+	}
+	return authorizeOwner(ctx, ad) == nil
+}
+
+// buildCacheKey derives a deterministic key from every filter that affects the
+// result; adsCacheKey places it in the current cache generation
 func (uc *AdUseCase) buildCacheKey(filter domain.FilterRequest) string {
-	key := fmt.Sprintf("ads:filter:%v:%v:%v:%v:%v",
+	key := fmt.Sprintf("filter:%v:%v:%q:%v:%v:%v:%v:%v:%v:%v:%v:%v:%v:%v:%v:%v:%v",
+		filter.Language,
+		formatOptional(filter.SellerID),
+		filter.OwnerID,
 		filter.CategoryIDs,
 		filter.TextSearch,
 		filter.SortBy,
 		filter.PageToken,
 		filter.PageSize,
+		filter.Currency,
+		formatOptional(filter.MinPrice),
+		formatOptional(filter.MaxPrice),
+		filter.ExcludePriceOnRequest,
+		filter.IncludeExpired,
+		filter.IncludeImages,
+		formatOptional(filter.Status),
+		filter.Statuses,
+		filter.SelectedFields,
 	)
 
-	for _, prop := range filter.PropertyFilters {
-		key += fmt.Sprintf(":%v=%v", prop.PropertyID, prop.Values)
+	// Sort property filters and their values so equivalent queries share a key
+	props := slices.Clone(filter.PropertyFilters)
+	slices.SortFunc(props, func(a, b domain.PropertyFilter) int {
+		return cmp.Compare(a.PropertyID, b.PropertyID)
+	})
+	for _, prop := range props {
+		values := slices.Clone(prop.Values)
+		slices.Sort(values)
+		valueIDs := slices.Clone(prop.ValueIDs)
+		slices.Sort(valueIDs)
+		key += fmt.Sprintf(":%v=%q#%v", prop.PropertyID, values, valueIDs)
 	}
 
 	return key
 }
 
+// formatOptional renders an optional filter value, using an empty string when unset
+func formatOptional[T any](value *T) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(*value)
+}
+
 func (uc *AdUseCase) CreateAd(ctx context.Context, ad *domain.Ad) error {
+	if err := uc.prepareNewAd(ctx, ad); err != nil {
+		return err
+	}
 	if err := uc.repo.Create(ctx, ad); err != nil {
 		return err
 	}
 
-	// Invalidate relevant cache entries
-	uc.cache.Del(ctx, "ads:*")
+	deltas := make(map[int]int64)
+	addCategoryDeltas(deltas, ad, 1)
+	uc.adjustCategoryCounts(ctx, deltas)
+
+	uc.syncSearchIndex(ctx, ad.ID)
+
+	// Invalidate relevant cache entries and pre-warm the ad itself,
+	// replacing a cached not-found marker for its ID
+	uc.invalidateAdsCache(ctx)
+	uc.cacheAd(ctx, ad)
+	uc.publishInvalidation(ctx, ad.ID, ad)
 	return nil
 }
 
+// prepareNewAd validates an ad about to be created and fills in what the
+// caller does not choose: its owner, seller, sealed phone, expiry date and,
+// for parser imports, whether it duplicates a recent ad
+func (uc *AdUseCase) prepareNewAd(ctx context.Context, ad *domain.Ad) error {
+	if err := domain.ValidateAd(ad); err != nil {
+		return err
+	}
+	if err := uc.validatePrice(ad); err != nil {
+		return err
+	}
+	if err := uc.sealPhone(ad); err != nil {
+		return err
+	}
+
+	if err := authorizeStatus(ctx, nil, ad); err != nil {
+		return err
+	}
+	if ad.Status == domain.StatusActive {
+		uc.startLifetime(ad)
+	}
+
+	// Ads posted by a seller belong to them regardless of the request body,
+	// and every ad to the user who posted it
+	ad.OwnerID = ""
+	if principal := domain.PrincipalFromContext(ctx); principal != nil {
+		ad.OwnerID = principal.UserID
+		if principal.SellerID != nil {
+			sellerID := *principal.SellerID
+			ad.SellerID = &sellerID
+		}
+	}
+
+	uc.markDuplicate(ctx, ad)
+	return nil
+}
+
+// CreateAdIdempotent creates the ad at most once per idempotency key.
+// A repeated key returns the originally created ad and replayed=true. When the
+// key cannot be checked, the ad is not created and
+// domain.ErrIdempotencyUnavailable is returned: creating it anyway could
+// duplicate an ad the client is retrying.
+func (uc *AdUseCase) CreateAdIdempotent(ctx context.Context, key string, ad *domain.Ad) (created *domain.Ad, replayed bool, err error) {
+	cacheKey := "idempotency:ads:" + key
+	if principal := domain.PrincipalFromContext(ctx); principal != nil {
+		cacheKey = "idempotency:ads:" + principal.UserID + ":" + key
+	}
+
+	redisCtx, cancel := uc.cacheContext(ctx)
+	acquired, err := uc.cache.SetNX(redisCtx, cacheKey, idempotencyPending, idempotencyTTL).Result()
+	cancel()
+	if err != nil {
+		log.Printf("Warning: idempotency key %s not acquired: %v", cacheKey, err)
+		return nil, false, domain.ErrIdempotencyUnavailable
+	}
+
+	if !acquired {
+		redisCtx, cancel := uc.cacheContext(ctx)
+		value, err := uc.cache.Get(redisCtx, cacheKey).Result()
+		cancel()
+		if err != nil {
+			log.Printf("Warning: idempotency key %s not read: %v", cacheKey, err)
+			return nil, false, domain.ErrIdempotencyUnavailable
+		}
+		if value == idempotencyPending {
+			return nil, false, domain.ErrRequestInProgress
+		}
+
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid idempotency record: %v", err)
+		}
+		existing, err := uc.repo.GetByID(ctx, uint(id))
+		if err != nil {
+			return nil, false, err
+		}
+		return existing, true, nil
+	}
+
+	if err := uc.CreateAd(ctx, ad); err != nil {
+		// Release the key so the client can retry
+		redisCtx, cancel := uc.cacheContext(ctx)
+		uc.cache.Del(redisCtx, cacheKey)
+		cancel()
+		return nil, false, err
+	}
+
+	uc.cacheSet(ctx, cacheKey, strconv.FormatUint(uint64(ad.ID), 10), idempotencyTTL)
+	return ad, false, nil
+}
+
+// UpdateAd saves the ad, which must carry the version it was read at; an ad
+// updated since is rejected with domain.ErrConflict
 func (uc *AdUseCase) UpdateAd(ctx context.Context, ad *domain.Ad) error {
+	if err := domain.ValidateAd(ad); err != nil {
+		return err
+	}
+	if ad.Version <= 0 {
+		return &domain.ValidationError{Fields: []domain.FieldError{{Field: "version", Message: "is required"}}}
+	}
+	if err := uc.validatePrice(ad); err != nil {
+		return err
+	}
+	if err := uc.sealPhone(ad); err != nil {
+		return err
+	}
+
+	existing, err := uc.repo.GetByID(ctx, ad.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeOwner(ctx, existing); err != nil {
+		return err
+	}
+	if err := authorizeStatus(ctx, existing, ad); err != nil {
+		return err
+	}
+	ad.OwnerID = existing.OwnerID
+	ad.DuplicateOf = existing.DuplicateOf
+	// An update without an expiry date keeps the ad's current one
+	if ad.ExpiresAt == nil {
+		ad.ExpiresAt = existing.ExpiresAt
+	}
+	if ad.Status == domain.StatusActive && existing.Status != domain.StatusActive {
+		uc.startLifetime(ad)
+	}
+
+	// The status reason explains the current status, so it is kept along with the status
+	if ad.Status == existing.Status && ad.StatusReason == "" {
+		ad.StatusReason = existing.StatusReason
+	}
+
+	// Clients never see the phone, so an update without one keeps it
+	if ad.PhoneEncrypted == "" {
+		ad.PhoneEncrypted = existing.PhoneEncrypted
+		ad.PhoneMasked = existing.PhoneMasked
+	}
+
 	if err := uc.repo.Update(ctx, ad); err != nil {
 		return err
 	}
 
+	deltas := make(map[int]int64)
+	addCategoryDeltas(deltas, existing, -1)
+	addCategoryDeltas(deltas, ad, 1)
+	uc.adjustCategoryCounts(ctx, deltas)
+	uc.syncSearchIndex(ctx, ad.ID)
+
 	// Invalidate relevant cache entries
-	uc.cache.Del(ctx, "ads:*")
+	uc.invalidateAdsCache(ctx)
+	uc.invalidateAd(ctx, ad.ID)
+	uc.publishInvalidation(ctx, ad.ID, existing, ad)
 	return nil
 }
 
 func (uc *AdUseCase) DeleteAd(ctx context.Context, id uint) error {
+	existing, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := authorizeOwner(ctx, existing); err != nil {
+		return err
+	}
+
 	if err := uc.repo.Delete(ctx, id); err != nil {
 		return err
 	}
 
+	deltas := make(map[int]int64)
+	addCategoryDeltas(deltas, existing, -1)
+	uc.adjustCategoryCounts(ctx, deltas)
+	uc.deleteImageObjects(ctx, id, existing.Images)
+	uc.syncSearchIndex(ctx, id)
+
 	// Invalidate relevant cache entries
-	uc.cache.Del(ctx, "ads:*")
+	uc.invalidateAdsCache(ctx)
+	uc.invalidateAd(ctx, id)
+	uc.publishInvalidation(ctx, id, existing)
+	return nil
+}
+
+// GetAd returns the ad with the given ID or domain.ErrNotFound. Ads outside
+// the publicly visible statuses are only returned to their owner, moderators
+// and admins; to anyone else they do not exist.
+func (uc *AdUseCase) GetAd(ctx context.Context, id uint) (*domain.Ad, error) {
+	ad, err := uc.getAd(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !adVisible(ctx, ad, uc.cfg.DefaultVisibleStatuses) {
+		return nil, domain.ErrNotFound
+	}
+	return ad, nil
+}
+
+// getAd returns the ad with the given ID or domain.ErrNotFound, whoever asks.
+// Ads are cached individually under ad:{id}; missing IDs are cached briefly too.
+func (uc *AdUseCase) getAd(ctx context.Context, id uint) (*domain.Ad, error) {
+	switch cacheMode(ctx) {
+	case domain.CacheNoStore:
+		setCacheStatus(ctx, domain.CacheBypass)
+		return uc.loadAd(ctx, id, false)
+	case domain.CacheNoCache:
+		setCacheStatus(ctx, domain.CacheBypass)
+		return uc.loadAd(ctx, id, true)
+	}
+
+	if cachedData, ok := uc.cacheGet(ctx, adCacheKey(id)); ok {
+		if string(cachedData) == adNotFoundMarker {
+			setCacheStatus(ctx, domain.CacheHit)
+			return nil, domain.ErrNotFound
+		}
+		var ad domain.Ad
+		if err := uc.serializer.decode(cachedData, &ad); err == nil {
+			setCacheStatus(ctx, domain.CacheHit)
+			return &ad, nil
+		}
+	}
+	setCacheStatus(ctx, domain.CacheMiss)
+
+	return uc.loadAd(ctx, id, true)
+}
+
+// loadAd reads an ad from the database, caching the result when store is set
+func (uc *AdUseCase) loadAd(ctx context.Context, id uint, store bool) (*domain.Ad, error) {
+	ad, err := uc.repo.GetByID(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) && store {
+		uc.cacheSet(ctx, adCacheKey(id), adNotFoundMarker, uc.cfg.AdNotFoundCacheTTL)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if store {
+		uc.cacheAd(ctx, ad)
+	}
+	return ad, nil
+}
+
+// GetAdBySlug returns the ad with the given slug or domain.ErrNotFound.
+// The slug ends with the ad ID, so the ad is read through the ad cache.
+func (uc *AdUseCase) GetAdBySlug(ctx context.Context, adSlug string) (*domain.Ad, error) {
+	id, ok := slug.ID(adSlug)
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+
+	ad, err := uc.GetAd(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ad.Slug != adSlug {
+		return nil, domain.ErrNotFound
+	}
+	return ad, nil
+}
+
+// GetAdRawSource returns the parser payload of the ad with the given ID.
+// It bypasses the ad cache since cached ads do not include the payload.
+func (uc *AdUseCase) GetAdRawSource(ctx context.Context, id uint) (domain.RawSource, error) {
+	ad, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return ad.RawSource, nil
+}
+
+// adCacheKey is the cache key of a single ad
+func adCacheKey(id uint) string {
+	return fmt.Sprintf("ad:%d", id)
+}
+
+// cacheAd stores a single ad in the cache
+func (uc *AdUseCase) cacheAd(ctx context.Context, ad *domain.Ad) {
+	if data, err := uc.serializer.encode(ad); err == nil {
+		uc.cacheSet(ctx, adCacheKey(ad.ID), data, uc.cfg.AdCacheTTL)
+	}
+}
+
+// invalidateAd drops a single cached ad, logging failures
+func (uc *AdUseCase) invalidateAd(ctx context.Context, id uint) {
+	ctx, cancel := uc.cacheContext(ctx)
+	defer cancel()
+
+	if err := uc.cache.Del(ctx, adCacheKey(id)).Err(); err != nil {
+		log.Printf("Warning: cache invalidation of ad %d failed: %v", id, err)
+	}
+}
+
+// validatePrice normalizes the price currency to the numeric ISO 4217 code
+// and checks it is allowed in every category of the ad
+func (uc *AdUseCase) validatePrice(ad *domain.Ad) error {
+	if ad.Price == nil || ad.Price.Currency == "" {
+		return nil
+	}
+
+	currency, err := domain.ParseCurrency(ad.Price.Currency)
+	if err != nil {
+		return err
+	}
+	ad.Price.Currency = string(currency)
+
+	for _, categoryID := range ad.CategoryIDs {
+		allowed, restricted := uc.cfg.CategoryCurrencies[categoryID]
+		if restricted && !slices.Contains(allowed, currency) {
+			return fmt.Errorf("%w: %s in category %d", domain.ErrCurrencyNotAllowed, currency.Alpha(), categoryID)
+		}
+	}
+	return nil
+}
+
+// authorStatuses are the statuses users other than moderators may give an ad;
+// every other status is set by moderation or by the system
+var authorStatuses = []domain.AdStatus{domain.StatusDraft, domain.StatusPending}
+
+// authorizeStatus checks the caller may move the ad from the existing status,
+// nil for a new ad, to its status. Moderators may set any status. Other users
+// may only create ads as drafts or pending moderation, parsers also straight
+// from the parser, and only move an ad between those statuses: leaving a
+// status set by moderation takes a moderator.
+func authorizeStatus(ctx context.Context, existing *domain.Ad, ad *domain.Ad) error {
+	principal := domain.PrincipalFromContext(ctx)
+	if principal != nil && principal.HasAnyRole(domain.RoleModerator) {
+		return nil
+	}
+
+	if existing == nil {
+		if slices.Contains(authorStatuses, ad.Status) ||
+			(ad.Status == domain.StatusFromParser && principal != nil && principal.HasAnyRole(domain.RoleParser)) {
+			return nil
+		}
+		return fmt.Errorf("%w: only moderators may create ads with status %s", domain.ErrForbidden, ad.Status)
+	}
+
+	if existing.Status == ad.Status {
+		return nil
+	}
+	if !slices.Contains(authorStatuses, existing.Status) || !slices.Contains(authorStatuses, ad.Status) {
+		return fmt.Errorf("%w: only moderators may change status %s to %s", domain.ErrForbidden, existing.Status, ad.Status)
+	}
 	return nil
 }
+
+// authorizeOwner checks the caller may change the existing ad: moderators and
+// admins may change any ad, other users only the ads they posted
+func authorizeOwner(ctx context.Context, existing *domain.Ad) error {
+	principal := domain.PrincipalFromContext(ctx)
+	if principal != nil && principal.HasAnyRole(domain.RoleModerator, domain.RoleAdmin) {
+		return nil
+	}
+	if principal == nil || existing.OwnerID == "" || existing.OwnerID != principal.UserID {
+		return fmt.Errorf("%w: ad %d belongs to another user", domain.ErrForbidden, existing.ID)
+	}
+	return nil
+}
+
+// addCategoryDeltas records a count change for every category of an active ad
+func addCategoryDeltas(deltas map[int]int64, ad *domain.Ad, delta int64) {
+	if ad.Status != domain.StatusActive {
+		return
+	}
+	for _, categoryID := range ad.CategoryIDs {
+		deltas[categoryID] += delta
+	}
+}
+
+// adjustCategoryCounts applies count changes to the category sorted set
+func (uc *AdUseCase) adjustCategoryCounts(ctx context.Context, deltas map[int]int64) {
+	pipe := uc.cache.Pipeline()
+	for categoryID, delta := range deltas {
+		if delta != 0 {
+			pipe.ZIncrBy(ctx, categoryAdCountsKey, float64(delta), strconv.Itoa(categoryID))
+		}
+	}
+	redisCtx, cancel := uc.cacheContext(ctx)
+	defer cancel()
+	if _, err := pipe.Exec(redisCtx); err != nil {
+		log.Printf("Warning: failed to update category counts: %v", err)
+	}
+}
+
+// cacheMode returns the cache mode requested for ctx
+func cacheMode(ctx context.Context) domain.CacheMode {
+	if control := domain.CacheControlFromContext(ctx); control != nil {
+		return control.Mode
+	}
+	return domain.CacheDefault
+}
+
+// setCacheStatus reports to the caller how the cache served the request
+func setCacheStatus(ctx context.Context, status string) {
+	if control := domain.CacheControlFromContext(ctx); control != nil {
+		control.Status = status
+	}
+}
+
+// cacheContext bounds a Redis call so that a slow Redis cannot stall the request
+func (uc *AdUseCase) cacheContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, uc.cfg.RedisCacheTimeout)
+}
+
+// cacheGet reads a cached value. Redis errors and timeouts are logged and
+// reported as a miss so the caller falls through to the database, as is an
+// empty key.
+func (uc *AdUseCase) cacheGet(ctx context.Context, key string) ([]byte, bool) {
+	if key == "" {
+		return nil, false
+	}
+	ctx, cancel := uc.cacheContext(ctx)
+	defer cancel()
+
+	data, err := uc.cache.Get(ctx, key).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			log.Printf("Warning: cache read of %s failed: %v", key, err)
+		}
+		return nil, false
+	}
+	return data, true
+}
+
+// cacheSet writes a cached value, logging failures. An empty key is skipped.
+func (uc *AdUseCase) cacheSet(ctx context.Context, key string, value interface{}, ttl time.Duration) {
+	if key == "" {
+		return
+	}
+	ctx, cancel := uc.cacheContext(ctx)
+	defer cancel()
+
+	if err := uc.cache.Set(ctx, key, value, ttl).Err(); err != nil {
+		log.Printf("Warning: cache write of %s failed: %v", key, err)
+	}
+}
+
+// invalidateAdsCache drops every cached ad listing and aggregate by starting
+// a new cache generation, logging failures
+func (uc *AdUseCase) invalidateAdsCache(ctx context.Context) {
+	ctx, cancel := uc.cacheContext(ctx)
+	defer cancel()
+
+	if err := uc.cache.Incr(ctx, adsCacheVersionKey).Err(); err != nil {
+		log.Printf("Warning: ads cache invalidation failed: %v", err)
+	}
+}
@@ -0,0 +1,382 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/1way-market/v3/internal/domain"
+)
+
+// asUser returns a context authenticated as user-1 with the given roles
+func asUser(roles ...string) context.Context {
+	return domain.WithPrincipal(context.Background(), &domain.Principal{UserID: "user-1", Roles: roles})
+}
+
+func TestAuthorizeStatusCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		status  domain.AdStatus
+		allowed bool
+	}{
+		{"seller draft", asUser(domain.RoleSeller), domain.StatusDraft, true},
+		{"seller pending", asUser(domain.RoleSeller), domain.StatusPending, true},
+		{"seller active", asUser(domain.RoleSeller), domain.StatusActive, false},
+		{"seller approved", asUser(domain.RoleSeller), domain.StatusApproved, false},
+		{"seller from parser", asUser(domain.RoleSeller), domain.StatusFromParser, false},
+		{"seller duplicate", asUser(domain.RoleSeller), domain.StatusDuplicate, false},
+		{"parser from parser", asUser(domain.RoleParser), domain.StatusFromParser, true},
+		{"parser active", asUser(domain.RoleParser), domain.StatusActive, false},
+		{"admin active", asUser(domain.RoleAdmin), domain.StatusActive, false},
+		{"anonymous pending", context.Background(), domain.StatusPending, true},
+		{"anonymous active", context.Background(), domain.StatusActive, false},
+		{"moderator active", asUser(domain.RoleModerator), domain.StatusActive, true},
+		{"moderator rejected", asUser(domain.RoleModerator), domain.StatusRejected, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorizeStatus(tt.ctx, nil, &domain.Ad{Status: tt.status})
+			checkAuthorized(t, err, tt.allowed)
+		})
+	}
+}
+
+func TestAuthorizeStatusUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		from, to domain.AdStatus
+		allowed  bool
+	}{
+		{"draft to pending", asUser(domain.RoleSeller), domain.StatusDraft, domain.StatusPending, true},
+		{"pending to draft", asUser(domain.RoleSeller), domain.StatusPending, domain.StatusDraft, true},
+		{"unchanged active", asUser(domain.RoleSeller), domain.StatusActive, domain.StatusActive, true},
+		{"unchanged rejected", asUser(domain.RoleSeller), domain.StatusRejected, domain.StatusRejected, true},
+		{"pending to approved", asUser(domain.RoleSeller), domain.StatusPending, domain.StatusApproved, false},
+		{"pending to active", asUser(domain.RoleSeller), domain.StatusPending, domain.StatusActive, false},
+		{"rejected to pending", asUser(domain.RoleSeller), domain.StatusRejected, domain.StatusPending, false},
+		{"duplicate to pending", asUser(domain.RoleParser), domain.StatusDuplicate, domain.StatusPending, false},
+		{"approved to draft", asUser(domain.RoleSeller), domain.StatusApproved, domain.StatusDraft, false},
+		{"active to completed", asUser(domain.RoleSeller), domain.StatusActive, domain.StatusCompleted, false},
+		{"admin rejected to pending", asUser(domain.RoleAdmin), domain.StatusRejected, domain.StatusPending, false},
+		{"moderator rejected to pending", asUser(domain.RoleModerator), domain.StatusRejected, domain.StatusPending, true},
+		{"moderator pending to approved", asUser(domain.RoleModerator), domain.StatusPending, domain.StatusApproved, true},
+		{"moderator duplicate to active", asUser(domain.RoleModerator), domain.StatusDuplicate, domain.StatusActive, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorizeStatus(tt.ctx, &domain.Ad{Status: tt.from}, &domain.Ad{Status: tt.to})
+			checkAuthorized(t, err, tt.allowed)
+		})
+	}
+}
+
+func checkAuthorized(t *testing.T, err error, allowed bool) {
+	t.Helper()
+	if allowed && err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !allowed && !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("error = %v, want %v", err, domain.ErrForbidden)
+	}
+}
+
+func TestApplyDefaultVisibility(t *testing.T) {
+	visible := []domain.AdStatus{domain.StatusActive, domain.StatusApproved}
+	draft := domain.StatusDraft
+	ownerCtx := domain.WithPrincipal(context.Background(), &domain.Principal{UserID: "user-1"})
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		filter domain.FilterRequest
+		want   []domain.AdStatus
+	}{
+		{"anonymous", context.Background(), domain.FilterRequest{}, visible},
+		{"anonymous status filter", context.Background(), domain.FilterRequest{Status: &draft}, visible},
+		{"user", ownerCtx, domain.FilterRequest{}, visible},
+		{"user status filter", ownerCtx, domain.FilterRequest{Status: &draft}, visible},
+		{"seller", asUser(domain.RoleSeller), domain.FilterRequest{}, visible},
+		{"other owner", ownerCtx, domain.FilterRequest{OwnerID: "user-2"}, visible},
+		{"own ads", ownerCtx, domain.FilterRequest{OwnerID: "user-1", Status: &draft}, nil},
+		{"moderator", asUser(domain.RoleModerator), domain.FilterRequest{Status: &draft}, nil},
+		{"admin", asUser(domain.RoleAdmin), domain.FilterRequest{}, nil},
+		{"admin listing", context.Background(), domain.FilterRequest{AnyStatus: true}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+			applyDefaultVisibility(tt.ctx, &filter, visible)
+			if !slices.Equal(filter.Statuses, tt.want) {
+				t.Errorf("statuses = %v, want %v", filter.Statuses, tt.want)
+			}
+			if !equalStatus(filter.Status, tt.filter.Status) {
+				t.Errorf("status filter changed to %v", filter.Status)
+			}
+		})
+	}
+}
+
+func equalStatus(a, b *domain.AdStatus) bool {
+	return a == b || (a != nil && b != nil && *a == *b)
+}
+
+// newSellerAd returns a valid draft ad
+func newSellerAd() *domain.Ad {
+	return &domain.Ad{
+		Title:       domain.MultiLangArray{{Lang: domain.LangEnglish, Text: "Red bicycle"}},
+		CategoryIDs: []int{1},
+	}
+}
+
+func TestUpdateAdStatusRoles(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		from, to domain.AdStatus
+		wantErr  error
+	}{
+		{"owner submits", asUser(domain.RoleSeller), domain.StatusDraft, domain.StatusPending, nil},
+		{"owner approves", asUser(domain.RoleSeller), domain.StatusPending, domain.StatusApproved, domain.ErrForbidden},
+		{"owner rejects", asUser(domain.RoleSeller), domain.StatusPending, domain.StatusRejected, domain.ErrForbidden},
+		{"owner resubmits rejected", asUser(domain.RoleSeller), domain.StatusRejected, domain.StatusPending, domain.ErrForbidden},
+		{"parser approves", asUser(domain.RoleParser), domain.StatusPending, domain.StatusApproved, domain.ErrForbidden},
+		{"moderator approves", asUser(domain.RoleModerator), domain.StatusPending, domain.StatusApproved, nil},
+		{"moderator rejects", asUser(domain.RoleModerator), domain.StatusPending, domain.StatusRejected, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeAdRepo(domain.Ad{ID: 1, OwnerID: "user-1", Status: tt.from, Version: 1})
+			uc := newTestAdUseCase(t, repo, testConfig())
+
+			ad := newSellerAd()
+			ad.ID, ad.Status, ad.Version = 1, tt.to, 1
+			err := uc.UpdateAd(tt.ctx, ad)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+
+			want := tt.to
+			if tt.wantErr != nil {
+				want = tt.from
+			}
+			if stored, _ := repo.GetByID(context.Background(), 1); stored.Status != want {
+				t.Errorf("stored status = %v, want %v", stored.Status, want)
+			}
+		})
+	}
+}
+
+func TestCreateAdRecordsOwner(t *testing.T) {
+	repo := newFakeAdRepo()
+	uc := newTestAdUseCase(t, repo, testConfig())
+
+	ad := newSellerAd()
+	ad.OwnerID = "user-2"
+	if err := uc.CreateAd(asUser(domain.RoleSeller), ad); err != nil {
+		t.Fatal(err)
+	}
+	if stored, _ := repo.GetByID(context.Background(), ad.ID); stored.OwnerID != "user-1" {
+		t.Errorf("owner = %q, want the caller user-1", stored.OwnerID)
+	}
+}
+
+func TestChangesRestrictedToOwner(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr error
+	}{
+		{"owner", asUser(domain.RoleSeller), nil},
+		{"other user", domain.WithPrincipal(context.Background(), &domain.Principal{UserID: "user-2"}), domain.ErrForbidden},
+		{"anonymous", context.Background(), domain.ErrForbidden},
+		{"moderator", domain.WithPrincipal(context.Background(), &domain.Principal{UserID: "user-2", Roles: []string{domain.RoleModerator}}), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeAdRepo(domain.Ad{ID: 1, OwnerID: "user-1", Status: domain.StatusDraft, Version: 1})
+			uc := newTestAdUseCase(t, repo, testConfig())
+
+			ad := newSellerAd()
+			ad.ID, ad.Status, ad.Version = 1, domain.StatusDraft, 1
+			if err := uc.UpdateAd(tt.ctx, ad); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("update error = %v, want %v", err, tt.wantErr)
+			}
+			if stored, _ := repo.GetByID(context.Background(), 1); stored.OwnerID != "user-1" {
+				t.Errorf("owner after update = %q, want user-1", stored.OwnerID)
+			}
+
+			if err := uc.DeleteAd(tt.ctx, 1); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("delete error = %v, want %v", err, tt.wantErr)
+			}
+			if _, err := repo.GetByID(context.Background(), 1); (err == nil) != (tt.wantErr != nil) {
+				t.Errorf("ad kept = %v after delete returning %v", err == nil, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetAdStatsRestrictedToOwner(t *testing.T) {
+	sellerID := uint(7)
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr error
+	}{
+		{"owner", asUser(), nil},
+		{"moderator", domain.WithPrincipal(context.Background(), &domain.Principal{UserID: "user-2", Roles: []string{domain.RoleModerator}}), nil},
+		{"admin", domain.WithPrincipal(context.Background(), &domain.Principal{UserID: "user-2", Roles: []string{domain.RoleAdmin}}), nil},
+		{"other user of the seller", domain.WithPrincipal(context.Background(), &domain.Principal{UserID: "user-2", SellerID: &sellerID}), domain.ErrForbidden},
+		{"anonymous", context.Background(), domain.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeAdRepo(domain.Ad{ID: 1, OwnerID: "user-1", SellerID: &sellerID, Status: domain.StatusActive})
+			uc, server := newTestAdUseCaseWithCache(t, repo, testConfig())
+			server.Set(phoneRevealsKey(1), "3")
+
+			stats, err := uc.GetAdStats(tt.ctx, 1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && stats.PhoneRevealCount != 3 {
+				t.Errorf("phone reveals = %d, want 3", stats.PhoneRevealCount)
+			}
+		})
+	}
+}
+
+func TestGetAdVisibility(t *testing.T) {
+	otherUser := domain.WithPrincipal(context.Background(), &domain.Principal{UserID: "user-2"})
+	moderator := domain.WithPrincipal(context.Background(), &domain.Principal{UserID: "user-2", Roles: []string{domain.RoleModerator}})
+	tests := []struct {
+		name    string
+		status  domain.AdStatus
+		ctx     context.Context
+		wantErr error
+	}{
+		{"active ad to anonymous", domain.StatusActive, context.Background(), nil},
+		{"active ad to other user", domain.StatusActive, otherUser, nil},
+		{"draft to anonymous", domain.StatusDraft, context.Background(), domain.ErrNotFound},
+		{"draft to other user", domain.StatusDraft, otherUser, domain.ErrNotFound},
+		{"draft to owner", domain.StatusDraft, asUser(), nil},
+		{"draft to moderator", domain.StatusDraft, moderator, nil},
+		{"draft to admin", domain.StatusDraft, asUser(domain.RoleAdmin), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeAdRepo(domain.Ad{ID: 1, OwnerID: "user-1", Status: tt.status, Version: 1})
+			uc, _ := newTestAdUseCaseWithCache(t, repo, testConfig())
+
+			// the second read is served from the ad cache
+			for i := 0; i < 2; i++ {
+				if _, err := uc.GetAd(tt.ctx, 1); !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestCacheKeyIncludesOwner(t *testing.T) {
+	uc := newTestAdUseCase(t, newFakeAdRepo(), testConfig())
+	mine := uc.buildCacheKey(domain.FilterRequest{OwnerID: "user-1"})
+	theirs := uc.buildCacheKey(domain.FilterRequest{OwnerID: "user-2"})
+	if mine == theirs {
+		t.Errorf("listings of different owners share the cache key %s", mine)
+	}
+}
+
+func TestInvalidationKeepsEventStream(t *testing.T) {
+	repo := newFakeAdRepo(domain.Ad{ID: 1, OwnerID: "user-1", Status: domain.StatusDraft, Version: 1})
+	uc, server := newTestAdUseCaseWithCache(t, repo, testConfig())
+	if _, err := server.XAdd("ads:events", "*", []string{"type", domain.AdEventCreated}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := uc.GetAds(context.Background(), domain.FilterRequest{Lang: "en"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := uc.DeleteAd(asUser(domain.RoleSeller), 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.GetAds(context.Background(), domain.FilterRequest{Lang: "en"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if finds := repo.finds.Load(); finds != 2 {
+		t.Errorf("listing read %d times from the database, want it cached once and reloaded after the change", finds)
+	}
+	if !server.Exists("ads:events") {
+		t.Error("event stream deleted with the cache")
+	}
+}
+
+func TestCreateAdIdempotentReplays(t *testing.T) {
+	repo := newFakeAdRepo()
+	uc := newTestAdUseCase(t, repo, testConfig())
+	ctx := asUser(domain.RoleSeller)
+
+	first, replayed, err := uc.CreateAdIdempotent(ctx, "key-1", newSellerAd())
+	if err != nil || replayed {
+		t.Fatalf("first request: replayed = %v, error = %v", replayed, err)
+	}
+	again, replayed, err := uc.CreateAdIdempotent(ctx, "key-1", newSellerAd())
+	if err != nil || !replayed {
+		t.Fatalf("retry: replayed = %v, error = %v", replayed, err)
+	}
+	if again.ID != first.ID {
+		t.Errorf("retry returned ad %d, want %d", again.ID, first.ID)
+	}
+	if creates := repo.creates.Load(); creates != 1 {
+		t.Errorf("created %d ads, want 1", creates)
+	}
+}
+
+func TestCreateAdIdempotentCacheDown(t *testing.T) {
+	repo := newFakeAdRepo()
+	uc, cache := newTestAdUseCaseWithCache(t, repo, testConfig())
+	cache.SetError("LOADING Redis is loading the dataset in memory")
+
+	_, _, err := uc.CreateAdIdempotent(asUser(domain.RoleSeller), "key-1", newSellerAd())
+	if !errors.Is(err, domain.ErrIdempotencyUnavailable) {
+		t.Errorf("error = %v, want %v", err, domain.ErrIdempotencyUnavailable)
+	}
+	if creates := repo.creates.Load(); creates != 0 {
+		t.Errorf("created %d ads without the idempotency key, want 0", creates)
+	}
+}
+
+func TestGetAdsDefaultVisibility(t *testing.T) {
+	tests := []struct {
+		name    string
+		visible []domain.AdStatus
+	}{
+		{"production", []domain.AdStatus{domain.StatusActive, domain.StatusApproved}},
+		{"pending shown", []domain.AdStatus{domain.StatusPending, domain.StatusActive}},
+		{"staging, every status", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.DefaultVisibleStatuses = tt.visible
+			repo := newFakeAdRepo()
+			uc := newTestAdUseCase(t, repo, cfg)
+
+			draft := domain.StatusDraft
+			if _, err := uc.GetAds(context.Background(), domain.FilterRequest{Lang: "en", Status: &draft}); err != nil {
+				t.Fatal(err)
+			}
+			if len(repo.filters) != 1 {
+				t.Fatalf("queried %d times, want 1", len(repo.filters))
+			}
+			if got := repo.filters[0].Statuses; !slices.Equal(got, tt.visible) {
+				t.Errorf("anonymous listing limited to %v, want %v", got, tt.visible)
+			}
+		})
+	}
+}
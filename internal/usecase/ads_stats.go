@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/1way-market/v3/internal/domain"
+)
+
+// adsStatsTTL is how long the ads statistics are cached; dashboards poll them
+const adsStatsTTL = time.Minute
+
+// GetAdsStats returns the number of ads per status and the number of ads
+// created on each of the last days UTC days, today included
+func (uc *AdUseCase) GetAdsStats(ctx context.Context, days int) (*domain.AdsStats, error) {
+	cacheKey := uc.adsCacheKey(ctx, fmt.Sprintf("stats:%d", days))
+	if cachedData, ok := uc.cacheGet(ctx, cacheKey); ok {
+		var stats domain.AdsStats
+		if err := uc.serializer.decode(cachedData, &stats); err == nil {
+			return &stats, nil
+		}
+	}
+
+	byStatus, err := uc.repo.CountByStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, 1-days)
+	byDay, err := uc.repo.CountCreatedByDay(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &domain.AdsStats{Days: days}
+
+	// Every known status is listed, with codes outside the known range appended
+	for status := domain.StatusDraft; status <= domain.StatusDuplicate; status++ {
+		stats.ByStatus = append(stats.ByStatus, domain.StatusCount{Status: status, Name: status.String(), Count: byStatus[status]})
+	}
+	var others []domain.AdStatus
+	for status := range byStatus {
+		if status < domain.StatusDraft || status > domain.StatusDuplicate {
+			others = append(others, status)
+		}
+	}
+	slices.Sort(others)
+	for _, status := range others {
+		stats.ByStatus = append(stats.ByStatus, domain.StatusCount{Status: status, Name: status.String(), Count: byStatus[status]})
+	}
+
+	for day := since; !day.After(today); day = day.AddDate(0, 0, 1) {
+		date := day.Format(time.DateOnly)
+		stats.CreatedDaily = append(stats.CreatedDaily, domain.DailyCount{Date: date, Count: byDay[date]})
+	}
+
+	if data, err := uc.serializer.encode(stats); err == nil {
+		uc.cacheSet(ctx, cacheKey, data, adsStatsTTL)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// adsCachePrefix namespaces the cached ad listings and aggregates, which are
+// dropped together whenever an ad changes. Other ads:* keys, such as the event
+// stream, are not cache entries and are left alone.
+const adsCachePrefix = "ads:cache:"
+
+// adsCacheVersionKey holds the generation of the ads cache. Entries are keyed
+// by it, so incrementing it drops them all at once without scanning the
+// keyspace; the entries of older generations expire with their TTLs.
+const adsCacheVersionKey = adsCachePrefix + "version"
+
+// cacheDeleteBatch is the number of keys scanned or deleted per round trip
+const cacheDeleteBatch = 100
+
+// adsCacheKey returns the key of a cached ads listing or aggregate in the
+// current generation of the ads cache. It returns an empty key, which
+// cacheGet and cacheSet skip, when the generation cannot be read.
+func (uc *AdUseCase) adsCacheKey(ctx context.Context, key string) string {
+	ctx, cancel := uc.cacheContext(ctx)
+	defer cancel()
+
+	version, err := uc.cache.Get(ctx, adsCacheVersionKey).Int64()
+	if err != nil && err != redis.Nil {
+		log.Printf("Warning: ads cache version read failed: %v", err)
+		return ""
+	}
+	return adsCachePrefix + strconv.FormatInt(version, 10) + ":" + key
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ugorji/go/codec"
+)
+
+// CacheCodec serializes cache entries
+type CacheCodec interface {
+	// Format is the byte prefixed to entries written by the codec
+	Format() byte
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// Cache entry format prefixes. The high bit marks gzip-compressed payloads.
+const (
+	cacheFormatJSON    byte = 0x01
+	cacheFormatMsgpack byte = 0x02
+	cacheFormatGzip    byte = 0x80
+)
+
+// jsonCodec is the default cache codec
+type jsonCodec struct{}
+
+func (jsonCodec) Format() byte { return cacheFormatJSON }
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// msgpackHandle encodes structs by their JSON field names and times as msgpack timestamps
+var msgpackHandle = func() *codec.MsgpackHandle {
+	h := &codec.MsgpackHandle{}
+	h.WriteExt = true
+	return h
+}()
+
+// msgpackCodec stores entries as MessagePack, which is smaller and faster to decode than JSON
+type msgpackCodec struct{}
+
+func (msgpackCodec) Format() byte { return cacheFormatMsgpack }
+
+func (msgpackCodec) Marshal(v interface{}) ([]byte, error) {
+	var data []byte
+	err := codec.NewEncoderBytes(&data, msgpackHandle).Encode(v)
+	return data, err
+}
+
+func (msgpackCodec) Unmarshal(data []byte, v interface{}) error {
+	return codec.NewDecoderBytes(data, msgpackHandle).Decode(v)
+}
+
+// cacheCodecs are the codecs entries can be read with, whichever one writes them
+var cacheCodecs = map[byte]CacheCodec{
+	cacheFormatJSON:    jsonCodec{},
+	cacheFormatMsgpack: msgpackCodec{},
+}
+
+// NewCacheCodec returns the codec with the given name: json or msgpack
+func NewCacheCodec(name string) (CacheCodec, error) {
+	switch name {
+	case "", "json":
+		return jsonCodec{}, nil
+	case "msgpack":
+		return msgpackCodec{}, nil
+	default:
+		return nil, fmt.Errorf("unknown cache codec %q", name)
+	}
+}
+
+// cacheSerializer writes cache entries with a format prefix, gzipping entries
+// of at least gzipMinSize bytes when gzipMinSize is positive. It reads entries
+// of any format, so entries written before a codec change stay readable.
+type cacheSerializer struct {
+	codec       CacheCodec
+	gzipMinSize int
+}
+
+func (s cacheSerializer) encode(v interface{}) ([]byte, error) {
+	payload, err := s.codec.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	format := s.codec.Format()
+	if s.gzipMinSize > 0 && len(payload) >= s.gzipMinSize {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(payload); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		payload = buf.Bytes()
+		format |= cacheFormatGzip
+	}
+
+	return append([]byte{format}, payload...), nil
+}
+
+func (s cacheSerializer) decode(data []byte, v interface{}) error {
+	if len(data) == 0 {
+		return fmt.Errorf("empty cache entry")
+	}
+
+	// Entries written before format prefixes existed are plain JSON
+	entryCodec, ok := cacheCodecs[data[0]&^cacheFormatGzip]
+	if !ok {
+		return json.Unmarshal(data, v)
+	}
+
+	payload := data[1:]
+	if data[0]&cacheFormatGzip != 0 {
+		zr, err := gzip.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		if payload, err = io.ReadAll(zr); err != nil {
+			return err
+		}
+	}
+	return entryCodec.Unmarshal(payload, v)
+}
@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/1way-market/v3/internal/domain"
+)
+
+// samplePage returns a page of n ads shaped like a category listing
+func samplePage(n int) *domain.PaginatedResponse {
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	page := &domain.PaginatedResponse{NextPage: "eyJpZCI6MTAwfQ", TotalCount: 12345}
+	for i := 1; i <= n; i++ {
+		sellerID := uint(i % 7)
+		valueID := uint(i % 5)
+		page.Items = append(page.Items, domain.Ad{
+			ID: uint(i),
+			Title: domain.MultiLangArray{
+				{Lang: domain.LangRussian, Text: fmt.Sprintf("Продаётся велосипед горный, почти новый %d", i)},
+				{Lang: domain.LangEnglish, Text: fmt.Sprintf("Mountain bike for sale, almost new %d", i)},
+			},
+			Description: domain.MultiLangArray{
+				{Lang: domain.LangEnglish, Text: "Aluminium frame, 21 gears, hydraulic disc brakes. Ridden twice, kept indoors. Pickup only."},
+			},
+			Properties: domain.AdProperties{
+				{ID: 1, Value: "aluminium"},
+				{ID: 2, ValueID: &valueID},
+				{ID: 3, Value: "21"},
+			},
+			CategoryIDs: []int{1, 12, 124},
+			Images: domain.Images{
+				{Key: fmt.Sprintf("ads/%d/1.webp", i), URL: fmt.Sprintf("https://cdn.1way.market/ads/%d/1.webp", i), Width: 1280, Height: 960, IsPrimary: true},
+				{Key: fmt.Sprintf("ads/%d/2.webp", i), URL: fmt.Sprintf("https://cdn.1way.market/ads/%d/2.webp", i), Width: 1280, Height: 960},
+			},
+			Status:      domain.StatusActive,
+			Price:       &domain.Price{Value: float64(100 + i), Currency: string(domain.CurrencyUSD), Type: domain.PriceFixed},
+			SellerID:    &sellerID,
+			Slug:        fmt.Sprintf("mountain-bike-for-sale-almost-new-%d-%d", i, i),
+			PhoneMasked: "+90 5** *** ** 12",
+			ViewCount:   int64(i * 3),
+			Version:     1,
+			CreatedAt:   created.Add(time.Duration(i) * time.Minute),
+			UpdatedAt:   created.Add(time.Duration(i) * time.Hour),
+		})
+	}
+	return page
+}
+
+// cacheSerializers are the serializer setups CACHE_CODEC and
+// CACHE_GZIP_MIN_SIZE select between
+var cacheSerializers = []struct {
+	name       string
+	serializer cacheSerializer
+}{
+	{"json", cacheSerializer{codec: jsonCodec{}}},
+	{"json+gzip", cacheSerializer{codec: jsonCodec{}, gzipMinSize: 1}},
+	{"msgpack", cacheSerializer{codec: msgpackCodec{}}},
+	{"msgpack+gzip", cacheSerializer{codec: msgpackCodec{}, gzipMinSize: 1}},
+}
+
+// checkSamplePage fails the test unless page holds what samplePage(n) does
+func checkSamplePage(t *testing.T, page *domain.PaginatedResponse, n int) {
+	t.Helper()
+	want := samplePage(n)
+	if len(page.Items) != n || page.TotalCount != want.TotalCount || page.NextPage != want.NextPage {
+		t.Fatalf("decoded %d ads, total %d, next %q; want %d, %d, %q",
+			len(page.Items), page.TotalCount, page.NextPage, n, want.TotalCount, want.NextPage)
+	}
+	for i := range page.Items {
+		got, wanted := page.Items[i], want.Items[i]
+		if got.ID != wanted.ID || got.Title[0] != wanted.Title[0] || got.Slug != wanted.Slug ||
+			*got.Price != *wanted.Price || !got.CreatedAt.Equal(wanted.CreatedAt) ||
+			len(got.Images) != len(wanted.Images) || *got.Properties[1].ValueID != *wanted.Properties[1].ValueID {
+			t.Fatalf("ad %d decoded as %+v, want %+v", i, got, wanted)
+		}
+	}
+}
+
+func TestCacheSerializerRoundTrip(t *testing.T) {
+	for _, tt := range cacheSerializers {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.serializer.encode(samplePage(3))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var page domain.PaginatedResponse
+			if err := tt.serializer.decode(data, &page); err != nil {
+				t.Fatal(err)
+			}
+			checkSamplePage(t, &page, 3)
+		})
+	}
+}
+
+func TestCacheSerializerReadsEveryFormat(t *testing.T) {
+	// During a rollout, entries of every format coexist whichever codec reads them
+	legacy, err := json.Marshal(samplePage(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := map[string][]byte{"unprefixed json": legacy}
+	for _, tt := range cacheSerializers {
+		if entries[tt.name], err = tt.serializer.encode(samplePage(3)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, reader := range cacheSerializers {
+		for name, data := range entries {
+			t.Run(reader.name+" reads "+name, func(t *testing.T) {
+				var page domain.PaginatedResponse
+				if err := reader.serializer.decode(data, &page); err != nil {
+					t.Fatal(err)
+				}
+				checkSamplePage(t, &page, 3)
+			})
+		}
+	}
+}
+
+func BenchmarkCacheEncode(b *testing.B) {
+	page := samplePage(100)
+	for _, tt := range cacheSerializers {
+		b.Run(tt.name, func(b *testing.B) {
+			var size int
+			for i := 0; i < b.N; i++ {
+				data, err := tt.serializer.encode(page)
+				if err != nil {
+					b.Fatal(err)
+				}
+				size = len(data)
+			}
+			// The entry size is what the page takes in Redis, besides key overhead
+			b.ReportMetric(float64(size), "entry-bytes")
+		})
+	}
+}
+
+func BenchmarkCacheDecode(b *testing.B) {
+	page := samplePage(100)
+	for _, tt := range cacheSerializers {
+		b.Run(tt.name, func(b *testing.B) {
+			data, err := tt.serializer.encode(page)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.SetBytes(int64(len(data)))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				var decoded domain.PaginatedResponse
+				if err := tt.serializer.decode(data, &decoded); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(data)), "entry-bytes")
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+
+	"github.com/1way-market/v3/internal/config"
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/go-redis/redis/v8"
+)
+
+type CategoryCountRepository interface {
+	CountActiveByCategory(ctx context.Context) (map[int]int64, error)
+}
+
+type CategoryTreeRepository interface {
+	GetByID(ctx context.Context, id int) (*domain.Category, error)
+	Create(ctx context.Context, category *domain.Category) error
+	Update(ctx context.Context, category *domain.Category) error
+	Delete(ctx context.Context, id int) error
+	GetPath(ctx context.Context, id int) ([]domain.Category, error)
+	GetDescendants(ctx context.Context, id int) ([]int, error)
+}
+
+type CategoryUseCase struct {
+	repo       CategoryCountRepository
+	categories CategoryTreeRepository
+	cache      *redis.Client
+	cfg        *config.Config
+}
+
+func NewCategoryUseCase(repo CategoryCountRepository, categories CategoryTreeRepository, cache *redis.Client, cfg *config.Config) *CategoryUseCase {
+	return &CategoryUseCase{
+		repo:       repo,
+		categories: categories,
+		cache:      cache,
+		cfg:        cfg,
+	}
+}
+
+// categoryPathKey is the cache key of the ancestor path of a category
+func categoryPathKey(id int) string {
+	return "category:path:" + strconv.Itoa(id)
+}
+
+// GetCategory returns the category with the given ID or domain.ErrNotFound
+func (uc *CategoryUseCase) GetCategory(ctx context.Context, id int) (*domain.Category, error) {
+	return uc.categories.GetByID(ctx, id)
+}
+
+// CreateCategory saves a new category below category.ParentID, or at the root
+func (uc *CategoryUseCase) CreateCategory(ctx context.Context, category *domain.Category) error {
+	if err := domain.ValidateCategory(category); err != nil {
+		return err
+	}
+	if err := uc.categories.Create(ctx, category); err != nil {
+		return err
+	}
+	uc.deleteCategoryPaths(ctx, []int{category.ID})
+	return nil
+}
+
+// UpdateCategory renames the category and moves it with its subcategories
+// below category.ParentID. The cached paths of the whole subtree are dropped
+// since they all carry the category.
+func (uc *CategoryUseCase) UpdateCategory(ctx context.Context, category *domain.Category) error {
+	if err := domain.ValidateCategory(category); err != nil {
+		return err
+	}
+	if err := uc.categories.Update(ctx, category); err != nil {
+		return err
+	}
+	subtree, err := uc.categories.GetDescendants(ctx, category.ID)
+	if err != nil {
+		log.Printf("Warning: cached paths below category %d not dropped: %v", category.ID, err)
+	}
+	uc.deleteCategoryPaths(ctx, append(subtree, category.ID))
+	return nil
+}
+
+// DeleteCategory deletes a category without subcategories
+func (uc *CategoryUseCase) DeleteCategory(ctx context.Context, id int) error {
+	if err := uc.categories.Delete(ctx, id); err != nil {
+		return err
+	}
+	uc.deleteCategoryPaths(ctx, []int{id})
+	return nil
+}
+
+// GetPath returns the category's ancestors from the root down to the
+// category itself, or domain.ErrNotFound. Paths are cached for
+// CATEGORY_PATH_CACHE_TTL, or until a category on them changes.
+func (uc *CategoryUseCase) GetPath(ctx context.Context, id int) ([]domain.Category, error) {
+	key := categoryPathKey(id)
+	if uc.cache != nil {
+		cacheCtx, cancel := context.WithTimeout(ctx, uc.cfg.RedisCacheTimeout)
+		data, err := uc.cache.Get(cacheCtx, key).Bytes()
+		cancel()
+		var path []domain.Category
+		if err == nil && json.Unmarshal(data, &path) == nil {
+			return path, nil
+		}
+		if err != nil && err != redis.Nil {
+			log.Printf("Warning: cache read of %s failed: %v", key, err)
+		}
+	}
+
+	path, err := uc.categories.GetPath(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if uc.cache != nil {
+		if data, err := json.Marshal(path); err == nil {
+			cacheCtx, cancel := context.WithTimeout(ctx, uc.cfg.RedisCacheTimeout)
+			if err := uc.cache.Set(cacheCtx, key, data, uc.cfg.CategoryPathTTL).Err(); err != nil {
+				log.Printf("Warning: cache write of %s failed: %v", key, err)
+			}
+			cancel()
+		}
+	}
+	return path, nil
+}
+
+// deleteCategoryPaths drops the cached paths of the given categories. A path
+// left behind is served until CATEGORY_PATH_CACHE_TTL, so failures are only
+// logged.
+func (uc *CategoryUseCase) deleteCategoryPaths(ctx context.Context, ids []int) {
+	if uc.cache == nil {
+		return
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = categoryPathKey(id)
+	}
+	for start := 0; start < len(keys); start += cacheDeleteBatch {
+		end := start + cacheDeleteBatch
+		if end > len(keys) {
+			end = len(keys)
+		}
+		if err := uc.cache.Del(ctx, keys[start:end]...).Err(); err != nil {
+			log.Printf("Warning: deleting cached category paths failed: %v", err)
+			return
+		}
+	}
+}
+
+// GetCountsBatch returns the number of active ads for each of the given categories
+func (uc *CategoryUseCase) GetCountsBatch(ctx context.Context, ids []int) (map[int]int64, error) {
+	counts := make(map[int]int64, len(ids))
+	if len(ids) == 0 {
+		return counts, nil
+	}
+
+	members := make([]string, len(ids))
+	for i, id := range ids {
+		members[i] = strconv.Itoa(id)
+	}
+
+	scores, err := uc.cache.ZMScore(ctx, categoryAdCountsKey, members...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, id := range ids {
+		counts[id] = int64(scores[i])
+	}
+	return counts, nil
+}
+
+// WarmUpCounts rebuilds the category count sorted set from the database
+func (uc *CategoryUseCase) WarmUpCounts(ctx context.Context) error {
+	counts, err := uc.repo.CountActiveByCategory(ctx)
+	if err != nil {
+		return err
+	}
+
+	members := make([]*redis.Z, 0, len(counts))
+	for categoryID, count := range counts {
+		members = append(members, &redis.Z{Score: float64(count), Member: strconv.Itoa(categoryID)})
+	}
+
+	// Build the set under a temporary key and swap it in so readers never see a partial set
+	tmpKey := categoryAdCountsKey + ":warmup"
+	pipe := uc.cache.TxPipeline()
+	pipe.Del(ctx, tmpKey)
+	if len(members) > 0 {
+		pipe.ZAdd(ctx, tmpKey, members...)
+		pipe.Rename(ctx, tmpKey, categoryAdCountsKey)
+	} else {
+		pipe.Del(ctx, categoryAdCountsKey)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
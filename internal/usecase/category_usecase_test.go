@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/1way-market/v3/internal/domain"
+)
+
+// fakeCategoryTree serves categories from a tree given as each category's parent
+type fakeCategoryTree struct {
+	parents map[int]int
+	names   map[int]string
+	reads   int
+}
+
+func (f *fakeCategoryTree) category(id int) domain.Category {
+	category := domain.Category{ID: id, Name: domain.MultiLangArray{{Lang: domain.LangEnglish, Text: f.names[id]}}}
+	if parent := f.parents[id]; parent != 0 {
+		category.ParentID = &parent
+	}
+	return category
+}
+
+func (f *fakeCategoryTree) GetByID(ctx context.Context, id int) (*domain.Category, error) {
+	if _, ok := f.parents[id]; !ok {
+		return nil, domain.ErrNotFound
+	}
+	category := f.category(id)
+	return &category, nil
+}
+
+// Create gives the category the lowest free ID, so that a category created
+// after a deletion takes over the ID of the deleted one
+func (f *fakeCategoryTree) Create(ctx context.Context, category *domain.Category) error {
+	category.ID = 1
+	for _, taken := f.parents[category.ID]; taken; _, taken = f.parents[category.ID] {
+		category.ID++
+	}
+	return f.Update(ctx, category)
+}
+
+func (f *fakeCategoryTree) Update(ctx context.Context, category *domain.Category) error {
+	if f.names == nil {
+		f.names = make(map[int]string)
+	}
+	f.parents[category.ID] = 0
+	if category.ParentID != nil {
+		f.parents[category.ID] = *category.ParentID
+	}
+	f.names[category.ID] = category.Name[0].Text
+	return nil
+}
+
+func (f *fakeCategoryTree) Delete(ctx context.Context, id int) error {
+	delete(f.parents, id)
+	return nil
+}
+
+func (f *fakeCategoryTree) GetPath(ctx context.Context, id int) ([]domain.Category, error) {
+	f.reads++
+	if _, ok := f.parents[id]; !ok {
+		return nil, domain.ErrNotFound
+	}
+	path := []domain.Category{f.category(id)}
+	for parent := f.parents[id]; parent != 0; parent = f.parents[parent] {
+		path = append([]domain.Category{f.category(parent)}, path...)
+	}
+	return path, nil
+}
+
+func (f *fakeCategoryTree) GetDescendants(ctx context.Context, id int) ([]int, error) {
+	var ids []int
+	for child := range f.parents {
+		for ancestor := child; ancestor != 0; ancestor = f.parents[ancestor] {
+			if ancestor == id {
+				ids = append(ids, child)
+				break
+			}
+		}
+	}
+	return ids, nil
+}
+
+// pathIDs returns the IDs of the categories on a path
+func pathIDs(path []domain.Category) []int {
+	ids := make([]int, len(path))
+	for i, category := range path {
+		ids[i] = category.ID
+	}
+	return ids
+}
+
+func TestGetPathIsCached(t *testing.T) {
+	categories := &fakeCategoryTree{parents: map[int]int{1: 0, 5: 1, 12: 5}}
+	cache, server := newTestCache(t)
+	cfg := testConfig()
+	cfg.CategoryPathTTL = time.Hour
+	uc := NewCategoryUseCase(nil, categories, cache, cfg)
+
+	for i := 0; i < 2; i++ {
+		path, err := uc.GetPath(context.Background(), 12)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []int{1, 5, 12}; !slices.Equal(pathIDs(path), want) {
+			t.Errorf("path = %v, want %v", path, want)
+		}
+	}
+	if categories.reads != 1 {
+		t.Errorf("path read %d times, want once", categories.reads)
+	}
+	if ttl := server.TTL(categoryPathKey(12)); ttl != time.Hour {
+		t.Errorf("cached for %v, want %v", ttl, time.Hour)
+	}
+
+	server.FastForward(time.Hour)
+	if _, err := uc.GetPath(context.Background(), 12); err != nil {
+		t.Fatal(err)
+	}
+	if categories.reads != 2 {
+		t.Errorf("path read %d times after the TTL, want twice", categories.reads)
+	}
+}
+
+func TestGetPathOfMissingCategory(t *testing.T) {
+	cache, server := newTestCache(t)
+	uc := NewCategoryUseCase(nil, &fakeCategoryTree{}, cache, testConfig())
+
+	if _, err := uc.GetPath(context.Background(), 12); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("error = %v, want %v", err, domain.ErrNotFound)
+	}
+	if server.Exists(categoryPathKey(12)) {
+		t.Error("missing category cached")
+	}
+}
+
+func TestCategoryChangesDropPaths(t *testing.T) {
+	english := func(text string) domain.MultiLangArray {
+		return domain.MultiLangArray{{Lang: domain.LangEnglish, Text: text}}
+	}
+	four := 4
+	tests := []struct {
+		name   string
+		change func(ctx context.Context, uc *CategoryUseCase) error
+		want   []int
+		names  []string
+	}{
+		{"rename an ancestor", func(ctx context.Context, uc *CategoryUseCase) error {
+			return uc.UpdateCategory(ctx, &domain.Category{ID: 1, Name: english("Transport")})
+		}, []int{1, 2, 3}, []string{"Transport", "2", "3"}},
+		{"move an ancestor", func(ctx context.Context, uc *CategoryUseCase) error {
+			return uc.UpdateCategory(ctx, &domain.Category{ID: 2, ParentID: &four, Name: english("2")})
+		}, []int{4, 2, 3}, []string{"4", "2", "3"}},
+		{"delete and recreate", func(ctx context.Context, uc *CategoryUseCase) error {
+			if err := uc.DeleteCategory(ctx, 3); err != nil {
+				return err
+			}
+			return uc.CreateCategory(ctx, &domain.Category{ParentID: &four, Name: english("Scooters")})
+		}, []int{4, 3}, []string{"4", "Scooters"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 1 > 2 > 3, and 4 at the root
+			categories := &fakeCategoryTree{
+				parents: map[int]int{1: 0, 2: 1, 3: 2, 4: 0},
+				names:   map[int]string{1: "1", 2: "2", 3: "3", 4: "4"},
+			}
+			cache, _ := newTestCache(t)
+			uc := NewCategoryUseCase(nil, categories, cache, testConfig())
+			ctx := context.Background()
+
+			for _, id := range []int{3, 4} {
+				if _, err := uc.GetPath(ctx, id); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := tt.change(ctx, uc); err != nil {
+				t.Fatal(err)
+			}
+
+			path, err := uc.GetPath(ctx, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, category := range path {
+				names = append(names, category.Name[0].Text)
+			}
+			if !slices.Equal(pathIDs(path), tt.want) || !slices.Equal(names, tt.names) {
+				t.Errorf("path of 3 = %v %v, want %v %v", pathIDs(path), names, tt.want, tt.names)
+			}
+			if _, err := uc.GetPath(ctx, 4); err != nil || categories.reads != 3 {
+				t.Errorf("path of 4 read %d times from the database, error %v, want it still cached", categories.reads, err)
+			}
+		})
+	}
+}
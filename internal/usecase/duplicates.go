@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/1way-market/v3/internal/domain"
+)
+
+// markDuplicate gives a parser import found to duplicate a recent ad the
+// duplicate status and the ID of that ad. Detection is best effort: a failed
+// check is logged and the ad is created as it is.
+func (uc *AdUseCase) markDuplicate(ctx context.Context, ad *domain.Ad) {
+	// Only duplicate detection marks ads as duplicates
+	ad.DuplicateOf = nil
+	if ad.Status != domain.StatusFromParser || uc.cfg.DuplicateThreshold <= 0 {
+		return
+	}
+
+	id, err := uc.repo.FindDuplicate(ctx, ad, uc.cfg.DuplicateWindow, uc.cfg.DuplicateThreshold)
+	if err != nil {
+		log.Printf("Warning: duplicate check of a parser import failed: %v", err)
+		return
+	}
+	if id != nil {
+		ad.Status = domain.StatusDuplicate
+		ad.DuplicateOf = id
+	}
+}
+
+// GetDuplicates returns the duplicate review of the ad with the given ID: the
+// ad it was found to duplicate and the ads found to duplicate it
+func (uc *AdUseCase) GetDuplicates(ctx context.Context, id uint) (*domain.AdDuplicates, error) {
+	ad, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	review := &domain.AdDuplicates{}
+	if ad.DuplicateOf != nil {
+		original, err := uc.repo.GetByID(ctx, *ad.DuplicateOf)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+		review.DuplicateOf = original
+	}
+
+	review.Duplicates, err = uc.repo.FindDuplicates(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if review.Duplicates == nil {
+		review.Duplicates = []domain.Ad{}
+	}
+	return review, nil
+}
+
+// ClearDuplicate overrides the duplicate detection for an ad marked as a
+// duplicate, returning it to pending moderation
+func (uc *AdUseCase) ClearDuplicate(ctx context.Context, id uint) (*domain.Ad, error) {
+	ad, err := uc.repo.ClearDuplicate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.indexAd(ctx, ad)
+	uc.invalidateAdsCache(ctx)
+	uc.invalidateAd(ctx, id)
+	uc.publishInvalidation(ctx, id, ad)
+	return ad, nil
+}
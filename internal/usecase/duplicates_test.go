@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/1way-market/v3/internal/domain"
+)
+
+func TestCreateAdMarksDuplicates(t *testing.T) {
+	original := uint(7)
+	tests := []struct {
+		name         string
+		status       domain.AdStatus
+		threshold    float64
+		duplicateErr error
+		wantStatus   domain.AdStatus
+		wantOf       *uint
+	}{
+		{"parser import duplicating an ad", domain.StatusFromParser, 0.8, nil, domain.StatusDuplicate, &original},
+		{"not a parser import", domain.StatusPending, 0.8, nil, domain.StatusPending, nil},
+		{"detection disabled", domain.StatusFromParser, 0, nil, domain.StatusFromParser, nil},
+		{"failed check", domain.StatusFromParser, 0.8, errors.New("database unavailable"), domain.StatusFromParser, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeAdRepo()
+			repo.duplicateOf, repo.duplicateErr = &original, tt.duplicateErr
+			cfg := testConfig()
+			cfg.DuplicateThreshold = tt.threshold
+			uc := newTestAdUseCase(t, repo, cfg)
+
+			ad := newSellerAd()
+			ad.Status = tt.status
+			// Clients cannot mark ads as duplicates themselves
+			claimed := uint(3)
+			ad.DuplicateOf = &claimed
+			if err := uc.CreateAd(asUser(domain.RoleParser), ad); err != nil {
+				t.Fatal(err)
+			}
+			if ad.Status != tt.wantStatus {
+				t.Errorf("status = %v, want %v", ad.Status, tt.wantStatus)
+			}
+			if (ad.DuplicateOf == nil) != (tt.wantOf == nil) || (ad.DuplicateOf != nil && *ad.DuplicateOf != *tt.wantOf) {
+				t.Errorf("duplicate of %v, want %v", ad.DuplicateOf, tt.wantOf)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/go-redis/redis/v8"
+)
+
+// expirationLockKey is held by the instance expiring ads, so that one instance
+// runs each expiration check
+const expirationLockKey = "lock:ads:expiration"
+
+// expirationBatchSize is the number of ads completed per expiration transaction
+const expirationBatchSize = 500
+
+// ExpireAds completes the active ads past their expiry date, in batches, and
+// returns how many were completed
+func (uc *AdUseCase) ExpireAds(ctx context.Context) (int, error) {
+	expired := 0
+	for {
+		ads, err := uc.repo.ExpireAds(ctx, expirationBatchSize)
+		if err != nil {
+			return expired, err
+		}
+		uc.statusesChanged(ctx, ads, domain.StatusActive)
+		expired += len(ads)
+		if len(ads) < expirationBatchSize {
+			return expired, nil
+		}
+	}
+}
+
+// RenewAd starts a new lifetime of AD_LIFETIME for an active or expired ad,
+// reactivating an expired one
+func (uc *AdUseCase) RenewAd(ctx context.Context, id uint) (*domain.Ad, error) {
+	existing, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeOwner(ctx, existing); err != nil {
+		return nil, err
+	}
+
+	ad, err := uc.repo.RenewAd(ctx, id, uc.cfg.AdLifetime)
+	if err != nil {
+		return nil, err
+	}
+
+	deltas := make(map[int]int64)
+	addCategoryDeltas(deltas, existing, -1)
+	addCategoryDeltas(deltas, ad, 1)
+	uc.adjustCategoryCounts(ctx, deltas)
+	uc.indexAd(ctx, ad)
+
+	uc.invalidateAdsCache(ctx)
+	uc.invalidateAd(ctx, id)
+	uc.publishInvalidation(ctx, id, ad)
+	return ad, nil
+}
+
+// startLifetime gives an ad becoming active the expiry date of AD_LIFETIME
+// from now, unless it has a future one already
+func (uc *AdUseCase) startLifetime(ad *domain.Ad) {
+	if now := time.Now(); ad.ExpiresAt == nil || !ad.ExpiresAt.After(now) {
+		expiresAt := now.Add(uc.cfg.AdLifetime)
+		ad.ExpiresAt = &expiresAt
+	}
+}
+
+// ExpirationWorker periodically completes the active ads past their expiry date.
+// Instances take turns through Redis, and concurrent expirations would skip
+// each other's ads anyway.
+type ExpirationWorker struct {
+	ads      *AdUseCase
+	cache    *redis.Client
+	interval time.Duration
+}
+
+func NewExpirationWorker(ads *AdUseCase, cache *redis.Client, interval time.Duration) *ExpirationWorker {
+	return &ExpirationWorker{
+		ads:      ads,
+		cache:    cache,
+		interval: interval,
+	}
+}
+
+// Run expires ads every interval until ctx is done
+func (w *ExpirationWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.runOnce(ctx)
+		}
+	}
+}
+
+// runOnce expires the ads if no other instance did in this interval
+func (w *ExpirationWorker) runOnce(ctx context.Context) {
+	if !takeTurn(ctx, w.cache, expirationLockKey, w.interval) {
+		return
+	}
+
+	expired, err := w.ads.ExpireAds(ctx)
+	if err != nil {
+		log.Printf("Warning: ad expiration failed: %v", err)
+		return
+	}
+	log.Printf("Expired %d ads", expired)
+}
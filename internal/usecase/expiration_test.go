@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1way-market/v3/internal/domain"
+)
+
+func TestExpireAdsUpdatesCounts(t *testing.T) {
+	repo := newFakeAdRepo()
+	repo.expiring = []domain.Ad{
+		{ID: 1, Status: domain.StatusActive, CategoryIDs: []int{4}},
+		{ID: 2, Status: domain.StatusActive, CategoryIDs: []int{4, 5}},
+	}
+	uc, server := newTestAdUseCaseWithCache(t, repo, testConfig())
+	server.ZAdd(categoryAdCountsKey, 2, "4")
+	server.ZAdd(categoryAdCountsKey, 1, "5")
+	if _, err := uc.GetAds(context.Background(), domain.FilterRequest{Lang: "en"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expired, err := uc.ExpireAds(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expired != 2 {
+		t.Errorf("expired %d ads, want 2", expired)
+	}
+	for category, want := range map[string]float64{"4": 0, "5": 0} {
+		if count, _ := server.ZScore(categoryAdCountsKey, category); count != want {
+			t.Errorf("category %s counts %v active ads, want %v", category, count, want)
+		}
+	}
+	if _, err := uc.GetAds(context.Background(), domain.FilterRequest{Lang: "en"}); err != nil {
+		t.Fatal(err)
+	}
+	if finds := repo.finds.Load(); finds != 2 {
+		t.Errorf("listing read %d times from the database, want it reloaded after ads expired", finds)
+	}
+}
+
+func TestExpireAdsInBatches(t *testing.T) {
+	repo := newFakeAdRepo()
+	for id := 1; id <= 2*expirationBatchSize+1; id++ {
+		repo.expiring = append(repo.expiring, domain.Ad{ID: uint(id), Status: domain.StatusActive})
+	}
+	uc := newTestAdUseCase(t, repo, testConfig())
+
+	expired, err := uc.ExpireAds(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expired != 2*expirationBatchSize+1 {
+		t.Errorf("expired %d ads, want %d", expired, 2*expirationBatchSize+1)
+	}
+	if batches := repo.expirations.Load(); batches != 3 {
+		t.Errorf("expired in %d batches, want 3", batches)
+	}
+}
+
+func TestRenewAd(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr error
+	}{
+		{"owner", asUser(domain.RoleSeller), nil},
+		{"moderator", domain.WithPrincipal(context.Background(), &domain.Principal{UserID: "user-2", Roles: []string{domain.RoleModerator}}), nil},
+		{"other user", domain.WithPrincipal(context.Background(), &domain.Principal{UserID: "user-2"}), domain.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expiredAt := time.Now().Add(-time.Hour)
+			repo := newFakeAdRepo(domain.Ad{ID: 1, OwnerID: "user-1", Status: domain.StatusCompleted, StatusReason: domain.CompletedByExpiry, ExpiresAt: &expiredAt})
+			uc := newTestAdUseCase(t, repo, testConfig())
+
+			ad, err := uc.RenewAd(tt.ctx, 1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (ad.Status != domain.StatusActive || !ad.ExpiresAt.After(time.Now().Add(29*24*time.Hour))) {
+				t.Errorf("renewed ad = %v expiring at %v, want active for AD_LIFETIME", ad.Status, ad.ExpiresAt)
+			}
+		})
+	}
+}
+
+func TestActivatedAdsStartTheirLifetime(t *testing.T) {
+	later := time.Now().Add(90 * 24 * time.Hour)
+	tests := []struct {
+		name      string
+		expiresAt *time.Time
+		want      time.Duration
+	}{
+		{"without expiry date", nil, 30 * 24 * time.Hour},
+		{"with a future expiry date", &later, 90 * 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeAdRepo()
+			uc := newTestAdUseCase(t, repo, testConfig())
+
+			ad := newSellerAd()
+			ad.Status, ad.ExpiresAt = domain.StatusActive, tt.expiresAt
+			if err := uc.CreateAd(asUser(domain.RoleModerator), ad); err != nil {
+				t.Fatal(err)
+			}
+			if ad.ExpiresAt == nil || time.Until(*ad.ExpiresAt).Round(time.Hour) != tt.want {
+				t.Errorf("expires at %v, want in %v", ad.ExpiresAt, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateAdKeepsExpiryDate(t *testing.T) {
+	expiresAt := time.Now().Add(10 * 24 * time.Hour).Truncate(time.Second)
+	later := expiresAt.Add(5 * 24 * time.Hour)
+	tests := []struct {
+		name      string
+		expiresAt *time.Time
+		want      time.Time
+	}{
+		{"without expiry date", nil, expiresAt},
+		{"with a new expiry date", &later, later},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeAdRepo(domain.Ad{ID: 1, OwnerID: "user-1", Status: domain.StatusActive, Version: 1, ExpiresAt: &expiresAt})
+			uc := newTestAdUseCase(t, repo, testConfig())
+
+			ad := newSellerAd()
+			ad.ID, ad.Status, ad.Version, ad.ExpiresAt = 1, domain.StatusActive, 1, tt.expiresAt
+			if err := uc.UpdateAd(asUser(domain.RoleModerator), ad); err != nil {
+				t.Fatal(err)
+			}
+			stored, _ := repo.GetByID(context.Background(), 1)
+			if stored.ExpiresAt == nil || !stored.ExpiresAt.Equal(tt.want) {
+				t.Errorf("expires at %v, want %v", stored.ExpiresAt, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpirationWorkerTakesTurns(t *testing.T) {
+	repo := newFakeAdRepo()
+	uc, server := newTestAdUseCaseWithCache(t, repo, testConfig())
+
+	first := NewExpirationWorker(uc, uc.cache, time.Minute)
+	second := NewExpirationWorker(uc, uc.cache, time.Minute)
+	first.runOnce(context.Background())
+	second.runOnce(context.Background())
+	if calls := repo.expirations.Load(); calls != 1 {
+		t.Fatalf("ads expired %d times in one interval, want 1", calls)
+	}
+
+	server.FastForward(time.Minute)
+	second.runOnce(context.Background())
+	if calls := repo.expirations.Load(); calls != 2 {
+		t.Errorf("ads expired %d times after the interval, want 2", calls)
+	}
+}
+
+func TestListingCacheExpiresWithFirstAd(t *testing.T) {
+	soon := time.Now().Add(30 * time.Second)
+	past := time.Now().Add(-time.Second)
+
+	tests := []struct {
+		name      string
+		expiresAt *time.Time
+		// maxTTL is the longest the listing may be cached, 0 for not cached
+		maxTTL time.Duration
+	}{
+		{"no expiry", nil, time.Hour},
+		{"expires soon", &soon, 30 * time.Second},
+		{"already expired", &past, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeAdRepo(
+				domain.Ad{ID: 1, Status: domain.StatusActive, ExpiresAt: tt.expiresAt},
+				domain.Ad{ID: 2, Status: domain.StatusActive},
+			)
+			uc, server := newTestAdUseCaseWithCache(t, repo, testConfig())
+			if _, err := uc.GetAds(context.Background(), domain.FilterRequest{Lang: "en"}); err != nil {
+				t.Fatal(err)
+			}
+
+			var keys []string
+			for _, key := range server.Keys() {
+				if strings.HasPrefix(key, adsCachePrefix) && strings.Contains(key, ":filter:") {
+					keys = append(keys, key)
+				}
+			}
+			if tt.maxTTL == 0 {
+				if len(keys) != 0 {
+					t.Errorf("listing with an expired ad cached as %v", keys)
+				}
+				return
+			}
+			if len(keys) != 1 {
+				t.Fatalf("cached listings %v, want one", keys)
+			}
+			if ttl := server.TTL(keys[0]); ttl <= 0 || ttl > tt.maxTTL {
+				t.Errorf("listing cached for %v, want at most %v", ttl, tt.maxTTL)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/1way-market/v3/internal/config"
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/1way-market/v3/internal/slug"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+// fakeAdRepo keeps ads in memory. It implements the methods the tests
+// exercise; calling any other method panics on the nil embedded interface.
+type fakeAdRepo struct {
+	AdRepository
+
+	mu  sync.Mutex
+	ads map[uint]*domain.Ad
+	// page is the result of FindWithFilter
+	page []domain.Ad
+	// expiring are the ads the next ExpireAds completes
+	expiring []domain.Ad
+	// expirations counts the calls to ExpireAds
+	expirations atomic.Int64
+	// duplicateOf is the result of FindDuplicate, failing with duplicateErr
+	duplicateOf  *uint
+	duplicateErr error
+	// rebuildErr fails RebuildSearchVectors once rebuilt holds rebuildFailAt IDs
+	rebuildErr    error
+	rebuildFailAt int
+	rebuilt       []uint
+
+	// finds counts the calls to FindWithFilter
+	finds atomic.Int64
+	// findGate, when set, holds FindWithFilter until it is closed
+	findGate chan struct{}
+	// filters records the filters FindWithFilter was called with
+	filters []domain.FilterRequest
+	// creates counts the calls to Create
+	creates atomic.Int64
+	nextID  uint
+}
+
+func newFakeAdRepo(ads ...domain.Ad) *fakeAdRepo {
+	repo := &fakeAdRepo{ads: make(map[uint]*domain.Ad)}
+	for i := range ads {
+		ad := ads[i]
+		repo.ads[ad.ID] = &ad
+		repo.page = append(repo.page, ad)
+	}
+	return repo
+}
+
+func (r *fakeAdRepo) FindWithFilter(ctx context.Context, filter domain.FilterRequest) (*domain.PaginatedResponse, error) {
+	r.finds.Add(1)
+	if r.findGate != nil {
+		<-r.findGate
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.filters = append(r.filters, filter)
+	items := append([]domain.Ad(nil), r.page...)
+	return &domain.PaginatedResponse{Items: items, TotalCount: int64(len(items)), PageSize: filter.PageSize}, nil
+}
+
+func (r *fakeAdRepo) Create(ctx context.Context, ad *domain.Ad) error {
+	r.creates.Add(1)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	for r.ads[r.nextID] != nil {
+		r.nextID++
+	}
+	ad.ID = r.nextID
+	ad.Slug = slug.ForAd(ad)
+	ad.Version = 1
+	saved := *ad
+	r.ads[ad.ID] = &saved
+	return nil
+}
+
+// CreateBatch creates the ads, failing the ones titled "rejected" as the
+// database would
+func (r *fakeAdRepo) CreateBatch(ctx context.Context, ads []*domain.Ad) ([]error, error) {
+	errs := make([]error, len(ads))
+	for i, ad := range ads {
+		if ad.Title.GetText(domain.LangEnglish) == "rejected" {
+			errs[i] = domain.ErrConstraint
+			continue
+		}
+		if err := r.Create(ctx, ad); err != nil {
+			return nil, err
+		}
+	}
+	return errs, nil
+}
+
+func (r *fakeAdRepo) Delete(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.ads[id]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.ads, id)
+	return nil
+}
+
+func (r *fakeAdRepo) Update(ctx context.Context, ad *domain.Ad) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, ok := r.ads[ad.ID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	if existing.Version != ad.Version {
+		return domain.ErrConflict
+	}
+	ad.Version++
+	saved := *ad
+	r.ads[ad.ID] = &saved
+	return nil
+}
+
+func (r *fakeAdRepo) GetByID(ctx context.Context, id uint) (*domain.Ad, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ad, ok := r.ads[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	found := *ad
+	return &found, nil
+}
+
+func (r *fakeAdRepo) ExpireAds(ctx context.Context, limit int) ([]domain.Ad, error) {
+	r.expirations.Add(1)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	expired := r.expiring[:min(limit, len(r.expiring))]
+	r.expiring = r.expiring[len(expired):]
+	for i := range expired {
+		expired[i].Status = domain.StatusCompleted
+	}
+	return expired, nil
+}
+
+func (r *fakeAdRepo) RenewAd(ctx context.Context, id uint, lifetime time.Duration) (*domain.Ad, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ad, ok := r.ads[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	expiresAt := time.Now().Add(lifetime)
+	ad.Status, ad.ExpiresAt = domain.StatusActive, &expiresAt
+	ad.Version++
+	renewed := *ad
+	return &renewed, nil
+}
+
+func (r *fakeAdRepo) FindDuplicate(ctx context.Context, ad *domain.Ad, window time.Duration, threshold float64) (*uint, error) {
+	return r.duplicateOf, r.duplicateErr
+}
+
+func (r *fakeAdRepo) CountByStatus(ctx context.Context) (map[domain.AdStatus]int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts := make(map[domain.AdStatus]int64)
+	for _, ad := range r.ads {
+		counts[ad.Status]++
+	}
+	return counts, nil
+}
+
+func (r *fakeAdRepo) RebuildSearchVectors(ctx context.Context, afterID uint, limit int) ([]uint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.rebuildErr != nil && len(r.rebuilt) >= r.rebuildFailAt {
+		return nil, r.rebuildErr
+	}
+	var ids []uint
+	for id := range r.ads {
+		if id > afterID {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	ids = ids[:min(limit, len(ids))]
+	r.rebuilt = append(r.rebuilt, ids...)
+	return ids, nil
+}
+
+func (r *fakeAdRepo) UpdateImages(ctx context.Context, id uint, change func(domain.Images) (domain.Images, error)) (domain.Images, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ad, ok := r.ads[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	images, err := change(slices.Clone(ad.Images))
+	if err != nil {
+		return nil, err
+	}
+	ad.Images = images
+	ad.Version++
+	return images, nil
+}
+
+// newTestCache returns a Redis client backed by an in-memory server that
+// lives as long as the test
+func newTestCache(t testing.TB) (*redis.Client, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return client, server
+}
+
+// testConfig returns the configuration the use cases are tested with
+func testConfig() *config.Config {
+	return &config.Config{
+		CacheCodec:         "json",
+		RedisCacheTimeout:  time.Second,
+		AdsCacheSoftTTL:    time.Minute,
+		AdsCacheHardTTL:    time.Hour,
+		AdCacheTTL:         time.Hour,
+		AdNotFoundCacheTTL: time.Minute,
+		MaxPageSize:        100,
+		AdLifetime:         30 * 24 * time.Hour,
+		DefaultVisibleStatuses: []domain.AdStatus{
+			domain.StatusActive,
+			domain.StatusApproved,
+		},
+	}
+}
+
+// newTestAdUseCase returns an ad use case over repo and an in-memory cache
+func newTestAdUseCase(t testing.TB, repo AdRepository, cfg *config.Config) *AdUseCase {
+	t.Helper()
+	uc, _ := newTestAdUseCaseWithCache(t, repo, cfg)
+	return uc
+}
+
+// newTestAdUseCaseWithCache is newTestAdUseCase also returning the cache
+// server, e.g. to inspect the cached keys
+func newTestAdUseCaseWithCache(t testing.TB, repo AdRepository, cfg *config.Config) (*AdUseCase, *miniredis.Miniredis) {
+	t.Helper()
+	cache, server := newTestCache(t)
+	return NewAdUseCase(repo, nil, nil, &fakeFavorites{}, nil, nil, nil, cache, cfg), server
+}
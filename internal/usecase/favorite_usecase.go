@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/1way-market/v3/internal/config"
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/go-redis/redis/v8"
+)
+
+type FavoriteRepository interface {
+	Add(ctx context.Context, userID string, adID uint) (bool, error)
+	Remove(ctx context.Context, userID string, adID uint) (bool, error)
+	CountByAd(ctx context.Context, adID uint) (int64, error)
+	FavoritedAmong(ctx context.Context, userID string, adIDs []uint) ([]uint, error)
+	ListAds(ctx context.Context, userID string, pageSize int, pageToken string) (*domain.PaginatedResponse, error)
+}
+
+// adFavoritesKey is the cached number of users who favorited an ad
+func adFavoritesKey(adID uint) string {
+	return fmt.Sprintf("ad:favorites:%d", adID)
+}
+
+type FavoriteUseCase struct {
+	repo   FavoriteRepository
+	adRepo AdRepository
+	cache  *redis.Client
+	cfg    *config.Config
+}
+
+func NewFavoriteUseCase(repo FavoriteRepository, adRepo AdRepository, cache *redis.Client, cfg *config.Config) *FavoriteUseCase {
+	return &FavoriteUseCase{
+		repo:   repo,
+		adRepo: adRepo,
+		cache:  cache,
+		cfg:    cfg,
+	}
+}
+
+func (uc *FavoriteUseCase) AddFavorite(ctx context.Context, userID string, adID uint) error {
+	if _, err := uc.adRepo.GetByID(ctx, adID); err != nil {
+		return err
+	}
+
+	added, err := uc.repo.Add(ctx, userID, adID)
+	if err != nil {
+		return err
+	}
+	if added {
+		uc.adjustFavoriteCount(ctx, adID, 1)
+	}
+	return nil
+}
+
+func (uc *FavoriteUseCase) RemoveFavorite(ctx context.Context, userID string, adID uint) error {
+	removed, err := uc.repo.Remove(ctx, userID, adID)
+	if err != nil {
+		return err
+	}
+	if removed {
+		uc.adjustFavoriteCount(ctx, adID, -1)
+	}
+	return nil
+}
+
+func (uc *FavoriteUseCase) ListFavorites(ctx context.Context, userID string, pageSize int, pageToken string) (*domain.PaginatedResponse, error) {
+	return uc.repo.ListAds(ctx, userID, pageSize, pageToken)
+}
+
+// adjustFavoriteCount applies a change to the cached favorite count of the ad.
+// A counter that did not exist, e.g. after Redis lost its data, is dropped so
+// that the next read counts from the database.
+func (uc *FavoriteUseCase) adjustFavoriteCount(ctx context.Context, adID uint, delta int64) {
+	ctx, cancel := context.WithTimeout(ctx, uc.cfg.RedisCacheTimeout)
+	defer cancel()
+
+	key := adFavoritesKey(adID)
+	count, err := uc.cache.IncrBy(ctx, key, delta).Result()
+	if err != nil {
+		log.Printf("Warning: favorite count of ad %d not updated: %v", adID, err)
+		return
+	}
+	if count == delta {
+		if err := uc.cache.Del(ctx, key).Err(); err != nil {
+			log.Printf("Warning: cache delete of %s failed: %v", key, err)
+		}
+	}
+}
+
+// GetFavoriteCount returns the number of users who favorited the ad, from the
+// cache when possible
+func (uc *AdUseCase) GetFavoriteCount(ctx context.Context, adID uint) (int64, error) {
+	key := adFavoritesKey(adID)
+	if cachedData, ok := uc.cacheGet(ctx, key); ok {
+		if count, err := strconv.ParseInt(string(cachedData), 10, 64); err == nil {
+			return count, nil
+		}
+	}
+
+	count, err := uc.favorites.CountByAd(ctx, adID)
+	if err != nil {
+		return 0, err
+	}
+	// The TTL bounds the drift from a favorite added while the count was read
+	uc.cacheSet(ctx, key, count, uc.cfg.AdCacheTTL)
+	return count, nil
+}
+
+// GetFavorited returns which of the ads the caller favorited, or nil for
+// anonymous callers
+func (uc *AdUseCase) GetFavorited(ctx context.Context, adIDs []uint) (map[uint]bool, error) {
+	principal := domain.PrincipalFromContext(ctx)
+	if principal == nil {
+		return nil, nil
+	}
+
+	ids, err := uc.favorites.FavoritedAmong(ctx, principal.UserID, adIDs)
+	if err != nil {
+		return nil, err
+	}
+	favorited := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		favorited[id] = true
+	}
+	return favorited, nil
+}
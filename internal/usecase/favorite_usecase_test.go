@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/1way-market/v3/internal/domain"
+)
+
+// fakeFavorites holds the IDs of the ads favorited, by user
+type fakeFavorites struct {
+	byUser map[string][]uint
+	// lookups counts the calls to FavoritedAmong
+	lookups int
+}
+
+func (f *fakeFavorites) CountByAd(ctx context.Context, adID uint) (int64, error) {
+	var count int64
+	for _, ids := range f.byUser {
+		if slices.Contains(ids, adID) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (f *fakeFavorites) FavoritedAmong(ctx context.Context, userID string, adIDs []uint) ([]uint, error) {
+	f.lookups++
+	var ids []uint
+	for _, id := range f.byUser[userID] {
+		if slices.Contains(adIDs, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func TestGetFavorited(t *testing.T) {
+	favorites := &fakeFavorites{byUser: map[string][]uint{"user-1": {2, 4, 9}}}
+	uc := &AdUseCase{favorites: favorites}
+	page := []uint{1, 2, 3, 4}
+
+	favorited, err := uc.GetFavorited(asUser(domain.RoleSeller), page)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[uint]bool{2: true, 4: true}; !maps.Equal(favorited, want) {
+		t.Errorf("favorited = %v, want %v", favorited, want)
+	}
+	if favorites.lookups != 1 {
+		t.Errorf("page loaded in %d lookups, want 1", favorites.lookups)
+	}
+
+	favorited, err = uc.GetFavorited(context.Background(), page)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if favorited != nil {
+		t.Errorf("anonymous favorited = %v, want nil", favorited)
+	}
+	if favorites.lookups != 1 {
+		t.Errorf("anonymous caller looked up favorites")
+	}
+}
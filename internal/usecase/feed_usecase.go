@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/1way-market/v3/internal/config"
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// feedSize is the number of most recent ads included in a feed
+	feedSize = 50
+	// feedDescriptionLength is the maximum item description length in characters
+	feedDescriptionLength = 200
+	// feedCacheTTL is how long a rendered feed is served from cache
+	feedCacheTTL = 5 * time.Minute
+)
+
+type FeedUseCase struct {
+	repo  AdRepository
+	cache *redis.Client
+	cfg   *config.Config
+}
+
+func NewFeedUseCase(repo AdRepository, cache *redis.Client, cfg *config.Config) *FeedUseCase {
+	return &FeedUseCase{
+		repo:  repo,
+		cache: cache,
+		cfg:   cfg,
+	}
+}
+
+// AdsRSS renders the RSS 2.0 feed of the newest active ads in a category.
+// A zero categoryID includes all categories.
+func (uc *FeedUseCase) AdsRSS(ctx context.Context, categoryID int, lang domain.Language) ([]byte, error) {
+	cacheKey := fmt.Sprintf("feed:rss:%d:%d", categoryID, lang)
+	if cached, err := uc.cache.Get(ctx, cacheKey).Bytes(); err == nil {
+		return cached, nil
+	}
+
+	status := domain.StatusActive
+	filter := domain.FilterRequest{
+		Status:   &status,
+		PageSize: feedSize,
+		Language: lang,
+	}
+	if categoryID != 0 {
+		filter.CategoryIDs = []int{categoryID}
+	}
+
+	response, err := uc.repo.FindWithFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := strings.TrimRight(uc.cfg.SiteBaseURL, "/")
+	feed := domain.RSS{
+		Version: "2.0",
+		Channel: domain.RSSChannel{
+			Title:       "New ads",
+			Link:        baseURL + "/",
+			Description: "The most recent ads",
+			Language:    lang.Code(),
+			Items:       make([]domain.RSSItem, 0, len(response.Items)),
+		},
+	}
+	if categoryID != 0 {
+		feed.Channel.Title = fmt.Sprintf("New ads in category %d", categoryID)
+		feed.Channel.Description = fmt.Sprintf("The most recent ads in category %d", categoryID)
+	}
+
+	for _, ad := range response.Items {
+		link := fmt.Sprintf("%s/ads/%d", baseURL, ad.ID)
+		feed.Channel.Items = append(feed.Channel.Items, domain.RSSItem{
+			Title:       ad.Title.GetText(lang),
+			Link:        link,
+			Description: truncate(ad.Description.GetText(lang), feedDescriptionLength),
+			PubDate:     ad.CreatedAt.Format(time.RFC1123Z),
+			GUID:        domain.RSSGUID{Value: link, IsPermaLink: true},
+		})
+	}
+
+	body, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error rendering feed: %v", err)
+	}
+	body = append([]byte(xml.Header), body...)
+
+	uc.cache.Set(ctx, cacheKey, body, feedCacheTTL)
+	return body, nil
+}
+
+// truncate shortens s to at most limit characters
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit])
+}
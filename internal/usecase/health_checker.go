@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/go-redis/redis/v8"
+)
+
+// healthCheckTimeout bounds each dependency check
+const healthCheckTimeout = 2 * time.Second
+
+// HealthChecker checks connectivity to PostgreSQL and Redis
+type HealthChecker struct {
+	db    *sql.DB
+	cache *redis.Client
+}
+
+func NewHealthChecker(db *sql.DB, cache *redis.Client) *HealthChecker {
+	return &HealthChecker{
+		db:    db,
+		cache: cache,
+	}
+}
+
+// Check pings both dependencies. The service is unhealthy without the
+// database and degraded without Redis, which only serves caches.
+func (h *HealthChecker) Check(ctx context.Context) domain.HealthReport {
+	report := domain.HealthReport{
+		DB: checkDependency(ctx, func(ctx context.Context) error {
+			if h.db == nil {
+				return errors.New("not configured")
+			}
+			return h.db.PingContext(ctx)
+		}),
+		Redis: checkDependency(ctx, func(ctx context.Context) error {
+			if h.cache == nil {
+				return errors.New("not configured")
+			}
+			return h.cache.Ping(ctx).Err()
+		}),
+	}
+
+	switch {
+	case report.DB.Status != domain.HealthUp:
+		report.Overall = domain.HealthUnhealthy
+	case report.Redis.Status != domain.HealthUp:
+		report.Overall = domain.HealthDegraded
+	default:
+		report.Overall = domain.HealthHealthy
+	}
+	return report
+}
+
+// checkDependency runs ping with a timeout and measures its latency
+func checkDependency(ctx context.Context, ping func(ctx context.Context) error) domain.DependencyHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := ping(ctx)
+	health := domain.DependencyHealth{
+		Status:    domain.HealthUp,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		health.Status = domain.HealthDown
+		health.Error = err.Error()
+	}
+	return health
+}
@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"log"
+	"net/http"
+	"slices"
+
+	"github.com/1way-market/v3/internal/domain"
+)
+
+// BlobStorage keeps the uploaded images of ads
+type BlobStorage interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// imageExtensions are the accepted image content types and the extensions of their keys
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// imageObjectKey is the storage key of an image of an ad
+func imageObjectKey(adID uint, key string) string {
+	return fmt.Sprintf("ads/%d/%s", adID, key)
+}
+
+// AddImage stores an uploaded image and appends it to the ad's images. The
+// content type is sniffed from the data rather than trusted from the client.
+func (uc *AdUseCase) AddImage(ctx context.Context, adID uint, data []byte) (*domain.Image, error) {
+	if uc.blobs == nil {
+		return nil, domain.ErrStorageUnavailable
+	}
+	if int64(len(data)) > uc.cfg.MaxImageSize {
+		return nil, domain.ErrImageTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := imageExtensions[contentType]
+	if !ok {
+		return nil, domain.ErrUnsupportedImageType
+	}
+	size, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, domain.ErrUnsupportedImageType
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("error generating image key: %v", err)
+	}
+	key := hex.EncodeToString(token) + ext
+	objectKey := imageObjectKey(adID, key)
+
+	// Check the ad before storing anything for it
+	existing, err := uc.repo.GetByID(ctx, adID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeOwner(ctx, existing); err != nil {
+		return nil, err
+	}
+	if len(existing.Images) >= domain.MaxAdImages {
+		return nil, domain.ErrTooManyImages
+	}
+
+	if err := uc.blobs.Put(ctx, objectKey, contentType, data); err != nil {
+		return nil, err
+	}
+
+	added := domain.Image{Key: key, URL: uc.blobs.URL(objectKey), Width: size.Width, Height: size.Height}
+	images, err := uc.repo.UpdateImages(ctx, adID, func(images domain.Images) (domain.Images, error) {
+		if len(images) >= domain.MaxAdImages {
+			return nil, domain.ErrTooManyImages
+		}
+		images = append(images, added)
+		images.MarkPrimary()
+		return images, nil
+	})
+	if err != nil {
+		uc.deleteImageObjects(ctx, adID, domain.Images{added})
+		return nil, err
+	}
+
+	uc.imagesChanged(ctx, existing)
+	return &images[len(images)-1], nil
+}
+
+// DeleteImage removes the image with the given key from the ad and the storage.
+// When the primary image is removed, the next one becomes primary.
+func (uc *AdUseCase) DeleteImage(ctx context.Context, adID uint, key string) error {
+	if uc.blobs == nil {
+		return domain.ErrStorageUnavailable
+	}
+	if err := uc.authorizeImageChange(ctx, adID); err != nil {
+		return err
+	}
+
+	var removed domain.Image
+	_, err := uc.repo.UpdateImages(ctx, adID, func(images domain.Images) (domain.Images, error) {
+		i := images.Index(key)
+		if i < 0 {
+			return nil, fmt.Errorf("%w: image %s", domain.ErrNotFound, key)
+		}
+		removed = images[i]
+		images = slices.Delete(images, i, i+1)
+		images.MarkPrimary()
+		return images, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	uc.deleteImageObjects(ctx, adID, domain.Images{removed})
+	uc.imagesChanged(ctx, &domain.Ad{ID: adID})
+	return nil
+}
+
+// ReorderImages puts the ad's images in the order of keys, which must list
+// each of them once. The first becomes the primary image.
+func (uc *AdUseCase) ReorderImages(ctx context.Context, adID uint, keys []string) (domain.Images, error) {
+	if err := uc.authorizeImageChange(ctx, adID); err != nil {
+		return nil, err
+	}
+	images, err := uc.repo.UpdateImages(ctx, adID, func(images domain.Images) (domain.Images, error) {
+		if len(keys) != len(images) {
+			return nil, invalidImageOrder()
+		}
+		reordered := make(domain.Images, 0, len(keys))
+		for _, key := range keys {
+			i := images.Index(key)
+			if i < 0 || reordered.Index(key) >= 0 {
+				return nil, invalidImageOrder()
+			}
+			reordered = append(reordered, images[i])
+		}
+		reordered.MarkPrimary()
+		return reordered, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.imagesChanged(ctx, &domain.Ad{ID: adID})
+	return images, nil
+}
+
+// authorizeImageChange checks the ad exists and the caller may change it
+func (uc *AdUseCase) authorizeImageChange(ctx context.Context, adID uint) error {
+	existing, err := uc.repo.GetByID(ctx, adID)
+	if err != nil {
+		return err
+	}
+	return authorizeOwner(ctx, existing)
+}
+
+// listImages keeps only the primary image of each listed ad unless the filter
+// asks for all of them
+func listImages(response *domain.PaginatedResponse, filter domain.FilterRequest) *domain.PaginatedResponse {
+	if filter.IncludeImages != "all" {
+		for i := range response.Items {
+			response.Items[i].Images = response.Items[i].Images.Primary()
+		}
+	}
+	return response
+}
+
+func invalidImageOrder() error {
+	return &domain.ValidationError{Fields: []domain.FieldError{{Field: "keys", Message: "must list each image of the ad once"}}}
+}
+
+// imagesChanged drops the cached copies of an ad whose images changed
+func (uc *AdUseCase) imagesChanged(ctx context.Context, ad *domain.Ad) {
+	uc.invalidateAdsCache(ctx)
+	uc.invalidateAd(ctx, ad.ID)
+	uc.publishInvalidation(ctx, ad.ID, ad)
+}
+
+// deleteImageObjects removes the stored objects of the images, logging
+// failures since the images are already gone from the ad
+func (uc *AdUseCase) deleteImageObjects(ctx context.Context, adID uint, images domain.Images) {
+	if uc.blobs == nil {
+		return
+	}
+	for _, img := range images {
+		if err := uc.blobs.Delete(ctx, imageObjectKey(adID, img.Key)); err != nil {
+			log.Printf("Warning: image %s of ad %d not deleted: %v", img.Key, adID, err)
+		}
+	}
+}
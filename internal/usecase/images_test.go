@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"sync"
+	"testing"
+
+	"github.com/1way-market/v3/internal/domain"
+)
+
+// fakeBlobs keeps stored objects in memory, by key
+type fakeBlobs struct {
+	mu      sync.Mutex
+	objects map[string]string
+}
+
+func (b *fakeBlobs) Put(ctx context.Context, key, contentType string, data []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.objects[key] = contentType
+	return nil
+}
+
+func (b *fakeBlobs) Delete(ctx context.Context, key string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.objects, key)
+	return nil
+}
+
+func (b *fakeBlobs) URL(key string) string {
+	return "https://cdn.example.com/" + key
+}
+
+func newImageTestAdUseCase(t *testing.T) (*AdUseCase, *fakeBlobs) {
+	t.Helper()
+	cfg := testConfig()
+	cfg.MaxImageSize = 1 << 20
+	uc := newTestAdUseCase(t, newFakeAdRepo(domain.Ad{ID: 1, OwnerID: "user-1"}), cfg)
+	blobs := &fakeBlobs{objects: make(map[string]string)}
+	uc.blobs = blobs
+	return uc, blobs
+}
+
+func pngImage(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestAddImage(t *testing.T) {
+	uc, blobs := newImageTestAdUseCase(t)
+	ctx := asUser()
+
+	first, err := uc.AddImage(ctx, 1, pngImage(t, 3, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	objectKey := imageObjectKey(1, first.Key)
+	if contentType := blobs.objects[objectKey]; contentType != "image/png" {
+		t.Errorf("stored %s as %q, want image/png", objectKey, contentType)
+	}
+	if first.URL != blobs.URL(objectKey) || first.Width != 3 || first.Height != 2 || !first.IsPrimary {
+		t.Errorf("first image = %+v", first)
+	}
+
+	second, err := uc.AddImage(ctx, 1, pngImage(t, 1, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.IsPrimary {
+		t.Error("second image made primary")
+	}
+}
+
+func TestAddImageRejectsInvalidUploads(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"text", []byte("not an image"), domain.ErrUnsupportedImageType},
+		{"truncated png", pngImage(t, 3, 2)[:20], domain.ErrUnsupportedImageType},
+		{"too large", append(pngImage(t, 3, 2), make([]byte, 1<<20)...), domain.ErrImageTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, blobs := newImageTestAdUseCase(t)
+			if _, err := uc.AddImage(asUser(), 1, tt.data); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+			if len(blobs.objects) != 0 {
+				t.Errorf("stored %v for a rejected upload", blobs.objects)
+			}
+		})
+	}
+}
+
+func TestDeleteImageRemovesObject(t *testing.T) {
+	uc, blobs := newImageTestAdUseCase(t)
+	ctx := asUser()
+	first, err := uc.AddImage(ctx, 1, pngImage(t, 3, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := uc.AddImage(ctx, 1, pngImage(t, 1, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := uc.DeleteImage(ctx, 1, first.Key); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := blobs.objects[imageObjectKey(1, first.Key)]; ok {
+		t.Error("object of the deleted image kept")
+	}
+	ad, _ := uc.repo.GetByID(ctx, 1)
+	if len(ad.Images) != 1 || ad.Images[0].Key != second.Key || !ad.Images[0].IsPrimary {
+		t.Errorf("images after delete = %+v, want %s as primary", ad.Images, second.Key)
+	}
+
+	if err := uc.DeleteImage(ctx, 1, first.Key); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("deleting again: error = %v, want %v", err, domain.ErrNotFound)
+	}
+}
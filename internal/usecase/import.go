@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/1way-market/v3/internal/domain"
+)
+
+// ImportAds creates a batch of ads in one transaction and returns the error of
+// each ad at its index, nil for the ads created. An invalid ad or one the
+// database rejects does not keep the others from being created.
+func (uc *AdUseCase) ImportAds(ctx context.Context, ads []*domain.Ad) []error {
+	errs := make([]error, len(ads))
+	valid := make([]*domain.Ad, 0, len(ads))
+	positions := make([]int, 0, len(ads))
+	for i, ad := range ads {
+		if err := uc.prepareNewAd(ctx, ad); err != nil {
+			errs[i] = err
+			continue
+		}
+		valid = append(valid, ad)
+		positions = append(positions, i)
+	}
+	if len(valid) == 0 {
+		return errs
+	}
+
+	createErrs, err := uc.repo.CreateBatch(ctx, valid)
+	if err != nil {
+		for _, i := range positions {
+			errs[i] = err
+		}
+		return errs
+	}
+
+	deltas := make(map[int]int64)
+	for j, ad := range valid {
+		if createErrs[j] != nil {
+			errs[positions[j]] = createErrs[j]
+			continue
+		}
+		addCategoryDeltas(deltas, ad, 1)
+		uc.syncSearchIndex(ctx, ad.ID)
+		uc.cacheAd(ctx, ad)
+		uc.publishInvalidation(ctx, ad.ID, ad)
+	}
+	uc.adjustCategoryCounts(ctx, deltas)
+	uc.invalidateAdsCache(ctx)
+	return errs
+}
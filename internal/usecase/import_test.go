@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/1way-market/v3/internal/domain"
+)
+
+func TestImportAdsReportsEachAd(t *testing.T) {
+	repo := newFakeAdRepo()
+	uc := newTestAdUseCase(t, repo, testConfig())
+
+	invalid := newSellerAd()
+	invalid.Title = nil
+	rejected := newSellerAd()
+	rejected.Title = domain.MultiLangArray{{Lang: domain.LangEnglish, Text: "rejected"}}
+	ads := []*domain.Ad{newSellerAd(), invalid, rejected, newSellerAd()}
+
+	errs := uc.ImportAds(asUser(domain.RoleParser), ads)
+	var validationErr *domain.ValidationError
+	if errs[0] != nil || !errors.As(errs[1], &validationErr) || !errors.Is(errs[2], domain.ErrConstraint) || errs[3] != nil {
+		t.Fatalf("errors = %v, want nil, a validation error, %v and nil", errs, domain.ErrConstraint)
+	}
+	for _, ad := range []*domain.Ad{ads[0], ads[3]} {
+		stored, err := repo.GetByID(context.Background(), ad.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored.OwnerID != "user-1" || stored.Slug == "" {
+			t.Errorf("imported ad %d has owner %q and slug %q", ad.ID, stored.OwnerID, stored.Slug)
+		}
+	}
+}
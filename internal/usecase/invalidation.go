@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/1way-market/v3/internal/domain"
+)
+
+// adsInvalidateChannel is the Redis pub/sub channel announcing ad changes to
+// every API instance. Messages may be lost, so the cache TTLs stay the backstop.
+const adsInvalidateChannel = "ads:invalidate"
+
+// adInvalidation is published on adsInvalidateChannel when an ad changes
+type adInvalidation struct {
+	AdID        uint  `json:"ad_id"`
+	CategoryIDs []int `json:"category_ids,omitempty"`
+}
+
+// publishInvalidation announces a change of the ad to all instances, including this one
+func (uc *AdUseCase) publishInvalidation(ctx context.Context, id uint, ads ...*domain.Ad) {
+	message := adInvalidation{AdID: id}
+	for _, ad := range ads {
+		if ad != nil {
+			message.CategoryIDs = append(message.CategoryIDs, ad.CategoryIDs...)
+		}
+	}
+	data, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := uc.cacheContext(ctx)
+	defer cancel()
+
+	if err := uc.cache.Publish(ctx, adsInvalidateChannel, data).Err(); err != nil {
+		log.Printf("Warning: publishing invalidation of ad %d failed: %v", id, err)
+	}
+}
+
+// ListenInvalidations applies ad changes announced by any instance to the
+// state kept by this one until ctx is cancelled
+func (uc *AdUseCase) ListenInvalidations(ctx context.Context) {
+	pubsub := uc.cache.Subscribe(ctx, adsInvalidateChannel)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			var invalidation adInvalidation
+			if err := json.Unmarshal([]byte(msg.Payload), &invalidation); err != nil {
+				log.Printf("Warning: invalid ads invalidation message %q: %v", msg.Payload, err)
+				continue
+			}
+			uc.invalidateLocal(invalidation)
+		}
+	}
+}
+
+// invalidateLocal drops per-instance state that may predate the change.
+// The Redis entries are shared and already invalidated by the publisher.
+func (uc *AdUseCase) invalidateLocal(invalidation adInvalidation) {
+	// Listings loading when the ad changed must not be shared with later requests
+	uc.flights.ForgetAll()
+}
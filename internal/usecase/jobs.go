@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/go-redis/redis/v8"
+)
+
+// jobTTL is how long the progress of a job stays queryable after its last update
+const jobTTL = 24 * time.Hour
+
+// jobKey is the cache key of the progress of a job
+func jobKey(id string) string {
+	return "job:" + id
+}
+
+// StartSearchVectorRebuild starts recomputing the search vector of every ad,
+// batchSize ads at a time, and returns the job reporting its progress. The
+// job runs in the background on this instance; its progress is kept in Redis
+// so that any instance can report it.
+func (uc *AdUseCase) StartSearchVectorRebuild(ctx context.Context, batchSize int) (*domain.Job, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("error generating job id: %v", err)
+	}
+
+	counts, err := uc.repo.CountByStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	job := &domain.Job{
+		ID:        hex.EncodeToString(token),
+		Type:      domain.JobSearchVectorRebuild,
+		Status:    domain.JobRunning,
+		StartedAt: time.Now().UTC(),
+	}
+	for _, count := range counts {
+		job.Total += count
+	}
+	if err := uc.saveJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	// The job outlives the request that started it
+	started := *job
+	go uc.rebuildSearchVectors(context.WithoutCancel(ctx), job, batchSize)
+	return &started, nil
+}
+
+// rebuildSearchVectors runs a search vector rebuild job, recording its
+// progress after every batch. Ads are walked in ID order, so ads created
+// while it runs are included; they get their vector when written anyway.
+func (uc *AdUseCase) rebuildSearchVectors(ctx context.Context, job *domain.Job, batchSize int) {
+	var afterID uint
+	for {
+		ids, err := uc.repo.RebuildSearchVectors(ctx, afterID, batchSize)
+		if err != nil {
+			job.Status = domain.JobFailed
+			job.Error = err.Error()
+			break
+		}
+		if len(ids) == 0 {
+			job.Status = domain.JobCompleted
+			break
+		}
+
+		afterID = ids[len(ids)-1]
+		job.Processed += int64(len(ids))
+		if err := uc.saveJob(ctx, job); err != nil {
+			log.Printf("Warning: progress of job %s not saved: %v", job.ID, err)
+		}
+	}
+
+	finishedAt := time.Now().UTC()
+	job.FinishedAt = &finishedAt
+	if err := uc.saveJob(ctx, job); err != nil {
+		log.Printf("Warning: result of job %s not saved: %v", job.ID, err)
+	}
+	log.Printf("Job %s (%s) %s after %d of %d items", job.ID, job.Type, job.Status, job.Processed, job.Total)
+}
+
+// saveJob records the progress of a job
+func (uc *AdUseCase) saveJob(ctx context.Context, job *domain.Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := uc.cacheContext(ctx)
+	defer cancel()
+	return uc.cache.Set(ctx, jobKey(job.ID), data, jobTTL).Err()
+}
+
+// GetJob returns the progress of the job with the given ID, or
+// domain.ErrNotFound once it has expired
+func (uc *AdUseCase) GetJob(ctx context.Context, id string) (*domain.Job, error) {
+	ctx, cancel := uc.cacheContext(ctx)
+	defer cancel()
+
+	data, err := uc.cache.Get(ctx, jobKey(id)).Bytes()
+	if err == redis.Nil {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var job domain.Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("invalid job record: %v", err)
+	}
+	return &job, nil
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/1way-market/v3/internal/domain"
+)
+
+// waitForJob polls the job until it is no longer running
+func waitForJob(t *testing.T, uc *AdUseCase, id string) *domain.Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, err := uc.GetJob(context.Background(), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.Status != domain.JobRunning {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job still running: %+v", job)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func newRebuildRepo(count int) *fakeAdRepo {
+	ads := make([]domain.Ad, count)
+	for i := range ads {
+		ads[i] = domain.Ad{ID: uint(i + 1), Status: domain.StatusActive}
+	}
+	return newFakeAdRepo(ads...)
+}
+
+func TestSearchVectorRebuildCompletes(t *testing.T) {
+	repo := newRebuildRepo(7)
+	uc, server := newTestAdUseCaseWithCache(t, repo, testConfig())
+
+	started, err := uc.StartSearchVectorRebuild(context.Background(), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if started.Status != domain.JobRunning || started.Total != 7 || started.Type != domain.JobSearchVectorRebuild {
+		t.Errorf("started job = %+v", started)
+	}
+
+	job := waitForJob(t, uc, started.ID)
+	if job.Status != domain.JobCompleted || job.Processed != 7 || job.FinishedAt == nil {
+		t.Errorf("finished job = %+v, want 7 ads processed", job)
+	}
+	if len(repo.rebuilt) != 7 {
+		t.Errorf("rebuilt %v, want every ad once", repo.rebuilt)
+	}
+	if ttl := server.TTL(jobKey(job.ID)); ttl != jobTTL {
+		t.Errorf("job kept for %v, want %v", ttl, jobTTL)
+	}
+
+	server.FastForward(jobTTL)
+	if _, err := uc.GetJob(context.Background(), job.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expired job error = %v, want %v", err, domain.ErrNotFound)
+	}
+}
+
+func TestSearchVectorRebuildFails(t *testing.T) {
+	repo := newRebuildRepo(7)
+	repo.rebuildErr, repo.rebuildFailAt = errors.New("statement timeout"), 3
+	uc := newTestAdUseCase(t, repo, testConfig())
+
+	started, err := uc.StartSearchVectorRebuild(context.Background(), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	job := waitForJob(t, uc, started.ID)
+	if job.Status != domain.JobFailed || job.Processed != 3 || job.Error != "statement timeout" {
+		t.Errorf("failed job = %+v, want failed after 3 ads", job)
+	}
+}
+
+func TestGetUnknownJob(t *testing.T) {
+	uc := newTestAdUseCase(t, newFakeAdRepo(), testConfig())
+	if _, err := uc.GetJob(context.Background(), "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("error = %v, want %v", err, domain.ErrNotFound)
+	}
+}
@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/1way-market/v3/internal/config"
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/1way-market/v3/internal/events"
+	"github.com/go-redis/redis/v8"
+)
+
+// EventPublisher delivers ad events to the message broker. Events may be
+// delivered more than once, so consumers deduplicate them by sequence.
+type EventPublisher interface {
+	Publish(ctx context.Context, event domain.OutboxEvent) error
+}
+
+// OutboxRepository hands out the events recorded in the outbox
+type OutboxRepository interface {
+	RelayUnsent(ctx context.Context, limit int, publish func([]domain.OutboxEvent) (int, error)) (int, error)
+	DeleteSentBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// outboxBatchSize is the number of events relayed per outbox transaction
+const outboxBatchSize = 100
+
+// OutboxRelay publishes the ad events recorded in the outbox
+type OutboxRelay struct {
+	repo      OutboxRepository
+	publisher EventPublisher
+	interval  time.Duration
+	retention time.Duration
+}
+
+func NewOutboxRelay(repo OutboxRepository, publisher EventPublisher, interval, retention time.Duration) *OutboxRelay {
+	return &OutboxRelay{
+		repo:      repo,
+		publisher: publisher,
+		interval:  interval,
+		retention: retention,
+	}
+}
+
+// Run relays the outbox every interval until ctx is done, then closes the
+// publisher's connections. Events left unsent on shutdown stay in the outbox
+// for the next run.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	if closer, ok := r.publisher.(io.Closer); ok {
+		defer func() {
+			if err := closer.Close(); err != nil {
+				log.Printf("Warning: closing the event publisher failed: %v", err)
+			}
+		}()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Relay(ctx); err != nil {
+				log.Printf("Warning: outbox relay failed: %v", err)
+			}
+			if _, err := r.repo.DeleteSentBefore(ctx, time.Now().Add(-r.retention)); err != nil {
+				log.Printf("Warning: outbox cleanup failed: %v", err)
+			}
+		}
+	}
+}
+
+// Relay publishes the unsent events in sequence order until the outbox is
+// drained. It stops at the first event that fails to publish, which is
+// retried on the next call.
+func (r *OutboxRelay) Relay(ctx context.Context) error {
+	for {
+		sent, err := r.repo.RelayUnsent(ctx, outboxBatchSize, func(events []domain.OutboxEvent) (int, error) {
+			for i, event := range events {
+				if err := r.publisher.Publish(ctx, event); err != nil {
+					return i, fmt.Errorf("error publishing event %d: %v", event.Sequence, err)
+				}
+			}
+			return len(events), nil
+		})
+		if err != nil || sent < outboxBatchSize {
+			return err
+		}
+	}
+}
+
+// eventStreamMaxLen caps, approximately, the entries kept in the event stream
+const eventStreamMaxLen = 100000
+
+// RedisStreamPublisher appends ad events to a Redis stream
+type RedisStreamPublisher struct {
+	client *redis.Client
+	stream string
+}
+
+func NewRedisStreamPublisher(client *redis.Client, stream string) *RedisStreamPublisher {
+	return &RedisStreamPublisher{client: client, stream: stream}
+}
+
+// Publish adds the event to the stream with its type and sequence as fields
+// of their own, so that consumers can filter without decoding the event
+func (p *RedisStreamPublisher) Publish(ctx context.Context, event domain.OutboxEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error encoding event: %v", err)
+	}
+	return p.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: p.stream,
+		MaxLen: eventStreamMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{
+			"type":     event.Type,
+			"sequence": event.Sequence,
+			"event":    data,
+		},
+	}).Err()
+}
+
+// LogPublisher writes ad events to the log, for development
+type LogPublisher struct{}
+
+func (LogPublisher) Publish(ctx context.Context, event domain.OutboxEvent) error {
+	log.Printf("Event %d: %s of ad %d", event.Sequence, event.Type, event.AdID)
+	return nil
+}
+
+// newEventPublisher returns the publisher configured by EVENT_PUBLISHER, or nil
+// when ad events are not published
+func newEventPublisher(cfg *config.Config, redisClient *redis.Client) EventPublisher {
+	switch cfg.EventPublisher {
+	case "redis":
+		if redisClient == nil {
+			log.Printf("Warning: Redis is unavailable, ad events are not published")
+			return nil
+		}
+		return NewRedisStreamPublisher(redisClient, cfg.EventStream)
+	case "nats":
+		publisher, err := events.NewNATS(cfg.NATSURL, cfg.EventStream)
+		if err != nil {
+			log.Printf("Warning: %v, ad events are not published", err)
+			return nil
+		}
+		return publisher
+	case "kafka":
+		return events.NewKafka(cfg.KafkaBrokers, cfg.EventStream)
+	case "log":
+		return LogPublisher{}
+	default:
+		return nil
+	}
+}
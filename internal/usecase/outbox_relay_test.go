@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/1way-market/v3/internal/domain"
+)
+
+// fakeOutbox keeps events in memory like outbox_events. Events are marked
+// sent only when the relaying transaction commits: a publish that panics, as
+// a crashing process would, or a failed commit leaves them unsent.
+type fakeOutbox struct {
+	mu     sync.Mutex
+	events []domain.OutboxEvent
+	sent   map[int64]bool
+	// failCommits is the number of relaying transactions left to fail
+	failCommits int
+}
+
+func newFakeOutbox(n int) *fakeOutbox {
+	outbox := &fakeOutbox{sent: make(map[int64]bool)}
+	for i := 1; i <= n; i++ {
+		outbox.events = append(outbox.events, domain.OutboxEvent{
+			Sequence: int64(i),
+			Type:     domain.AdEventUpdated,
+			AdID:     uint(i),
+			Payload:  []byte(fmt.Sprintf(`{"id":%d}`, i)),
+		})
+	}
+	return outbox
+}
+
+func (o *fakeOutbox) RelayUnsent(ctx context.Context, limit int, publish func([]domain.OutboxEvent) (int, error)) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var unsent []domain.OutboxEvent
+	for _, event := range o.events {
+		if !o.sent[event.Sequence] && len(unsent) < limit {
+			unsent = append(unsent, event)
+		}
+	}
+	if len(unsent) == 0 {
+		return 0, nil
+	}
+
+	sent, publishErr := publish(unsent)
+	if o.failCommits > 0 {
+		o.failCommits--
+		return 0, errors.New("error marking outbox events sent: connection reset")
+	}
+	for _, event := range unsent[:sent] {
+		o.sent[event.Sequence] = true
+	}
+	return sent, publishErr
+}
+
+func (o *fakeOutbox) DeleteSentBefore(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// fakePublisher records the sequences it delivers. It fails once on the
+// sequences in fail and panics once on crashAt.
+type fakePublisher struct {
+	mu        sync.Mutex
+	delivered []int64
+	fail      map[int64]bool
+	crashAt   int64
+	closed    bool
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, event domain.OutboxEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.fail[event.Sequence] {
+		delete(p.fail, event.Sequence)
+		return errors.New("broker unavailable")
+	}
+	if event.Sequence == p.crashAt {
+		p.crashAt = 0
+		panic("relay crashed")
+	}
+	p.delivered = append(p.delivered, event.Sequence)
+	return nil
+}
+
+func (p *fakePublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	return nil
+}
+
+// assertAllDelivered fails unless every event of the outbox was delivered and
+// marked sent
+func assertAllDelivered(t *testing.T, outbox *fakeOutbox, publisher *fakePublisher) {
+	t.Helper()
+	for _, event := range outbox.events {
+		if !slices.Contains(publisher.delivered, event.Sequence) {
+			t.Errorf("event %d never delivered", event.Sequence)
+		}
+		if !outbox.sent[event.Sequence] {
+			t.Errorf("event %d not marked sent", event.Sequence)
+		}
+	}
+}
+
+func sequences(from, to int64) []int64 {
+	var seqs []int64
+	for seq := from; seq <= to; seq++ {
+		seqs = append(seqs, seq)
+	}
+	return seqs
+}
+
+func TestOutboxRelayRetriesFailedEvent(t *testing.T) {
+	outbox := newFakeOutbox(5)
+	publisher := &fakePublisher{fail: map[int64]bool{3: true}}
+	relay := NewOutboxRelay(outbox, publisher, time.Second, time.Hour)
+
+	if err := relay.Relay(context.Background()); err == nil {
+		t.Fatal("expected the failed publish to be reported")
+	}
+	if !outbox.sent[2] || outbox.sent[3] {
+		t.Fatalf("sent = %v, want the events before the failed one only", outbox.sent)
+	}
+
+	if err := relay.Relay(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	assertAllDelivered(t, outbox, publisher)
+	if want := sequences(1, 5); !slices.Equal(publisher.delivered, want) {
+		t.Errorf("delivered = %v, want %v", publisher.delivered, want)
+	}
+}
+
+func TestOutboxRelayRepublishesAfterCrash(t *testing.T) {
+	outbox := newFakeOutbox(5)
+	publisher := &fakePublisher{crashAt: 4}
+
+	// The process dies while publishing event 4, within the relaying transaction
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected the relay to crash")
+			}
+		}()
+		NewOutboxRelay(outbox, publisher, time.Second, time.Hour).Relay(context.Background())
+	}()
+	if len(outbox.sent) != 0 {
+		t.Fatalf("sent = %v, want nothing marked by the crashed transaction", outbox.sent)
+	}
+
+	// The restarted relay publishes everything again, in order
+	if err := NewOutboxRelay(outbox, publisher, time.Second, time.Hour).Relay(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	assertAllDelivered(t, outbox, publisher)
+	if want := append(sequences(1, 3), sequences(1, 5)...); !slices.Equal(publisher.delivered, want) {
+		t.Errorf("delivered = %v, want %v", publisher.delivered, want)
+	}
+}
+
+func TestOutboxRelayRepublishesAfterFailedCommit(t *testing.T) {
+	outbox := newFakeOutbox(5)
+	outbox.failCommits = 1
+	publisher := &fakePublisher{}
+	relay := NewOutboxRelay(outbox, publisher, time.Second, time.Hour)
+
+	// Every event is published but the transaction marking them sent fails
+	if err := relay.Relay(context.Background()); err == nil {
+		t.Fatal("expected the failed commit to be reported")
+	}
+	if err := relay.Relay(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	assertAllDelivered(t, outbox, publisher)
+	if want := append(sequences(1, 5), sequences(1, 5)...); !slices.Equal(publisher.delivered, want) {
+		t.Errorf("delivered = %v, want %v", publisher.delivered, want)
+	}
+}
+
+func TestOutboxRelayDrainsSeveralBatches(t *testing.T) {
+	outbox := newFakeOutbox(2*outboxBatchSize + 1)
+	publisher := &fakePublisher{}
+
+	if err := NewOutboxRelay(outbox, publisher, time.Second, time.Hour).Relay(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	assertAllDelivered(t, outbox, publisher)
+	if want := sequences(1, 2*outboxBatchSize+1); !slices.Equal(publisher.delivered, want) {
+		t.Errorf("delivered %d events, want %d in order", len(publisher.delivered), len(want))
+	}
+}
+
+func TestOutboxRelayRunClosesPublisher(t *testing.T) {
+	outbox := newFakeOutbox(3)
+	publisher := &fakePublisher{}
+	relay := NewOutboxRelay(outbox, publisher, time.Millisecond, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		relay.Run(ctx)
+		close(done)
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		outbox.mu.Lock()
+		sent := len(outbox.sent)
+		outbox.mu.Unlock()
+		if sent == 3 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	assertAllDelivered(t, outbox, publisher)
+	if !publisher.closed {
+		t.Error("publisher not closed when the relay stopped")
+	}
+}
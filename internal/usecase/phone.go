@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/1way-market/v3/internal/domain"
+	"github.com/go-redis/redis/v8"
+)
+
+// errPhonesDisabled is returned when an ad phone is stored or revealed without an encryption key
+var errPhonesDisabled = errors.New("ad phones are disabled: no phone encryption key configured")
+
+// phoneRevealsKey is the counter of phone reveals of an ad
+func phoneRevealsKey(adID uint) string {
+	return fmt.Sprintf("phone_reveals:%d", adID)
+}
+
+// sealPhone validates and encrypts the plaintext phone of the ad and sets its
+// masked form. The plaintext is cleared so it is neither cached nor echoed back.
+func (uc *AdUseCase) sealPhone(ad *domain.Ad) error {
+	if ad.Phone == "" {
+		ad.PhoneEncrypted = ""
+		ad.PhoneMasked = ""
+		return nil
+	}
+	if err := domain.ValidatePhone(ad.Phone); err != nil {
+		return err
+	}
+	if uc.phones == nil {
+		return errPhonesDisabled
+	}
+
+	encrypted, err := uc.phones.Encrypt(ad.Phone)
+	if err != nil {
+		return fmt.Errorf("error encrypting phone: %v", err)
+	}
+	ad.PhoneEncrypted = encrypted
+	ad.PhoneMasked = domain.MaskPhone(ad.Phone)
+	ad.Phone = ""
+	return nil
+}
+
+// RevealPhone returns the full phone of the ad and records that the calling
+// user viewed it. It reads the database since cached ads omit the phone.
+func (uc *AdUseCase) RevealPhone(ctx context.Context, id uint) (string, error) {
+	ad, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if ad.PhoneEncrypted == "" {
+		return "", domain.ErrNoPhone
+	}
+	if uc.phones == nil {
+		return "", errPhonesDisabled
+	}
+
+	phone, err := uc.phones.Decrypt(ad.PhoneEncrypted)
+	if err != nil {
+		return "", fmt.Errorf("error decrypting phone of ad %d: %v", id, err)
+	}
+
+	reveal := &domain.PhoneReveal{AdID: id}
+	if principal := domain.PrincipalFromContext(ctx); principal != nil {
+		reveal.UserID = principal.UserID
+	}
+	if err := uc.reveals.Create(ctx, reveal); err != nil {
+		return "", err
+	}
+	uc.incrementPhoneReveals(ctx, id)
+
+	return phone, nil
+}
+
+// incrementPhoneReveals bumps the reveal counter of the ad. A counter that
+// did not exist, e.g. after Redis lost its data, is reset from the database.
+func (uc *AdUseCase) incrementPhoneReveals(ctx context.Context, id uint) {
+	redisCtx, cancel := uc.cacheContext(ctx)
+	count, err := uc.cache.Incr(redisCtx, phoneRevealsKey(id)).Result()
+	cancel()
+	if err != nil {
+		log.Printf("Warning: phone reveal count of ad %d not updated: %v", id, err)
+		return
+	}
+
+	if count == 1 {
+		if total, err := uc.reveals.CountByAd(ctx, id); err == nil && total > 1 {
+			uc.cacheSet(ctx, phoneRevealsKey(id), total, 0)
+		}
+	}
+}
+
+// GetAdStats returns the engagement counters of the ad to the user who posted
+// it, moderators and admins
+func (uc *AdUseCase) GetAdStats(ctx context.Context, id uint) (*domain.AdStats, error) {
+	ad, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeOwner(ctx, ad); err != nil {
+		return nil, err
+	}
+
+	stats := &domain.AdStats{AdID: id}
+	redisCtx, cancel := uc.cacheContext(ctx)
+	stats.PhoneRevealCount, err = uc.cache.Get(redisCtx, phoneRevealsKey(id)).Int64()
+	cancel()
+	if err != nil {
+		if err != redis.Nil {
+			log.Printf("Warning: cache read of %s failed: %v", phoneRevealsKey(id), err)
+		}
+		if stats.PhoneRevealCount, err = uc.reveals.CountByAd(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return stats, nil
+}
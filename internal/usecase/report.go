@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"log"
+
+	"github.com/1way-market/v3/internal/domain"
+)
+
+// ReportAd records the current user's report of an ad. An ad with more
+// reports than the configured threshold is rejected automatically.
+func (uc *AdUseCase) ReportAd(ctx context.Context, report *domain.AdReport) error {
+	if err := domain.ValidateReport(report); err != nil {
+		return err
+	}
+
+	principal := domain.PrincipalFromContext(ctx)
+	if principal == nil {
+		return domain.ErrForbidden
+	}
+
+	ad, err := uc.repo.GetByID(ctx, report.AdID)
+	if err != nil {
+		return err
+	}
+
+	report.ID = 0
+	report.ReporterID = principal.UserID
+	report.Status = domain.ReportStatusOpen
+	if err := uc.reports.Create(ctx, report); err != nil {
+		return err
+	}
+
+	threshold := uc.cfg.AutoRejectThreshold
+	if threshold <= 0 || ad.Status == domain.StatusRejected {
+		return nil
+	}
+	// The report is saved, so failing to act on it only delays the rejection to the next report
+	count, err := uc.reports.CountByAd(ctx, ad.ID)
+	if err != nil {
+		log.Printf("Warning: reports of ad %d not counted: %v", ad.ID, err)
+		return nil
+	}
+	if count > int64(threshold) {
+		uc.autoReject(ctx, ad)
+	}
+	return nil
+}
+
+// autoReject rejects an ad that was reported too many times
+func (uc *AdUseCase) autoReject(ctx context.Context, ad *domain.Ad) {
+	if err := uc.repo.SetStatus(ctx, ad.ID, domain.StatusRejected, domain.AutoRejectedByReports); err != nil {
+		log.Printf("Warning: ad %d not rejected after reports: %v", ad.ID, err)
+		return
+	}
+	log.Printf("Ad %d rejected automatically after exceeding %d reports", ad.ID, uc.cfg.AutoRejectThreshold)
+
+	deltas := make(map[int]int64)
+	addCategoryDeltas(deltas, ad, -1)
+	uc.adjustCategoryCounts(ctx, deltas)
+	uc.syncSearchIndex(ctx, ad.ID)
+
+	uc.invalidateAdsCache(ctx)
+	uc.invalidateAd(ctx, ad.ID)
+	uc.publishInvalidation(ctx, ad.ID, ad)
+}
+
+// ListReports returns a page of the reports awaiting moderation, oldest first
+func (uc *AdUseCase) ListReports(ctx context.Context, pageSize int, pageToken string) (*domain.AdReportPage, error) {
+	if pageSize <= 0 {
+		pageSize = domain.DefaultPageSize
+	}
+	return uc.reports.ListOpen(ctx, pageSize, pageToken)
+}
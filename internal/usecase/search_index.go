@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/1way-market/v3/internal/domain"
+)
+
+// SearchIndex is an external search engine the ads are indexed in. When one is
+// configured, it answers the text searches of ad listings and the matching ads
+// are then loaded from the database; otherwise the database full-text search
+// answers them.
+type SearchIndex interface {
+	IndexAd(ctx context.Context, ad *domain.Ad) error
+	RemoveAd(ctx context.Context, id uint) error
+	Search(ctx context.Context, filter domain.FilterRequest) (*domain.SearchResult, error)
+}
+
+// findAds queries a page of the ads listing, through the search index for text searches
+func (uc *AdUseCase) findAds(ctx context.Context, filter domain.FilterRequest) (*domain.PaginatedResponse, error) {
+	if uc.search == nil || filter.TextSearch == "" {
+		response, err := uc.repo.FindWithFilter(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		return listImages(response, filter), nil
+	}
+
+	result, err := uc.search.Search(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	found, err := uc.repo.FindByIDs(ctx, result.IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the ranking of the index; ads deleted since they were indexed are skipped
+	byID := make(map[uint]domain.Ad, len(found))
+	for _, ad := range found {
+		byID[ad.ID] = ad
+	}
+	items := make([]domain.Ad, 0, len(result.IDs))
+	for _, id := range result.IDs {
+		if ad, ok := byID[id]; ok {
+			items = append(items, ad)
+		}
+	}
+
+	return listImages(&domain.PaginatedResponse{
+		Items:      items,
+		NextPage:   result.Next,
+		TotalCount: result.Total,
+		PageSize:   filter.PageSize,
+		Facets:     result.Facets,
+	}, filter), nil
+}
+
+// syncSearchIndex brings the indexed ad up to date with the database after a change
+func (uc *AdUseCase) syncSearchIndex(ctx context.Context, id uint) {
+	if uc.search == nil {
+		return
+	}
+
+	ad, err := uc.repo.GetByID(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		if err := uc.search.RemoveAd(ctx, id); err != nil {
+			log.Printf("Warning: ad %d not removed from the search index: %v", id, err)
+		}
+		return
+	}
+	if err != nil {
+		log.Printf("Warning: ad %d not reindexed: %v", id, err)
+		return
+	}
+	uc.indexAd(ctx, ad)
+}
+
+// indexAd writes the ad to the search index. Failures are logged, not returned:
+// the change is saved, and the index catches up on the next change of the ad
+// or the next reindex.
+func (uc *AdUseCase) indexAd(ctx context.Context, ad *domain.Ad) {
+	if uc.search == nil {
+		return
+	}
+	if err := uc.search.IndexAd(ctx, ad); err != nil {
+		log.Printf("Warning: ad %d not reindexed: %v", ad.ID, err)
+	}
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/1way-market/v3/internal/config"
+	"github.com/1way-market/v3/internal/domain"
+)
+
+type SellerRepository interface {
+	Create(ctx context.Context, seller *domain.Seller) error
+	Update(ctx context.Context, seller *domain.Seller) (bool, error)
+	Delete(ctx context.Context, id uint) error
+	GetByID(ctx context.Context, id uint) (*domain.Seller, error)
+}
+
+type SellerUseCase struct {
+	repo   SellerRepository
+	adRepo AdRepository
+	cfg    *config.Config
+}
+
+func NewSellerUseCase(repo SellerRepository, adRepo AdRepository, cfg *config.Config) *SellerUseCase {
+	return &SellerUseCase{
+		repo:   repo,
+		adRepo: adRepo,
+		cfg:    cfg,
+	}
+}
+
+func (uc *SellerUseCase) CreateSeller(ctx context.Context, seller *domain.Seller) error {
+	return uc.repo.Create(ctx, seller)
+}
+
+func (uc *SellerUseCase) UpdateSeller(ctx context.Context, seller *domain.Seller) error {
+	found, err := uc.repo.Update(ctx, seller)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (uc *SellerUseCase) DeleteSeller(ctx context.Context, id uint) error {
+	return uc.repo.Delete(ctx, id)
+}
+
+// GetSeller returns the seller with the given ID or domain.ErrNotFound
+func (uc *SellerUseCase) GetSeller(ctx context.Context, id uint) (*domain.Seller, error) {
+	seller, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if seller == nil {
+		return nil, domain.ErrNotFound
+	}
+	return seller, nil
+}
+
+// GetSellerAds returns the seller's ads matching the filter
+func (uc *SellerUseCase) GetSellerAds(ctx context.Context, id uint, filter domain.FilterRequest) (*domain.PaginatedResponse, error) {
+	if _, err := uc.GetSeller(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if err := domain.ValidateFilter(&filter); err != nil {
+		return nil, err
+	}
+	if err := normalizeFilter(&filter); err != nil {
+		return nil, err
+	}
+	if err := limitPageSize(&filter, uc.cfg.MaxPageSize); err != nil {
+		return nil, err
+	}
+	applyDefaultVisibility(ctx, &filter, uc.cfg.DefaultVisibleStatuses)
+	filter.SellerID = &id
+	return uc.adRepo.FindWithFilter(ctx, filter)
+}
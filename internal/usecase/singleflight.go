@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// flightTimeout bounds a shared call, which none of its callers can cancel
+const flightTimeout = 30 * time.Second
+
+// flightGroup deduplicates concurrent calls sharing a key. The zero value is
+// ready to use.
+type flightGroup struct {
+	group atomic.Pointer[singleflight.Group]
+}
+
+// current returns the group new calls join
+func (g *flightGroup) current() *singleflight.Group {
+	for {
+		if group := g.group.Load(); group != nil {
+			return group
+		}
+		g.group.CompareAndSwap(nil, new(singleflight.Group))
+	}
+}
+
+// Do runs fn once for all concurrent callers with the same key and returns its
+// result and error to each of them. fn runs on a context detached from the
+// cancellation of the caller that started it, so that the others do not fail
+// when it gives up; a caller whose context ends stops waiting with its error.
+// A panic in fn is returned as an error to every caller.
+func (g *flightGroup) Do(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	results := g.current().DoChan(key, func() (val interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("Error: shared call %s panicked: %v\n%s", key, r, debug.Stack())
+				val, err = nil, fmt.Errorf("shared call %s panicked: %v", key, r)
+			}
+		}()
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), flightTimeout)
+		defer cancel()
+		return fn(ctx)
+	})
+
+	select {
+	case result := <-results:
+		return result.Val, result.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// ForgetAll makes later callers start new calls instead of joining the ones in
+// flight, whose results may predate a change. Current waiters are unaffected.
+func (g *flightGroup) ForgetAll() {
+	g.group.Store(new(singleflight.Group))
+}
@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/1way-market/v3/internal/domain"
+)
+
+func TestGetAdsSharesConcurrentMisses(t *testing.T) {
+	repo := newFakeAdRepo(domain.Ad{ID: 1, Status: domain.StatusActive}, domain.Ad{ID: 2, Status: domain.StatusActive})
+	repo.findGate = make(chan struct{})
+	uc := newTestAdUseCase(t, repo, testConfig())
+
+	const callers = 100
+	var (
+		ready sync.WaitGroup
+		done  sync.WaitGroup
+	)
+	responses := make([]*domain.PaginatedResponse, callers)
+	errs := make([]error, callers)
+	for i := 0; i < callers; i++ {
+		ready.Add(1)
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			ready.Done()
+			responses[i], errs[i] = uc.GetAds(context.Background(), domain.FilterRequest{Lang: "en"})
+		}()
+	}
+
+	// Give the callers time to join the query held by the gate; a caller
+	// arriving once it is done finds its result in the cache
+	ready.Wait()
+	time.Sleep(100 * time.Millisecond)
+	close(repo.findGate)
+	done.Wait()
+
+	if finds := repo.finds.Load(); finds != 1 {
+		t.Errorf("%d concurrent misses queried the database %d times, want 1", callers, finds)
+	}
+	for i := range responses {
+		if errs[i] != nil {
+			t.Fatalf("caller %d: %v", i, errs[i])
+		}
+		if responses[i] == nil || len(responses[i].Items) != 2 {
+			t.Fatalf("caller %d got %+v, want the 2 ads", i, responses[i])
+		}
+	}
+}
+
+func TestFlightGroupDetachesFromLeader(t *testing.T) {
+	var group flightGroup
+	started := make(chan struct{})
+	release := make(chan struct{})
+	callErr := make(chan error, 1)
+	fn := func(ctx context.Context) (interface{}, error) {
+		close(started)
+		<-release
+		// The call must outlive the leader giving up
+		callErr <- ctx.Err()
+		return "ads", ctx.Err()
+	}
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := group.Do(leaderCtx, "key", fn)
+		leaderErr <- err
+	}()
+	<-started
+
+	cancelLeader()
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("leader error = %v, want %v", err, context.Canceled)
+	}
+
+	// The call is still in flight, so a later caller joins it
+	follower := make(chan error, 1)
+	go func() {
+		val, err := group.Do(context.Background(), "key", func(context.Context) (interface{}, error) {
+			return nil, errors.New("follower started a second call")
+		})
+		if err == nil && val != "ads" {
+			err = errors.New("follower got no ads")
+		}
+		follower <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	if err := <-callErr; err != nil {
+		t.Errorf("shared call canceled with its leader: %v", err)
+	}
+	if err := <-follower; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFlightGroupPanic(t *testing.T) {
+	var group flightGroup
+	release := make(chan struct{})
+	fn := func(context.Context) (interface{}, error) {
+		<-release
+		panic("query failed")
+	}
+
+	const callers = 10
+	var wg sync.WaitGroup
+	vals := make([]interface{}, callers)
+	errs := make([]error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			vals[i], errs[i] = group.Do(context.Background(), "key", fn)
+		}()
+	}
+	close(release)
+	wg.Wait()
+
+	for i := range errs {
+		if errs[i] == nil {
+			t.Errorf("caller %d got (%v, nil), want an error", i, vals[i])
+		}
+	}
+}
+
+func TestFlightGroupForgetAll(t *testing.T) {
+	var group flightGroup
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go group.Do(context.Background(), "key", func(context.Context) (interface{}, error) {
+		close(started)
+		<-release
+		return "stale", nil
+	})
+	<-started
+
+	group.ForgetAll()
+	val, err := group.Do(context.Background(), "key", func(context.Context) (interface{}, error) {
+		return "fresh", nil
+	})
+	close(release)
+	if err != nil || val != "fresh" {
+		t.Errorf("got (%v, %v) after ForgetAll, want (fresh, nil)", val, err)
+	}
+}
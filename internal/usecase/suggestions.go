@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/1way-market/v3/internal/domain"
+)
+
+// suggestionsTTL is how long search suggestions are cached; they are requested on every keystroke
+const suggestionsTTL = 30 * time.Second
+
+// GetSuggestions returns up to limit ad titles in the given language matching the
+// typed prefix, most frequent first
+func (uc *AdUseCase) GetSuggestions(ctx context.Context, prefix string, lang domain.Language, limit int) ([]domain.Suggestion, error) {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+
+	cacheKey := uc.adsCacheKey(ctx, fmt.Sprintf("suggest:%d:%d:%s", lang, limit, prefix))
+	if cachedData, ok := uc.cacheGet(ctx, cacheKey); ok {
+		var suggestions []domain.Suggestion
+		if err := uc.serializer.decode(cachedData, &suggestions); err == nil {
+			return suggestions, nil
+		}
+	}
+
+	suggestions, err := uc.repo.Suggest(ctx, prefix, lang, limit)
+	if err != nil {
+		return nil, err
+	}
+	if suggestions == nil {
+		suggestions = []domain.Suggestion{}
+	}
+
+	if data, err := uc.serializer.encode(suggestions); err == nil {
+		uc.cacheSet(ctx, cacheKey, data, suggestionsTTL)
+	}
+
+	return suggestions, nil
+}
@@ -1,16 +1,85 @@
 package usecase
 
 import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/1way-market/v3/internal/config"
 	"github.com/1way-market/v3/internal/repository"
+	"github.com/1way-market/v3/internal/search"
+	"github.com/1way-market/v3/internal/storage"
 	"github.com/go-redis/redis/v8"
 )
 
 type UseCases struct {
-	AdUseCase *AdUseCase
+	AdUseCase        *AdUseCase
+	CategoryUseCase  *CategoryUseCase
+	FavoriteUseCase  *FavoriteUseCase
+	FeedUseCase      *FeedUseCase
+	SellerUseCase    *SellerUseCase
+	HealthChecker    *HealthChecker
+	ViewCountFlusher *ViewCountFlusher
+	// OutboxRelay is nil when ad events are not published
+	OutboxRelay *OutboxRelay
+	// ActivationWorker is nil when approved ads are not activated automatically
+	ActivationWorker *StatusActivationWorker
+	// ExpirationWorker is nil without Redis, which it takes turns through
+	ExpirationWorker *ExpirationWorker
 }
 
-func NewUseCases(repos *repository.Repositories, redisClient *redis.Client) *UseCases {
+func NewUseCases(repos *repository.Repositories, sqlDB *sql.DB, redisClient *redis.Client, cfg *config.Config) *UseCases {
+	var outboxRelay *OutboxRelay
+	if publisher := newEventPublisher(cfg, redisClient); publisher != nil {
+		outboxRelay = NewOutboxRelay(repos.Outbox, publisher, cfg.OutboxInterval, cfg.OutboxRetention)
+	}
+
+	// Without a search index, the database full-text search answers text searches
+	var searchIndex SearchIndex
+	if cfg.SearchBackend == "opensearch" {
+		index := search.NewOpenSearch(cfg.OpenSearchURL, cfg.OpenSearchIndex)
+		// Create the index with its mapping before ads are written to it
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := index.EnsureIndex(ctx); err != nil {
+			log.Printf("Warning: Failed to create the search index: %v", err)
+		}
+		cancel()
+		searchIndex = index
+	}
+
+	// Without a blob storage, ads have no images
+	var blobs BlobStorage
+	if cfg.StorageEndpoint != "" {
+		bucket, err := storage.NewS3(cfg.StorageEndpoint, cfg.StorageBucket, cfg.StorageRegion,
+			cfg.StorageAccessKey, cfg.StorageSecretKey, cfg.StoragePublicURL)
+		if err != nil {
+			log.Printf("Warning: %v, ad images disabled", err)
+		} else {
+			blobs = bucket
+		}
+	}
+
+	adUseCase := NewAdUseCase(repos.Ad, repos.Property, repos.PhoneReveal, repos.Favorite, repos.AdReport, searchIndex, blobs, redisClient, cfg)
+	var activationWorker *StatusActivationWorker
+	if cfg.ActivationDelay > 0 && redisClient != nil {
+		activationWorker = NewStatusActivationWorker(adUseCase, redisClient, cfg.ActivationDelay, cfg.ActivationInterval)
+	}
+	var expirationWorker *ExpirationWorker
+	if redisClient != nil {
+		expirationWorker = NewExpirationWorker(adUseCase, redisClient, cfg.ExpirationInterval)
+	}
+
 	return &UseCases{
-		AdUseCase: NewAdUseCase(repos.Ad, redisClient),
+		AdUseCase:        adUseCase,
+		CategoryUseCase:  NewCategoryUseCase(repos.Ad, repos.Category, redisClient, cfg),
+		FavoriteUseCase:  NewFavoriteUseCase(repos.Favorite, repos.Ad, redisClient, cfg),
+		FeedUseCase:      NewFeedUseCase(repos.Ad, redisClient, cfg),
+		SellerUseCase:    NewSellerUseCase(repos.Seller, repos.Ad, cfg),
+		HealthChecker:    NewHealthChecker(sqlDB, redisClient),
+		ViewCountFlusher: NewViewCountFlusher(repos.Ad, redisClient, cfg.ViewFlushInterval),
+		OutboxRelay:      outboxRelay,
+		ActivationWorker: activationWorker,
+		ExpirationWorker: expirationWorker,
 	}
 }
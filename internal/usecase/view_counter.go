@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// adViewsKeyPrefix prefixes the Redis counters of ad views not yet flushed to the database
+const adViewsKeyPrefix = "ad:views:"
+
+// adViewsKey is the view counter of an ad
+func adViewsKey(id uint) string {
+	return fmt.Sprintf("%s%d", adViewsKeyPrefix, id)
+}
+
+// RecordView counts a view of the ad. Views are counted in Redis and added to
+// the database by the ViewCountFlusher, so that views do not write to ads.
+func (uc *AdUseCase) RecordView(ctx context.Context, id uint) {
+	ctx, cancel := uc.cacheContext(ctx)
+	defer cancel()
+
+	if err := uc.cache.Incr(ctx, adViewsKey(id)).Err(); err != nil {
+		log.Printf("Warning: view of ad %d not counted: %v", id, err)
+	}
+}
+
+// ViewCountRepository persists ad view counts
+type ViewCountRepository interface {
+	AddViewCounts(ctx context.Context, deltas map[uint]int64) error
+}
+
+// ViewCountFlusher periodically moves the Redis view counters into ads.view_count
+type ViewCountFlusher struct {
+	repo     ViewCountRepository
+	cache    *redis.Client
+	interval time.Duration
+}
+
+func NewViewCountFlusher(repo ViewCountRepository, cache *redis.Client, interval time.Duration) *ViewCountFlusher {
+	return &ViewCountFlusher{
+		repo:     repo,
+		cache:    cache,
+		interval: interval,
+	}
+}
+
+// Run flushes the counters every interval until ctx is done, then once more
+// so that views counted before shutdown are kept
+func (f *ViewCountFlusher) Run(ctx context.Context) {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := f.Flush(flushCtx); err != nil {
+				log.Printf("Warning: final view count flush failed: %v", err)
+			}
+			cancel()
+			return
+		case <-ticker.C:
+			if err := f.Flush(ctx); err != nil {
+				log.Printf("Warning: view count flush failed: %v", err)
+			}
+		}
+	}
+}
+
+// Flush adds every Redis view counter to the database in one update. Counters
+// are taken with GETDEL, so views counted meanwhile go to the next flush and
+// concurrent flushes of other instances never count a view twice. When the
+// update fails the counts are put back.
+func (f *ViewCountFlusher) Flush(ctx context.Context) error {
+	deltas := make(map[uint]int64)
+	iter := f.cache.Scan(ctx, 0, adViewsKeyPrefix+"*", cacheDeleteBatch).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		id, err := strconv.ParseUint(strings.TrimPrefix(key, adViewsKeyPrefix), 10, 32)
+		if err != nil {
+			continue
+		}
+
+		count, err := f.cache.GetDel(ctx, key).Int64()
+		if err != nil {
+			if err == redis.Nil {
+				continue
+			}
+			f.restore(deltas)
+			return fmt.Errorf("error reading %s: %v", key, err)
+		}
+		deltas[uint(id)] += count
+	}
+	if err := iter.Err(); err != nil {
+		f.restore(deltas)
+		return fmt.Errorf("error scanning view counters: %v", err)
+	}
+
+	if err := f.repo.AddViewCounts(ctx, deltas); err != nil {
+		f.restore(deltas)
+		return err
+	}
+	return nil
+}
+
+// restore puts taken counts back into their Redis counters
+func (f *ViewCountFlusher) restore(deltas map[uint]int64) {
+	if len(deltas) == 0 {
+		return
+	}
+
+	// The flush context may be what failed, so the counts are restored regardless of it
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pipe := f.cache.Pipeline()
+	for id, count := range deltas {
+		pipe.IncrBy(ctx, adViewsKey(id), count)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("Warning: %d ad view counts lost: %v", len(deltas), err)
+	}
+}
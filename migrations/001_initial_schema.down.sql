@@ -0,0 +1,7 @@
+-- Drop the search vector trigger and its function
+DROP TRIGGER IF EXISTS ads_search_vector_update ON ads;
+DROP FUNCTION IF EXISTS ads_search_vector_trigger();
+
+-- Drop the initial tables
+DROP TABLE IF EXISTS category_closure;
+DROP TABLE IF EXISTS ads;
@@ -3,7 +3,7 @@ CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 CREATE EXTENSION IF NOT EXISTS "btree_gin";
 
 -- Create ads table
-CREATE TABLE ads (
+CREATE TABLE IF NOT EXISTS ads (
     id SERIAL PRIMARY KEY,
     title JSONB NOT NULL,
     description JSONB,
@@ -17,24 +17,24 @@ CREATE TABLE ads (
 );
 
 -- Create indexes
-CREATE INDEX idx_ads_status ON ads(status);
-CREATE INDEX idx_ads_category_ids ON ads USING GIN(category_ids);
-CREATE INDEX idx_ads_search_vector ON ads USING GIN(search_vector);
-CREATE INDEX idx_ads_title ON ads USING GIN(title jsonb_path_ops);
-CREATE INDEX idx_ads_properties ON ads USING GIN(properties);
-CREATE INDEX idx_ads_price ON ads(price);
-CREATE INDEX idx_ads_created_at ON ads(created_at);
+CREATE INDEX IF NOT EXISTS idx_ads_status ON ads(status);
+CREATE INDEX IF NOT EXISTS idx_ads_category_ids ON ads USING GIN(category_ids);
+CREATE INDEX IF NOT EXISTS idx_ads_search_vector ON ads USING GIN(search_vector);
+CREATE INDEX IF NOT EXISTS idx_ads_title ON ads USING GIN(title jsonb_path_ops);
+CREATE INDEX IF NOT EXISTS idx_ads_properties ON ads USING GIN(properties);
+CREATE INDEX IF NOT EXISTS idx_ads_price ON ads(price);
+CREATE INDEX IF NOT EXISTS idx_ads_created_at ON ads(created_at);
 
 -- Create categories closure table
-CREATE TABLE category_closure (
+CREATE TABLE IF NOT EXISTS category_closure (
     ancestor_id INTEGER NOT NULL,
     descendant_id INTEGER NOT NULL,
     depth INTEGER NOT NULL,
     PRIMARY KEY (ancestor_id, descendant_id)
 );
 
-CREATE INDEX idx_category_closure_ancestor ON category_closure(ancestor_id);
-CREATE INDEX idx_category_closure_descendant ON category_closure(descendant_id);
+CREATE INDEX IF NOT EXISTS idx_category_closure_ancestor ON category_closure(ancestor_id);
+CREATE INDEX IF NOT EXISTS idx_category_closure_descendant ON category_closure(descendant_id);
 
 -- Create function to update search vector
 CREATE OR REPLACE FUNCTION ads_search_vector_trigger() RETURNS trigger AS $$
@@ -49,6 +49,7 @@ END
 $$ LANGUAGE plpgsql;
 
 -- Create trigger for search vector updates
+DROP TRIGGER IF EXISTS ads_search_vector_update ON ads;
 CREATE TRIGGER ads_search_vector_update
     BEFORE INSERT OR UPDATE ON ads
     FOR EACH ROW
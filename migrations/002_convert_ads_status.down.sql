@@ -0,0 +1,25 @@
+-- Convert the numeric AdStatus codes back into the textual status
+ALTER TABLE ads ADD COLUMN IF NOT EXISTS status_old VARCHAR(50);
+
+UPDATE ads
+SET status_old = CASE status
+    WHEN 0 THEN 'draft'
+    WHEN 1 THEN 'pending'
+    WHEN 2 THEN 'from_parser'
+    WHEN 3 THEN 'active'
+    WHEN 4 THEN 'completed'
+    WHEN 5 THEN 'rejected'
+    WHEN 6 THEN 'approved'
+    WHEN 7 THEN 'unknown'
+    WHEN 8 THEN 'duplicate'
+    ELSE 'draft'
+END;
+
+ALTER TABLE ads DROP COLUMN status;
+ALTER TABLE ads
+    ALTER COLUMN status_old SET NOT NULL,
+    ALTER COLUMN status_old SET DEFAULT 'active';
+ALTER TABLE ads RENAME COLUMN status_old TO status;
+
+-- Recreate the status index
+CREATE INDEX IF NOT EXISTS idx_ads_status ON ads(status);
@@ -0,0 +1,44 @@
+-- Convert the textual ad status into the numeric AdStatus codes.
+-- Guarded so databases converted by earlier tooling, fully or partially, are left consistent.
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = 'public' AND table_name = 'ads'
+        AND column_name = 'status' AND data_type = 'character varying'
+    ) THEN
+        ALTER TABLE ads ADD COLUMN IF NOT EXISTS status_new INTEGER;
+
+        UPDATE ads
+        SET status_new = CASE status
+            WHEN 'draft' THEN 0
+            WHEN 'pending' THEN 1
+            WHEN 'from_parser' THEN 2
+            WHEN 'active' THEN 3
+            WHEN 'completed' THEN 4
+            WHEN 'rejected' THEN 5
+            WHEN 'approved' THEN 6
+            WHEN 'unknown' THEN 7
+            WHEN 'duplicate' THEN 8
+            ELSE 0
+        END;
+
+        -- Drop the old status column
+        ALTER TABLE ads DROP COLUMN status;
+    END IF;
+
+    -- Rename the new column if a previous run stopped after dropping the old one
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = 'public' AND table_name = 'ads' AND column_name = 'status_new'
+    ) THEN
+        ALTER TABLE ads
+            ALTER COLUMN status_new SET NOT NULL,
+            ALTER COLUMN status_new SET DEFAULT 0;
+        ALTER TABLE ads RENAME COLUMN status_new TO status;
+    END IF;
+END
+$$;
+
+-- Recreate the status index
+CREATE INDEX IF NOT EXISTS idx_ads_status ON ads(status);
@@ -0,0 +1,3 @@
+-- Drop property tables
+DROP TABLE IF EXISTS property_values;
+DROP TABLE IF EXISTS properties;
@@ -0,0 +1,2 @@
+-- The previous column type is not known and its data was dropped by the up
+-- migration, so properties stays JSONB. Rolling back only unrecords the version.
@@ -0,0 +1,17 @@
+-- Store ad properties as a JSONB array of {ID, value, value_id}.
+-- Only replace the column when it is not JSONB yet so re-running keeps existing data.
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = 'public' AND table_name = 'ads'
+        AND column_name = 'properties' AND data_type <> 'jsonb'
+    ) THEN
+        ALTER TABLE ads DROP COLUMN properties;
+    END IF;
+END
+$$;
+
+ALTER TABLE ads ADD COLUMN IF NOT EXISTS properties JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_ads_properties ON ads USING gin(properties);
@@ -0,0 +1,9 @@
+-- Create favorites table
+CREATE TABLE IF NOT EXISTS favorites (
+    user_id VARCHAR(255) NOT NULL,
+    ad_id INTEGER NOT NULL REFERENCES ads(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, ad_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_favorites_user_created ON favorites(user_id, created_at DESC, ad_id DESC);
@@ -0,0 +1,14 @@
+-- Restore the original search vector function
+CREATE OR REPLACE FUNCTION ads_search_vector_trigger() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector('russian', COALESCE(NEW.title->>'ru', '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(NEW.title->>'en', '')), 'A') ||
+        setweight(to_tsvector('russian', COALESCE(NEW.description->>'ru', '')), 'B') ||
+        setweight(to_tsvector('english', COALESCE(NEW.description->>'en', '')), 'B');
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+DROP FUNCTION IF EXISTS ads_build_search_vector(JSONB, JSONB);
+DROP FUNCTION IF EXISTS ads_lang_regconfig(INTEGER);
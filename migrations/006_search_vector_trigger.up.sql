@@ -0,0 +1,44 @@
+-- Map ad language codes to text search configurations
+CREATE OR REPLACE FUNCTION ads_lang_regconfig(lang INTEGER) RETURNS regconfig AS $$
+    SELECT CASE lang
+        WHEN 1 THEN 'russian'::regconfig
+        WHEN 2 THEN 'english'::regconfig
+        WHEN 3 THEN 'turkish'::regconfig
+        ELSE 'simple'::regconfig
+    END
+$$ LANGUAGE sql IMMUTABLE;
+
+-- Build the search vector from every language version of the title and description
+CREATE OR REPLACE FUNCTION ads_build_search_vector(title JSONB, description JSONB) RETURNS tsvector AS $$
+DECLARE
+    result tsvector := ''::tsvector;
+    item JSONB;
+BEGIN
+    IF jsonb_typeof(title) = 'array' THEN
+        FOR item IN SELECT * FROM jsonb_array_elements(title) LOOP
+            result := result || setweight(
+                to_tsvector(ads_lang_regconfig((item->>'lang')::int), COALESCE(item->>'text', '')), 'A');
+        END LOOP;
+    END IF;
+
+    IF jsonb_typeof(description) = 'array' THEN
+        FOR item IN SELECT * FROM jsonb_array_elements(description) LOOP
+            result := result || setweight(
+                to_tsvector(ads_lang_regconfig((item->>'lang')::int), COALESCE(item->>'text', '')), 'B');
+        END LOOP;
+    END IF;
+
+    RETURN result;
+END
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+-- Compute the search vector server-side on every write
+CREATE OR REPLACE FUNCTION ads_search_vector_trigger() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector := ads_build_search_vector(NEW.title, NEW.description);
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+-- Recompute vectors for existing ads
+UPDATE ads SET search_vector = ads_build_search_vector(title, description);
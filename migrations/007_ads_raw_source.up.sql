@@ -0,0 +1,2 @@
+-- Keep the original parser payload of ingested ads for debugging
+ALTER TABLE ads ADD COLUMN IF NOT EXISTS raw_source JSONB;
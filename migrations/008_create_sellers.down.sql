@@ -0,0 +1,4 @@
+-- Unlink ads and drop sellers table
+DROP INDEX IF EXISTS idx_ads_seller_id;
+ALTER TABLE ads DROP COLUMN IF EXISTS seller_id;
+DROP TABLE IF EXISTS sellers;
@@ -0,0 +1,15 @@
+-- Create sellers table
+CREATE TABLE IF NOT EXISTS sellers (
+    id SERIAL PRIMARY KEY,
+    name JSONB NOT NULL,
+    phone VARCHAR(50),
+    email VARCHAR(255),
+    is_verified BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Link ads to the seller who posted them; existing ads stay anonymous
+ALTER TABLE ads ADD COLUMN IF NOT EXISTS seller_id INTEGER REFERENCES sellers(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_ads_seller_id ON ads(seller_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_ads_slug;
+ALTER TABLE ads DROP COLUMN IF EXISTS slug;
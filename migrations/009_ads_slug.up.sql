@@ -0,0 +1,4 @@
+-- Human-readable URL segment of an ad, assigned once it has an ID
+ALTER TABLE ads ADD COLUMN IF NOT EXISTS slug VARCHAR(255);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ads_slug ON ads(slug);
@@ -0,0 +1,4 @@
+-- Drop phone reveals and the ad phone columns
+DROP TABLE IF EXISTS phone_reveals;
+ALTER TABLE ads DROP COLUMN IF EXISTS phone_masked;
+ALTER TABLE ads DROP COLUMN IF EXISTS phone_encrypted;
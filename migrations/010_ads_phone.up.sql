@@ -0,0 +1,13 @@
+-- Seller contact phone: encrypted with AES-256-GCM, plus a masked copy for listings
+ALTER TABLE ads ADD COLUMN IF NOT EXISTS phone_encrypted TEXT;
+ALTER TABLE ads ADD COLUMN IF NOT EXISTS phone_masked VARCHAR(50);
+
+-- Users who viewed the full phone of an ad
+CREATE TABLE IF NOT EXISTS phone_reveals (
+    id SERIAL PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    ad_id INTEGER NOT NULL REFERENCES ads(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_phone_reveals_ad_id ON phone_reveals(ad_id);
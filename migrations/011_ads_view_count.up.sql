@@ -0,0 +1,2 @@
+-- Number of times an ad was viewed, flushed periodically from Redis counters
+ALTER TABLE ads ADD COLUMN IF NOT EXISTS view_count BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+-- Incremented on every update; updates must name the version they were based on
+ALTER TABLE ads ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
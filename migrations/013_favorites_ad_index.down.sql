@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_favorites_ad_id;
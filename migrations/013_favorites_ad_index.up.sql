@@ -0,0 +1,2 @@
+-- Favorites are counted per ad for the single-ad response
+CREATE INDEX IF NOT EXISTS idx_favorites_ad_id ON favorites(ad_id);
@@ -0,0 +1,3 @@
+-- Drop ad reports and the ad status reason
+DROP TABLE IF EXISTS ad_reports;
+ALTER TABLE ads DROP COLUMN IF EXISTS status_reason;
@@ -0,0 +1,16 @@
+-- Why an ad has its status, e.g. auto_rejected_by_reports
+ALTER TABLE ads ADD COLUMN IF NOT EXISTS status_reason VARCHAR(100);
+
+-- Ads flagged by users, at most once per user
+CREATE TABLE IF NOT EXISTS ad_reports (
+    id SERIAL PRIMARY KEY,
+    ad_id INTEGER NOT NULL REFERENCES ads(id) ON DELETE CASCADE,
+    reporter_id VARCHAR(255) NOT NULL,
+    reason INTEGER NOT NULL,
+    description TEXT,
+    status INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (ad_id, reporter_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ad_reports_status_id ON ad_reports(status, id);
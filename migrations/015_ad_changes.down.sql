@@ -0,0 +1,4 @@
+-- Drop the ad change journal
+DROP TRIGGER IF EXISTS ads_record_change ON ads;
+DROP FUNCTION IF EXISTS ads_record_change();
+DROP TABLE IF EXISTS ad_changes;
@@ -0,0 +1,37 @@
+-- Journal of ad writes for incremental sync. Changes are read in (txid, id)
+-- order and only from transactions older than every running one, so a
+-- reader never passes a change that commits later.
+CREATE TABLE IF NOT EXISTS ad_changes (
+    id BIGSERIAL PRIMARY KEY,
+    txid BIGINT NOT NULL DEFAULT txid_current(),
+    ad_id INTEGER NOT NULL,
+    deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    changed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_ad_changes_txid_id ON ad_changes(txid, id);
+
+-- Record every insert, update and delete of an ad; view count flushes are not changes
+CREATE OR REPLACE FUNCTION ads_record_change() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO ad_changes (ad_id, deleted) VALUES (OLD.id, TRUE);
+        RETURN OLD;
+    END IF;
+
+    IF TG_OP = 'UPDATE' AND to_jsonb(OLD) - 'view_count' = to_jsonb(NEW) - 'view_count' THEN
+        RETURN NEW;
+    END IF;
+
+    INSERT INTO ad_changes (ad_id) VALUES (NEW.id);
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS ads_record_change ON ads;
+CREATE TRIGGER ads_record_change
+    AFTER INSERT OR UPDATE OR DELETE ON ads
+    FOR EACH ROW EXECUTE FUNCTION ads_record_change();
+
+-- Start the journal with every existing ad so that new consumers get a full copy
+INSERT INTO ad_changes (ad_id) SELECT id FROM ads ORDER BY id;
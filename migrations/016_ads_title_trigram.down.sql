@@ -0,0 +1,5 @@
+-- Drop the title trigram indexes; the pg_trgm extension is left installed
+DROP INDEX IF EXISTS idx_ads_title_trgm_ru;
+DROP INDEX IF EXISTS idx_ads_title_trgm_en;
+DROP INDEX IF EXISTS idx_ads_title_trgm_tr;
+DROP FUNCTION IF EXISTS ads_title_text(JSONB, INTEGER);
@@ -0,0 +1,14 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Lowercased title of an ad in one language, or NULL when it has none
+CREATE OR REPLACE FUNCTION ads_title_text(title JSONB, lang INTEGER) RETURNS text AS $$
+    SELECT lower(item->>'text')
+    FROM jsonb_array_elements(CASE WHEN jsonb_typeof(title) = 'array' THEN title ELSE '[]'::jsonb END) AS item
+    WHERE (item->>'lang')::int = lang
+    LIMIT 1
+$$ LANGUAGE sql IMMUTABLE;
+
+-- Trigram indexes over the title in each language serve the prefix matches of search suggestions
+CREATE INDEX IF NOT EXISTS idx_ads_title_trgm_ru ON ads USING GIN (ads_title_text(title, 1) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_ads_title_trgm_en ON ads USING GIN (ads_title_text(title, 2) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_ads_title_trgm_tr ON ads USING GIN (ads_title_text(title, 3) gin_trgm_ops);
@@ -0,0 +1,16 @@
+-- Ad events written in the transaction of the ad change and relayed to the
+-- message broker. The id is the event sequence; events are relayed in id
+-- order and only from transactions older than every running one, so that an
+-- event is never relayed before an earlier one commits.
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    txid BIGINT NOT NULL DEFAULT txid_current(),
+    event_type VARCHAR(50) NOT NULL,
+    ad_id INTEGER NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_unsent ON outbox_events(id) WHERE sent_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_sent_at ON outbox_events(sent_at) WHERE sent_at IS NOT NULL;
@@ -0,0 +1,4 @@
+-- Time after which an ad is completed automatically; NULL never expires
+ALTER TABLE ads ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_ads_expires_at ON ads(expires_at) WHERE expires_at IS NOT NULL;
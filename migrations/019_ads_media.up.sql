@@ -0,0 +1,2 @@
+-- Ordered list of {url, type, width, height} attachments of the ad
+ALTER TABLE ads ADD COLUMN IF NOT EXISTS media JSONB NOT NULL DEFAULT '[]';
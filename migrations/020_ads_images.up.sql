@@ -0,0 +1,2 @@
+-- Ordered list of {key, url, width, height, is_primary} uploaded images of the ad
+ALTER TABLE ads ADD COLUMN IF NOT EXISTS images JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_ads_owner_id;
+ALTER TABLE ads DROP COLUMN IF EXISTS owner_id;
@@ -0,0 +1,4 @@
+-- User who created the ad; NULL for ads created anonymously
+ALTER TABLE ads ADD COLUMN IF NOT EXISTS owner_id VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_ads_owner_id ON ads(owner_id, created_at DESC) WHERE owner_id IS NOT NULL;
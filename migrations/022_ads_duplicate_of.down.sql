@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_ads_category_ids_created_at;
+DROP INDEX IF EXISTS idx_ads_duplicate_of;
+ALTER TABLE ads DROP COLUMN IF EXISTS duplicate_of;
@@ -0,0 +1,8 @@
+-- Ad a parser import was found to duplicate; NULL for ads not marked as duplicates
+ALTER TABLE ads ADD COLUMN IF NOT EXISTS duplicate_of INTEGER REFERENCES ads(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_ads_duplicate_of ON ads(duplicate_of) WHERE duplicate_of IS NOT NULL;
+
+-- Duplicate detection scans the recent ads of the imported ad's categories;
+-- btree_gin lets created_at share the GIN index with category_ids
+CREATE INDEX IF NOT EXISTS idx_ads_category_ids_created_at ON ads USING GIN(category_ids, created_at);
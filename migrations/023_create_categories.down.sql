@@ -0,0 +1,6 @@
+-- Keep the closure and drop categories table
+ALTER TABLE category_closure
+    DROP CONSTRAINT IF EXISTS category_closure_ancestor_id_fkey,
+    DROP CONSTRAINT IF EXISTS category_closure_descendant_id_fkey;
+DROP INDEX IF EXISTS idx_categories_parent_id;
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,27 @@
+-- Create categories table; the tree itself stays in category_closure
+CREATE TABLE IF NOT EXISTS categories (
+    id SERIAL PRIMARY KEY,
+    parent_id INTEGER REFERENCES categories(id),
+    name JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id);
+
+-- Categories were maintained outside this service until now: add a nameless
+-- record for each category in the closure, under its direct parent
+INSERT INTO categories (id, name)
+SELECT DISTINCT descendant_id, '[]'::jsonb FROM category_closure
+ON CONFLICT (id) DO NOTHING;
+
+UPDATE categories SET parent_id = category_closure.ancestor_id
+FROM category_closure
+WHERE category_closure.descendant_id = categories.id AND category_closure.depth = 1;
+
+SELECT setval(pg_get_serial_sequence('categories', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM categories;
+
+-- Closure rows go with their categories
+ALTER TABLE category_closure
+    ADD CONSTRAINT category_closure_ancestor_id_fkey FOREIGN KEY (ancestor_id) REFERENCES categories(id) ON DELETE CASCADE,
+    ADD CONSTRAINT category_closure_descendant_id_fkey FOREIGN KEY (descendant_id) REFERENCES categories(id) ON DELETE CASCADE;
@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL migration files so the binaries do not
+// depend on the working directory.
+package migrations
+
+import "embed"
+
+// FS holds the NNN_name.up.sql and NNN_name.down.sql migration files
+//
+//go:embed *.sql
+var FS embed.FS